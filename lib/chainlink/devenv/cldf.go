@@ -8,6 +8,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -19,6 +23,10 @@ import (
 	"github.com/smartcontractkit/chainlink-deployments-framework/operations"
 
 	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/jd"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+	"github.com/smartcontractkit/chainlink/devenv/products/ocr2"
 
 	chainsel "github.com/smartcontractkit/chain-selectors"
 
@@ -36,6 +44,16 @@ const (
 
 const LinkToken cldf.ContractType = "LinkToken"
 
+const (
+	// EnvVarJDGRPCURL overrides JD gRPC endpoint from [jd] output, ex.: to use an external JD instance
+	EnvVarJDGRPCURL = "JD_GRPC_URL"
+	// EnvVarJDWSRPCURL overrides JD WSRPC endpoint from [jd] output
+	EnvVarJDWSRPCURL = "JD_WSRPC_URL"
+)
+
+// JDReadyTimeout is how long LoadCLDFEnvironment waits for JD to respond, 0 skips the check
+var JDReadyTimeout = 2 * time.Minute
+
 type JobDistributor struct {
 	nodev1.NodeServiceClient
 	jobv1.JobServiceClient
@@ -49,16 +67,24 @@ type JDConfig struct {
 }
 
 // LoadCLDFEnvironment loads CLDF environment with a memory data store and JD client.
-func LoadCLDFEnvironment(in *Cfg) (cldf.Environment, error) {
-	ctx := context.Background()
-
+// Registrations add deployed contracts to the data store before it's sealed, ex.: RegisterDeployedFeeds.
+// ctx bounds connecting to JD, environment GetContext returns ctx without its cancellation, so a logger injected
+// with products.WithLogger reaches operations
+func LoadCLDFEnvironment(ctx context.Context, in *Cfg, registrations ...DataStoreRegistration) (cldf.Environment, error) {
+	envCtx := context.WithoutCancel(ctx)
 	getCtx := func() context.Context {
-		return ctx
+		return envCtx
 	}
 
 	// This only generates a brand new datastore and does not load any existing data.
 	// We will need to figure out how data will be persisted and loaded in the future.
-	ds := datastore.NewMemoryDataStore().Seal()
+	mds := datastore.NewMemoryDataStore()
+	for _, register := range registrations {
+		if err := register(mds); err != nil {
+			return cldf.Environment{}, fmt.Errorf("failed to register contracts in the datastore: %w", err)
+		}
+	}
+	ds := mds.Seal()
 
 	lggr, err := logger.NewWith(func(config *zap.Config) {
 		config.Development = true
@@ -73,15 +99,22 @@ func LoadCLDFEnvironment(in *Cfg) (cldf.Environment, error) {
 		return cldf.Environment{}, fmt.Errorf("failed to load CLDF chains: %w", err)
 	}
 
-	jd, err := NewJDClient(ctx, JDConfig{
-		GRPC:  in.JD.Out.ExternalGRPCUrl,
-		WSRPC: in.JD.Out.ExternalWSRPCUrl,
-	})
+	jdCfg, err := NewJDConfig(in.JD)
+	if err != nil {
+		return cldf.Environment{}, err
+	}
+	jd, err := NewJDClient(ctx, jdCfg)
 	if err != nil {
 		return cldf.Environment{},
 			fmt.Errorf("failed to load offchain client: %w", err)
 	}
+	if JDReadyTimeout > 0 {
+		if err := WaitForJD(ctx, jd, JDReadyTimeout); err != nil {
+			return cldf.Environment{}, err
+		}
+	}
 
+	ctxLogger(ctx).Info().Int("Chains", len(blockchains)).Int("Registrations", len(registrations)).Msg("Loaded CLDF environment")
 	opBundle := operations.NewBundle(
 		getCtx,
 		lggr,
@@ -124,6 +157,9 @@ func loadEVMChain(bci *blockchain.Input) (cldfchain.BlockChain, error) {
 	if bci.Out == nil {
 		return nil, fmt.Errorf("output configuration for %s blockchain %s is not set", bci.Type, bci.ChainID)
 	}
+	if len(bci.Out.Nodes) == 0 {
+		return nil, fmt.Errorf("output configuration for %s blockchain %s has no nodes", bci.Type, bci.ChainID)
+	}
 
 	chainDetails, err := chainsel.GetChainDetailsByChainIDAndFamily(bci.ChainID, chainsel.FamilyEVM)
 	if err != nil {
@@ -154,6 +190,46 @@ func loadEVMChain(bci *blockchain.Input) (cldfchain.BlockChain, error) {
 	return chain, nil
 }
 
+// NewJDConfig returns JD endpoints from [jd] output, JD_GRPC_URL and JD_WSRPC_URL override them if set
+func NewJDConfig(in *jd.Input) (JDConfig, error) {
+	var cfg JDConfig
+	if in != nil && in.Out != nil {
+		cfg = JDConfig{GRPC: in.Out.ExternalGRPCUrl, WSRPC: in.Out.ExternalWSRPCUrl}
+	}
+	cfg.GRPC = defaults.EnvOr(EnvVarJDGRPCURL, cfg.GRPC)
+	cfg.WSRPC = defaults.EnvOr(EnvVarJDWSRPCURL, cfg.WSRPC)
+	if cfg.GRPC == "" {
+		return cfg, fmt.Errorf("no JD gRPC endpoint, bring up [jd] or set %s", EnvVarJDGRPCURL)
+	}
+	if err := validateEndpoint(cfg.GRPC); err != nil {
+		return cfg, fmt.Errorf("invalid JD gRPC endpoint: %w", err)
+	}
+	if cfg.WSRPC != "" {
+		if err := validateEndpoint(cfg.WSRPC); err != nil {
+			return cfg, fmt.Errorf("invalid JD WSRPC endpoint: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// validateEndpoint checks endpoint is either a URL with a host, ex.: ws://localhost:8080, or host:port, ex.: localhost:14231
+func validateEndpoint(endpoint string) error {
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("could not parse %q: %w", endpoint, err)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("%q has no host", endpoint)
+		}
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		return fmt.Errorf("%q is neither a URL nor host:port: %w", endpoint, err)
+	}
+	return nil
+}
+
 // NewJDClient creates a new JobDistributor client.
 func NewJDClient(ctx context.Context, cfg JDConfig) (cldf.OffchainClient, error) {
 	conn, err := NewJDConnection(cfg)
@@ -170,6 +246,22 @@ func NewJDClient(ctx context.Context, cfg JDConfig) (cldf.OffchainClient, error)
 	return jd, err
 }
 
+// WaitForJD polls JD with a lightweight ListKeypairs call until it responds, gRPC client connects lazily
+// so without it the first real call fails if JD container is still initializing
+func WaitForJD(ctx context.Context, jd csav1.CSAServiceClient, timeout time.Duration) error {
+	w := ocr2.WaitConfig{PollIntervalMs: 1000, TimeoutSec: int64(math.Ceil(timeout.Seconds()))}
+	err := w.Poll(ctx, func(ctx context.Context) (bool, error) {
+		if _, err := jd.ListKeypairs(ctx, &csav1.ListKeypairsRequest{}); err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("job distributor is not ready after %s: %w", timeout, err)
+	}
+	return nil
+}
+
 func (jd JobDistributor) GetCSAPublicKey(ctx context.Context) (string, error) {
 	keypairs, err := jd.ListKeypairs(ctx, &csav1.ListKeypairsRequest{})
 	if err != nil {
@@ -0,0 +1,67 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/jd"
+
+	csav1 "github.com/smartcontractkit/chainlink-protos/job-distributor/v1/csa"
+)
+
+// startingJD fails ListKeypairs until it's called ready times
+type startingJD struct {
+	csav1.CSAServiceClient
+	calls int
+	ready int
+}
+
+func (s *startingJD) ListKeypairs(context.Context, *csav1.ListKeypairsRequest, ...grpc.CallOption) (*csav1.ListKeypairsResponse, error) {
+	s.calls++
+	if s.calls < s.ready {
+		return nil, errors.New("connection refused")
+	}
+	return &csav1.ListKeypairsResponse{}, nil
+}
+
+func TestWaitForJD(t *testing.T) {
+	jd := &startingJD{ready: 2}
+	require.NoError(t, WaitForJD(context.Background(), jd, 10*time.Second))
+	require.Equal(t, 2, jd.calls)
+
+	err := WaitForJD(context.Background(), &startingJD{ready: 100}, time.Second)
+	require.ErrorContains(t, err, "job distributor is not ready after 1s")
+	require.ErrorContains(t, err, "connection refused")
+}
+
+func TestNewJDConfig(t *testing.T) {
+	in := &jd.Input{Out: &jd.Output{ExternalGRPCUrl: "127.0.0.1:14231", ExternalWSRPCUrl: "127.0.0.1:8080"}}
+
+	cfg, err := NewJDConfig(in)
+	require.NoError(t, err)
+	require.Equal(t, JDConfig{GRPC: "127.0.0.1:14231", WSRPC: "127.0.0.1:8080"}, cfg)
+
+	t.Setenv(EnvVarJDGRPCURL, "jd.shared.example:443")
+	t.Setenv(EnvVarJDWSRPCURL, "wss://jd.shared.example/ws")
+	cfg, err = NewJDConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, JDConfig{GRPC: "jd.shared.example:443", WSRPC: "wss://jd.shared.example/ws"}, cfg)
+
+	t.Setenv(EnvVarJDGRPCURL, "not an endpoint")
+	_, err = NewJDConfig(in)
+	require.ErrorContains(t, err, "invalid JD gRPC endpoint")
+
+	t.Setenv(EnvVarJDGRPCURL, "")
+	t.Setenv(EnvVarJDWSRPCURL, "ws://")
+	_, err = NewJDConfig(in)
+	require.ErrorContains(t, err, "invalid JD WSRPC endpoint")
+
+	t.Setenv(EnvVarJDWSRPCURL, "")
+	_, err = NewJDConfig(&jd.Input{})
+	require.ErrorContains(t, err, "no JD gRPC endpoint")
+}
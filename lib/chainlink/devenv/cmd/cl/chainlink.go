@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -13,7 +17,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/smartcontractkit/chainlink-testing-framework/framework"
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
 	de "github.com/smartcontractkit/chainlink/devenv"
+	"github.com/smartcontractkit/chainlink/devenv/products"
 	"github.com/smartcontractkit/chainlink/devenv/products/ocr2"
 )
 
@@ -34,6 +40,28 @@ var rootCmd = &cobra.Command{
 			framework.L.Info().Msg("Debug mode enabled, setting CTF_CLNODE_DLV=true")
 			os.Setenv("CTF_CLNODE_DLV", "true")
 		}
+		archive, err := cmd.Flags().GetBool("archive-outputs")
+		if err != nil {
+			return err
+		}
+		if archive {
+			framework.L.Info().Msgf("Archiving outputs enabled, setting %s=true", de.EnvVarArchiveOutputs)
+			os.Setenv(de.EnvVarArchiveOutputs, "true")
+		}
+		artifactsDir, err := cmd.Flags().GetString("artifacts-dir")
+		if err != nil {
+			return err
+		}
+		if artifactsDir != "" {
+			os.Setenv(products.EnvVarArtifactsDir, artifactsDir)
+		}
+		runID, err := cmd.Flags().GetString("run-id")
+		if err != nil {
+			return err
+		}
+		if runID != "" {
+			os.Setenv(products.EnvVarRunID, runID)
+		}
 		return nil
 	},
 }
@@ -54,13 +82,11 @@ var restartCmd = &cobra.Command{
 		_ = os.Setenv("CTF_CONFIGS", configFile)
 		_ = os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
 		framework.L.Info().Msg("Tearing down the development environment")
-		err := framework.RemoveTestContainers()
-		if err != nil {
-			return fmt.Errorf("failed to clean Docker resources: %w", err)
+		if err := de.DestroyEnvironment(context.Background()); err != nil {
+			return fmt.Errorf("failed to tear down the environment: %w", err)
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-		defer cancel()
-		return de.NewEnvironment(ctx)
+		// bring-up is bounded by CTF_UP_TIMEOUT, 15m by default
+		return de.NewEnvironment(context.Background())
 	},
 }
 
@@ -70,30 +96,67 @@ var upCmd = &cobra.Command{
 	Short:   "Spin up the development environment",
 	Args:    cobra.RangeArgs(0, 1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		useDefaults, err := cmd.Flags().GetBool("defaults")
+		if err != nil {
+			return err
+		}
 		var configFile string
-		if len(args) > 0 {
+		switch {
+		case len(args) > 0:
 			configFile = args[0]
-		} else {
+		case useDefaults:
+			// built-in defaults are written to env-default.toml when the environment loads its config
+			_ = os.Setenv(de.EnvVarUseDefaults, "true")
+		default:
 			configFile = "env.toml"
 		}
 		framework.L.Info().Str("Config", configFile).Msg("Creating development environment")
 		_ = os.Setenv("CTF_CONFIGS", configFile)
 		_ = os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-		defer cancel()
-		return de.NewEnvironment(ctx)
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			return err
+		}
+		if timeout > 0 {
+			_ = os.Setenv(de.EnvVarUpTimeout, timeout.String())
+		}
+		reuseNodes, err := cmd.Flags().GetBool("reuse-nodes")
+		if err != nil {
+			return err
+		}
+		if reuseNodes {
+			_ = os.Setenv(de.EnvVarReuseNodeSets, "true")
+		}
+		progressJSON, err := cmd.Flags().GetBool("progress-json")
+		if err != nil {
+			return err
+		}
+		var opts de.UpOptions
+		if progressJSON {
+			opts.OnProgress = jsonProgress(cmd.OutOrStdout())
+		}
+		return de.NewEnvironment(context.Background(), opts)
 	},
 }
 
+// jsonProgress writes every bring-up progress event as a JSON line
+func jsonProgress(w io.Writer) func(de.Event) {
+	enc := json.NewEncoder(w)
+	return func(e de.Event) {
+		if err := enc.Encode(e); err != nil {
+			framework.L.Warn().Err(err).Msg("Could not write progress event")
+		}
+	}
+}
+
 var downCmd = &cobra.Command{
 	Use:     "down",
 	Aliases: []string{"d"},
 	Short:   "Tear down the development environment",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		framework.L.Info().Msg("Tearing down the development environment")
-		err := framework.RemoveTestContainers()
-		if err != nil {
-			return fmt.Errorf("failed to clean Docker resources: %w", err)
+		if err := de.DestroyEnvironment(context.Background()); err != nil {
+			return fmt.Errorf("failed to tear down the environment: %w", err)
 		}
 		return nil
 	},
@@ -200,6 +263,215 @@ var obsRestartCmd = &cobra.Command{
 	},
 }
 
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Manage jobs on running CL nodes",
+}
+
+var jobsAddCmd = &cobra.Command{
+	Use:   "add <node_idx> <spec_file>",
+	Short: "Create a job from TOML spec file on a single node",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idx, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid node index %s: %w", args[0], err)
+		}
+		spec, err := os.ReadFile(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to read job spec file: %w", err)
+		}
+		cl, err := connectCLNodes()
+		if err != nil {
+			return err
+		}
+		jobID, err := ocr2.CreateJobOnNode(cmd.Context(), cl, idx, ocr2.RawJobSpec(spec))
+		if err != nil {
+			return err
+		}
+		framework.L.Info().Int("Node", idx).Str("JobID", jobID).Msg("Job created")
+		return nil
+	},
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:     "list <node_idx>",
+	Aliases: []string{"ls"},
+	Short:   "List jobs on a single node",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		node, err := connectCLNode(args[0])
+		if err != nil {
+			return err
+		}
+		jobs, err := ocr2.ListJobs(cmd.Context(), node)
+		if err != nil {
+			return err
+		}
+		for _, j := range jobs {
+			framework.L.Info().Str("JobID", j.ID).Str("Type", j.Type).Str("Name", j.Name).Msg("Job")
+		}
+		return nil
+	},
+}
+
+var jobsDeleteCmd = &cobra.Command{
+	Use:     "rm <node_idx> <job_id>",
+	Aliases: []string{"delete"},
+	Short:   "Delete a job from a single node",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		node, err := connectCLNode(args[0])
+		if err != nil {
+			return err
+		}
+		return ocr2.DeleteJob(cmd.Context(), node, args[1])
+	},
+}
+
+var jobsRenderCmd = &cobra.Command{
+	Use:   "render [config]",
+	Short: "Render job specs from config to files without a running environment, node keys are placeholders",
+	Args:  cobra.RangeArgs(0, 1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configFile := "env.toml"
+		if len(args) > 0 {
+			configFile = args[0]
+		}
+		outDir, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return err
+		}
+		if !cmd.Flags().Changed("out") {
+			outDir = products.ArtifactPath(outDir)
+		}
+		aggregator, err := cmd.Flags().GetString("aggregator")
+		if err != nil {
+			return err
+		}
+		transmitters, err := cmd.Flags().GetStringSlice("transmitters")
+		if err != nil {
+			return err
+		}
+		_ = os.Setenv("CTF_CONFIGS", configFile)
+		in, err := de.Load[de.Cfg]()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		c := ocr2.NewOCR2Configurator()
+		if err := c.Load(); err != nil {
+			return err
+		}
+		specs, err := ocr2.GenerateJobSpecs(c, aggregator, transmitters, in.Blockchains[0].ChainID)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		for name, spec := range specs {
+			path := filepath.Join(outDir, name)
+			if err := os.WriteFile(path, []byte(spec), 0o600); err != nil {
+				return fmt.Errorf("failed to write job spec %s: %w", path, err)
+			}
+			framework.L.Info().Str("File", path).Msg("Job spec rendered")
+		}
+		return nil
+	},
+}
+
+// connectCLNodes connects to all nodes of the first node set from environment output
+func connectCLNodes() ([]*clclient.ChainlinkClient, error) {
+	in, err := de.LoadOutput[de.Cfg](products.DefaultOutputPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load environment output: %w", err)
+	}
+	cl, err := clclient.New(in.NodeSets[0].Out.CLNodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to CL nodes: %w", err)
+	}
+	return cl, nil
+}
+
+// connectCLNode connects to a node by its index argument
+func connectCLNode(arg string) (*clclient.ChainlinkClient, error) {
+	idx, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node index %s: %w", arg, err)
+	}
+	cl, err := connectCLNodes()
+	if err != nil {
+		return nil, err
+	}
+	if idx < 0 || idx >= len(cl) {
+		return nil, fmt.Errorf("node index %d is out of range, there are %d nodes", idx, len(cl))
+	}
+	return cl[idx], nil
+}
+
+var fakeCmd = &cobra.Command{
+	Use:   "fake",
+	Short: "Manage the fake data provider (EA)",
+}
+
+var fakeRestartCmd = &cobra.Command{
+	Use:     "restart [fake_idx]",
+	Aliases: []string{"r"},
+	Short:   "Recreate only the fake data provider container, chains and nodes keep running",
+	Args:    cobra.RangeArgs(0, 1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idx := 0
+		if len(args) > 0 {
+			var err error
+			idx, err = strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid fake server index %s: %w", args[0], err)
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		return de.RestartFakeServer(ctx, idx)
+	},
+}
+
+var scaleCmd = &cobra.Command{
+	Use:   "scale <nodeset> <count>",
+	Short: "Scale running node set up or down and reconfigure the DON",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid node count %s: %w", args[1], err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		return de.ScaleNodeSet(ctx, args[0], count)
+	},
+}
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Compute OCR2 config digest from env-out.toml offline and compare it with the stored on-chain digest",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return de.CheckConfigDigest()
+	},
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <a.toml> <b.toml>",
+	Short: "Compare two stored environment outputs, ex.: env-out.toml of a passing and a failing run",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report, err := de.DiffOutputs(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Print(report)
+		return nil
+	},
+}
+
 var testCmd = &cobra.Command{
 	Use:     "test",
 	Aliases: []string{"t"},
@@ -220,8 +492,24 @@ var testCmd = &cobra.Command{
 			return fmt.Errorf("test suite %s is unknown, choose between smoke or load", args[0])
 		}
 
+		overrides, err := setConfigOverrides(cmd)
+		if err != nil {
+			return err
+		}
+
 		testCmd := exec.Command("go", "test", "-v", "-run", testPattern, "./...")
 		testCmd.Dir = "./tests"
+		testCmd.Env = os.Environ()
+		if overrides != nil {
+			testCmd.Env = append(testCmd.Env, fmt.Sprintf("%s=%s", ocr2.EnvVarSetConfigOverrides, overrides))
+		}
+		exportMetrics, err := cmd.Flags().GetBool("export-metrics")
+		if err != nil {
+			return err
+		}
+		if exportMetrics {
+			testCmd.Env = append(testCmd.Env, ocr2.EnvVarExportMetrics+"=true")
+		}
 		testCmd.Stdout = os.Stdout
 		testCmd.Stderr = os.Stderr
 		testCmd.Stdin = os.Stdin
@@ -240,11 +528,76 @@ var testCmd = &cobra.Command{
 	},
 }
 
+// setConfigOverrides encodes OCR2 set config flags changed on the command line, nil means there are no overrides
+func setConfigOverrides(cmd *cobra.Command) ([]byte, error) {
+	flags := cmd.Flags()
+	s := &ocr2.SetConfigOverrides{}
+	changed := false
+	if flags.Changed("rmax") {
+		rmax, err := flags.GetUint8("rmax")
+		if err != nil {
+			return nil, err
+		}
+		s.RMax = &rmax
+		changed = true
+	}
+	for name, field := range map[string]**time.Duration{
+		"delta-progress": &s.DeltaProgress,
+		"delta-resend":   &s.DeltaResend,
+		"delta-round":    &s.DeltaRound,
+		"delta-grace":    &s.DeltaGrace,
+		"delta-stage":    &s.DeltaStage,
+	} {
+		if !flags.Changed(name) {
+			continue
+		}
+		d, err := flags.GetDuration(name)
+		if err != nil {
+			return nil, err
+		}
+		*field = &d
+		changed = true
+	}
+	if !changed {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
 func init() {
 	rootCmd.PersistentFlags().BoolP("debug", "d", false, "Enable running services with dlv to allow remote debugging.")
+	rootCmd.PersistentFlags().Bool("archive-outputs", false, "Keep a timestamped copy of the previous env-out.toml before overriding it.")
+	rootCmd.PersistentFlags().String("artifacts-dir", "", "Root directory outputs, job specs and logs are written to, current directory if not set.")
+	rootCmd.PersistentFlags().String("run-id", "", "Per-run subdirectory of --artifacts-dir, \"default\" if not set.")
+	upCmd.Flags().Bool("defaults", false, "Use built-in single chain, single node set defaults if no config is passed, they're written to env-default.toml.")
+	upCmd.Flags().Duration("timeout", 0, "Fail if the environment is not up in time, ex.: 30m, 15m if not set, it's CTF_UP_TIMEOUT")
+	upCmd.Flags().Bool("progress-json", false, "Write bring-up progress events as JSON lines to stdout, ex.: for CI dashboards")
+	upCmd.Flags().Bool("reuse-nodes", false, "Keep running chain, fakes and node sets from the previous output if they respond, only jobs and contracts are configured again")
 
+	// OCR2 set config overrides, layered over set config options of test cases that apply a new config
+	testCmd.Flags().Uint8("rmax", 0, "Override OCR2 RMax")
+	testCmd.Flags().Duration("delta-progress", 0, "Override OCR2 DeltaProgress, ex.: 30s")
+	testCmd.Flags().Duration("delta-resend", 0, "Override OCR2 DeltaResend, ex.: 30s")
+	testCmd.Flags().Duration("delta-round", 0, "Override OCR2 DeltaRound, ex.: 10s")
+	testCmd.Flags().Duration("delta-grace", 0, "Override OCR2 DeltaGrace, ex.: 5s")
+	testCmd.Flags().Duration("delta-stage", 0, "Override OCR2 DeltaStage, ex.: 15s")
+	testCmd.Flags().Bool("export-metrics", false, "Save raw Prometheus query results of resource and transmission checks as JSON artifacts")
 	rootCmd.AddCommand(testCmd)
 
+	// jobs
+	jobsCmd.AddCommand(jobsAddCmd)
+	jobsCmd.AddCommand(jobsListCmd)
+	jobsCmd.AddCommand(jobsDeleteCmd)
+	jobsRenderCmd.Flags().StringP("out", "o", "job-specs", "Directory rendered job specs are written to")
+	jobsRenderCmd.Flags().String("aggregator", "", "OCR2 aggregator address, placeholder is used if not set")
+	jobsRenderCmd.Flags().StringSlice("transmitters", nil, "Comma separated worker transmitter addresses, one worker spec is rendered per address")
+	jobsCmd.AddCommand(jobsRenderCmd)
+	rootCmd.AddCommand(jobsCmd)
+
+	// fake data provider
+	fakeCmd.AddCommand(fakeRestartCmd)
+	rootCmd.AddCommand(fakeCmd)
+
 	// Blockscout, on-chain debug
 	bsCmd.PersistentFlags().StringP("url", "u", "http://host.docker.internal:8555", "EVM RPC node URL (default to dst chain on 8555")
 	bsCmd.PersistentFlags().StringP("chain-id", "c", "2337", "RPC's Chain ID")
@@ -261,6 +614,9 @@ func init() {
 	rootCmd.AddCommand(obsCmd)
 
 	// main env commands
+	rootCmd.AddCommand(scaleCmd)
+	rootCmd.AddCommand(digestCmd)
+	rootCmd.AddCommand(diffCmd)
 	rootCmd.AddCommand(upCmd)
 	rootCmd.AddCommand(restartCmd)
 	rootCmd.AddCommand(downCmd)
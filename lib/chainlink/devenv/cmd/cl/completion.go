@@ -17,6 +17,11 @@ func getCommands() []prompt.Suggest {
 		{Text: "down", Description: "Tear down the development environment"},
 		{Text: "restart", Description: "Restart the development environment"},
 		{Text: "test", Description: "Perform smoke or load/chaos testing"},
+		{Text: "jobs", Description: "Manage jobs on running CL nodes"},
+		{Text: "fake", Description: "Manage the fake data provider (EA)"},
+		{Text: "scale", Description: "Scale running node set up or down: scale <nodeset> <count>"},
+		{Text: "digest", Description: "Check OCR2 config digest of env-out.toml offline"},
+		{Text: "diff", Description: "Compare two stored environment outputs: diff <a.toml> <b.toml>"},
 		{Text: "bs", Description: "Manage the Blockscout EVM block explorer"},
 		{Text: "obs", Description: "Manage the observability stack"},
 		{Text: "db", Description: "Inspect Databases"},
@@ -32,13 +37,23 @@ func getSubCommands(parent string) []prompt.Suggest {
 			{Text: "gas", Description: "Run OCR2 load test + simulate gas spikes"},
 			{Text: "chaos", Description: "Run OCR2 load test + introduce container kills and latency"},
 		}
+	case "jobs":
+		return []prompt.Suggest{
+			{Text: "add", Description: "Create a job from TOML spec file on a single node: jobs add <node_idx> <spec_file>"},
+			{Text: "list", Description: "List jobs on a single node: jobs list <node_idx>"},
+			{Text: "rm", Description: "Delete a job from a single node: jobs rm <node_idx> <job_id>"},
+		}
+	case "fake":
+		return []prompt.Suggest{
+			{Text: "restart", Description: "Recreate fake data provider container, use FAKE_SERVER_IMAGE to change the image: fake restart [fake_idx]"},
+		}
 	case "bs":
 		return []prompt.Suggest{
 			{Text: "up", Description: "Spin up Blockscout and listen to dst chain (8555)"},
-			{Text: "up -u http://host.docker.internal:8545 -c 1337", Description: "Spin up Blockscout and listen to src chain (8545)"},
+			{Text: "up -u http://host.docker.internal:8545 -c ", Description: "Spin up Blockscout and listen to src chain (8545), set -c to its chain_id"},
 			{Text: "down", Description: "Remove Blockscout stack"},
 			{Text: "restart", Description: "Restart Blockscout and listen to dst chain (8555)"},
-			{Text: "restart -u http://host.docker.internal:8545 -c 1337", Description: "Restart Blockscout and listen to src chain (8545)"},
+			{Text: "restart -u http://host.docker.internal:8545 -c ", Description: "Restart Blockscout and listen to src chain (8545), set -c to its chain_id"},
 		}
 	case "obs":
 		return []prompt.Suggest{
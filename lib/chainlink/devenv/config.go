@@ -8,18 +8,39 @@ To configure the environment we use a set of files we read from the env var CTF_
 To store infra or product component outputs we use Store[T] that creates env-cache.toml file.
 This file can be used in tests or in any other code that integrated with dev environment.
 LoadCache[T] is used if you need to write outputs the second time.
+
+CTF_CONFIGS may contain a single "-" entry, in that case TOML is read from stdin and merged in its listed position,
+ex.: generate-config | CTF_CONFIGS=env.toml,- cl up
+When the base (first) config is "-" outputs are written to env-out.toml.
+
+Set CTF_ARCHIVE_OUTPUTS=true (or cl --archive-outputs) to keep a timestamped copy of the previous output,
+ex.: env-out.20250101T120000Z.toml, before it's overridden by Store[T].
+
+Set CTF_STRICT_CONFIGS=true to report unknown keys, ex.: typos in [ocr2] or [[nodesets]], as errors.
+
+Load[T], Store[T] and LoadOutput[T] accept an optional products.ConfigStore to read and write configs somewhere
+other than local filesystem, ex.: products.NewMemoryStore() in tests.
+
+LoadOrDefault[T] works as Load[T], but if CTF_CONFIGS is empty and CTF_USE_DEFAULTS=true (or cl up --defaults) it writes
+built-in defaults to env-default.toml and loads them, so a demo environment comes up without any TOML.
 */
 
 import (
+	"context"
+	_ "embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+
+	"github.com/smartcontractkit/chainlink/devenv/products"
 )
 
 const (
@@ -27,22 +48,51 @@ const (
 	DefaultConfigDir = "."
 	// EnvVarTestConfigs is the environment variable name to read config paths from, ex.: CTF_CONFIGS=env.toml,overrides.toml.
 	EnvVarTestConfigs = "CTF_CONFIGS"
+	// EnvVarArchiveOutputs enables archiving of the previous output file before it's overridden, ex.: CTF_ARCHIVE_OUTPUTS=true.
+	EnvVarArchiveOutputs = "CTF_ARCHIVE_OUTPUTS"
+	// ArchiveTimeFormat is the timestamp format used in archived output file names.
+	ArchiveTimeFormat = "20060102T150405Z"
 	// DefaultOverridesFilePath is the default overrides.toml file path.
 	DefaultOverridesFilePath = "overrides.toml"
 	// DefaultAnvilKey is a default, well-known Anvil first key
 	DefaultAnvilKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	// EnvVarUseDefaults makes LoadOrDefault use built-in defaults when CTF_CONFIGS is empty, ex.: CTF_USE_DEFAULTS=true.
+	EnvVarUseDefaults = "CTF_USE_DEFAULTS"
+	// DefaultEnvConfigPath is where built-in defaults are written, outputs go to env-default-out.toml.
+	DefaultEnvConfigPath = "env-default.toml"
 )
 
+// DefaultEnvConfig is a minimal single chain, single node set and single fake configuration used if no configs are provided
+//
+//go:embed default-env.toml
+var DefaultEnvConfig []byte
+
 var L = log.Output(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.InfoLevel)
 
+// ctxLogger returns the logger injected with products.WithLogger or UpOptions, L if there is none
+func ctxLogger(ctx context.Context) *zerolog.Logger {
+	return products.Logger(ctx, &L)
+}
+
 // Load loads TOML configurations from environment variable, ex.: CTF_CONFIGS=env.toml,overrides.toml
 // and unmarshalls the files from left to right overriding keys.
-func Load[T any]() (*T, error) {
+// A single "-" entry reads TOML from stdin, ex.: CTF_CONFIGS=env.toml,-
+// Configs are read from the optional store, filesystem is used by default.
+func Load[T any](store ...products.ConfigStore) (*T, error) {
 	var config T
-	paths := strings.Split(os.Getenv(EnvVarTestConfigs), ",")
+	s := products.SelectStore(DefaultConfigDir, store)
+	paths, err := products.ConfigPaths(os.Getenv(EnvVarTestConfigs))
+	if err != nil {
+		return nil, err
+	}
 	for _, path := range paths {
 		L.Info().Str("Path", path).Msg("Loading configuration input")
-		data, err := os.ReadFile(filepath.Join(DefaultConfigDir, path))
+		var data []byte
+		if path == products.StdinConfigPath {
+			data, err = products.ReadStdin()
+		} else {
+			data, err = s.Read(path)
+		}
 		if err != nil {
 			if path == DefaultOverridesFilePath {
 				L.Info().Str("Path", path).Msg("Overrides file not found or empty")
@@ -54,10 +104,8 @@ func Load[T any]() (*T, error) {
 			fmt.Println(string(data))
 		}
 
-		decoder := toml.NewDecoder(strings.NewReader(string(data)))
-
-		if err := decoder.Decode(&config); err != nil {
-			return nil, fmt.Errorf("failed to decode TOML config, strict mode: %w", err)
+		if err := products.DecodeTOML(path, data, &config); err != nil {
+			return nil, err
 		}
 	}
 	if L.GetLevel() == zerolog.TraceLevel {
@@ -67,32 +115,97 @@ func Load[T any]() (*T, error) {
 	return &config, nil
 }
 
-// Store writes config to a file, adds -cache.toml suffix if it's an initial configuration.
-func Store[T any](cfg *T) error {
-	baseConfigPath, err := BaseConfigPath()
+// LoadOrDefault loads configs as Load does, if CTF_CONFIGS is empty and CTF_USE_DEFAULTS=true built-in defaults are
+// written to env-default.toml and CTF_CONFIGS points to it, so product configs and outputs use the same file.
+// Without CTF_USE_DEFAULTS a missing config is an error.
+func LoadOrDefault[T any](store ...products.ConfigStore) (*T, error) {
+	if os.Getenv(EnvVarTestConfigs) == "" && os.Getenv(EnvVarUseDefaults) == "true" {
+		if err := UseDefaultConfig(store...); err != nil {
+			return nil, err
+		}
+	}
+	if os.Getenv(EnvVarTestConfigs) == "" {
+		return nil, fmt.Errorf("no %s env var is provided, provide at least one config in TOML or set %s=true to use built-in defaults", EnvVarTestConfigs, EnvVarUseDefaults)
+	}
+	return Load[T](store...)
+}
+
+// UseDefaultConfig writes built-in defaults to env-default.toml and sets CTF_CONFIGS to it
+func UseDefaultConfig(store ...products.ConfigStore) error {
+	s := products.SelectStore(DefaultConfigDir, store)
+	if err := s.Write(DefaultEnvConfigPath, DefaultEnvConfig); err != nil {
+		return fmt.Errorf("could not write default config: %w", err)
+	}
+	// logged values are read from the defaults, so the log doesn't drift when they change
+	var in Cfg
+	if err := toml.Unmarshal(DefaultEnvConfig, &in); err != nil {
+		return fmt.Errorf("could not decode default config: %w", err)
+	}
+	chains := make([]string, 0, len(in.Blockchains))
+	for _, bc := range in.Blockchains {
+		chains = append(chains, bc.Type+" "+bc.ChainID)
+	}
+	nodes := 0
+	for _, nodeSet := range in.NodeSets {
+		nodes += nodeSet.Nodes
+	}
+	L.Warn().
+		Str("Path", DefaultEnvConfigPath).
+		Strs("Blockchains", chains).
+		Int("Nodes", nodes).
+		Int("Fakes", len(in.Fakes())).
+		Msg("No configs provided, using built-in defaults, edit the file and pass it to `cl up` to customize")
+	return os.Setenv(EnvVarTestConfigs, DefaultEnvConfigPath)
+}
+
+// Store writes config to a file, adds -out.toml suffix if it's an initial configuration.
+// Output is written to the optional store, filesystem is used by default.
+func Store[T any](cfg *T, store ...products.ConfigStore) error {
+	outCacheName, err := OutputPath()
 	if err != nil {
 		return err
 	}
-	newCacheName := strings.ReplaceAll(baseConfigPath, ".toml", "")
-	var outCacheName string
-	if strings.Contains(newCacheName, "cache") {
-		L.Info().Str("Cache", baseConfigPath).Msg("Cache file already exists, overriding")
-		outCacheName = baseConfigPath
-	} else {
-		outCacheName = strings.ReplaceAll(baseConfigPath, ".toml", "") + "-out.toml"
+	if strings.Contains(outCacheName, "cache") {
+		L.Info().Str("Cache", outCacheName).Msg("Cache file already exists, overriding")
 	}
 	L.Info().Str("OutputFile", outCacheName).Msg("Storing configuration output")
 	d, err := toml.Marshal(cfg)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(DefaultConfigDir, outCacheName), d, 0600)
+	s := products.SelectStore(DefaultConfigDir, store)
+	if os.Getenv(EnvVarArchiveOutputs) == "true" {
+		if err := archiveOutput(s, outCacheName); err != nil {
+			return fmt.Errorf("failed to archive previous output: %w", err)
+		}
+	}
+	return s.Write(outCacheName, d)
+}
+
+// OutputPath returns the file Store writes outputs to for the current CTF_CONFIGS, ex.: env.toml -> env-out.toml,
+// it's placed in the run directory if CTF_ARTIFACTS_DIR is set, see products.ArtifactPath
+func OutputPath() (string, error) {
+	return products.OutputPath()
+}
+
+// archiveOutput copies existing output to a timestamped file, ex.: env-out.toml -> env-out.20250101T120000Z.toml.
+func archiveOutput(s products.ConfigStore, name string) error {
+	data, err := s.Read(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	archiveName := fmt.Sprintf("%s.%s.toml", strings.TrimSuffix(name, ".toml"), time.Now().UTC().Format(ArchiveTimeFormat))
+	L.Info().Str("From", name).Str("To", archiveName).Msg("Archiving previous output")
+	return s.Write(archiveName, data)
 }
 
 // LoadOutput loads config output file from path.
-func LoadOutput[T any](path string) (*T, error) {
+func LoadOutput[T any](path string, store ...products.ConfigStore) (*T, error) {
 	_ = os.Setenv(EnvVarTestConfigs, path)
-	return Load[T]()
+	return Load[T](store...)
 }
 
 // BaseConfigPath returns base config path, ex. env.toml,overrides.toml -> env.toml.
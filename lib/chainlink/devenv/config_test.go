@@ -0,0 +1,39 @@
+package devenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/devenv/products"
+)
+
+func TestLoadOrDefault(t *testing.T) {
+	store := products.NewMemoryStore()
+	t.Setenv(EnvVarTestConfigs, "")
+	t.Setenv(EnvVarUseDefaults, "")
+	_, err := LoadOrDefault[Cfg](store)
+	require.ErrorContains(t, err, "set CTF_USE_DEFAULTS=true to use built-in defaults")
+
+	t.Setenv(EnvVarUseDefaults, "true")
+	in, err := LoadOrDefault[Cfg](store)
+	require.NoError(t, err)
+	require.Equal(t, DefaultEnvConfigPath, os.Getenv(EnvVarTestConfigs))
+	require.Equal(t, "ocr2", in.ProductType)
+	require.Len(t, in.Blockchains, 1)
+	require.Equal(t, "1337", in.Blockchains[0].ChainID)
+	require.Len(t, in.NodeSets, 1)
+	require.Equal(t, 4, in.NodeSets[0].Nodes)
+	require.Len(t, in.Fakes(), 1)
+	data, err := store.Read(DefaultEnvConfigPath)
+	require.NoError(t, err)
+	require.Equal(t, DefaultEnvConfig, data)
+
+	// explicit configs always win over defaults
+	require.NoError(t, store.Write("env.toml", []byte(`product_type = "custom"`)))
+	t.Setenv(EnvVarTestConfigs, "env.toml")
+	in, err = LoadOrDefault[Cfg](store)
+	require.NoError(t, err)
+	require.Equal(t, "custom", in.ProductType)
+}
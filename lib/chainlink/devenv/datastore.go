@@ -0,0 +1,143 @@
+package devenv
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/smartcontractkit/chainlink-deployments-framework/datastore"
+
+	chainsel "github.com/smartcontractkit/chain-selectors"
+
+	cldf "github.com/smartcontractkit/chainlink-deployments-framework/deployment"
+
+	"github.com/smartcontractkit/chainlink/devenv/products/ocr2"
+)
+
+// OCR2Aggregator is the contract type feed aggregators are registered with
+const OCR2Aggregator cldf.ContractType = "OCR2Aggregator"
+
+const (
+	// LabelFeed is the label aggregators are registered with, its value is the feed name
+	LabelFeed = "feed"
+	// LabelProduct is the label every contract registered by RegisterDeployedFeeds has, ex.: product=ocr2
+	LabelProduct = "product"
+)
+
+// DefaultContractVersion is the version contracts are registered with, devenv deploys a single version of every contract
+var DefaultContractVersion = semver.MustParse("1.0.0")
+
+// DataStoreRegistration registers contracts in the datastore of a CLDF environment before it's sealed, ex.: RegisterDeployedFeeds
+type DataStoreRegistration func(ds datastore.MutableDataStore) error
+
+// ContractLabels tell deployments sharing a datastore apart, ex.: {"feed": "ETH/USD", "env": "staging"},
+// they are stored as key=value datastore labels
+type ContractLabels map[string]string
+
+// labelSet returns labels as a datastore label set, keys must be non-empty and must not contain "="
+func (l ContractLabels) labelSet() (datastore.LabelSet, error) {
+	labels := make([]string, 0, len(l))
+	for _, k := range slices.Sorted(maps.Keys(l)) {
+		if k == "" || strings.Contains(k, "=") {
+			return datastore.LabelSet{}, fmt.Errorf("invalid label key %q, it must be non-empty and must not contain \"=\"", k)
+		}
+		labels = append(labels, label(k, l[k]))
+	}
+	return datastore.NewLabelSet(labels...), nil
+}
+
+func label(key, value string) string { return key + "=" + value }
+
+// RegisterContract adds a deployed contract with labels to the datastore, qualifier tells apart contracts of the same type on a chain,
+// ex.: feed name of an aggregator
+func RegisterContract(ds datastore.MutableDataStore, chainSelector uint64, typ cldf.ContractType, address, qualifier string, labels ContractLabels) error {
+	if address == "" {
+		return fmt.Errorf("no address to register %s contract", typ)
+	}
+	set, err := labels.labelSet()
+	if err != nil {
+		return fmt.Errorf("could not register %s contract %s: %w", typ, address, err)
+	}
+	err = ds.Addresses().Add(datastore.AddressRef{
+		Address:       address,
+		ChainSelector: chainSelector,
+		Type:          datastore.ContractType(typ),
+		Version:       DefaultContractVersion,
+		Qualifier:     qualifier,
+		Labels:        set,
+	})
+	if err != nil {
+		return fmt.Errorf("could not register %s contract %s: %w", typ, address, err)
+	}
+	return nil
+}
+
+// RegisterLinkToken adds LINK token to the datastore, there is one LINK token per chain
+func RegisterLinkToken(ds datastore.MutableDataStore, chainSelector uint64, address string, labels ContractLabels) error {
+	return RegisterContract(ds, chainSelector, LinkToken, address, "", labels)
+}
+
+// RegisterAggregator adds a feed aggregator to the datastore, feed name is its qualifier and LabelFeed label,
+// so every feed of an environment has its own entry
+func RegisterAggregator(ds datastore.MutableDataStore, chainSelector uint64, feed, address string, labels ContractLabels) error {
+	if feed == "" {
+		return fmt.Errorf("no feed name to register aggregator %s", address)
+	}
+	l := maps.Clone(labels)
+	if l == nil {
+		l = make(ContractLabels, 1)
+	}
+	l[LabelFeed] = feed
+	return RegisterContract(ds, chainSelector, OCR2Aggregator, address, feed, l)
+}
+
+// RegisterDeployedFeeds registers aggregators of deployed feeds with LabelProduct and common labels, ex.: env tag,
+// feeds come from product output, ex.: DeployedContracts.AllFeeds()
+func RegisterDeployedFeeds(chainID, product string, feeds []*ocr2.DeployedFeed, labels ContractLabels) DataStoreRegistration {
+	return func(ds datastore.MutableDataStore) error {
+		details, err := chainsel.GetChainDetailsByChainIDAndFamily(chainID, chainsel.FamilyEVM)
+		if err != nil {
+			return fmt.Errorf("failed to get chain details for %s: %w", chainID, err)
+		}
+		l := maps.Clone(labels)
+		if l == nil {
+			l = make(ContractLabels, 1)
+		}
+		l[LabelProduct] = product
+		errs := make([]error, 0)
+		for _, f := range feeds {
+			errs = append(errs, RegisterAggregator(ds, details.ChainSelector, f.Name, f.Address, l))
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// AddressRefByLabel filters contracts labeled key=value, it can be combined with other datastore filters, ex.: datastore.AddressRefByType
+func AddressRefByLabel(key, value string) datastore.FilterFunc[datastore.AddressRefKey, datastore.AddressRef] {
+	return func(refs []datastore.AddressRef) []datastore.AddressRef {
+		res := make([]datastore.AddressRef, 0)
+		for _, r := range refs {
+			if r.Labels.Contains(label(key, value)) {
+				res = append(res, r)
+			}
+		}
+		return res
+	}
+}
+
+// ContractByLabel returns the only contract of the type labeled key=value, ex.: aggregator of a feed
+func ContractByLabel(ds datastore.DataStore, typ cldf.ContractType, key, value string) (datastore.AddressRef, error) {
+	refs := ds.Addresses().Filter(datastore.AddressRefByType(datastore.ContractType(typ)), AddressRefByLabel(key, value))
+	switch len(refs) {
+	case 0:
+		return datastore.AddressRef{}, fmt.Errorf("no %s contract labeled %s", typ, label(key, value))
+	case 1:
+		return refs[0], nil
+	default:
+		return datastore.AddressRef{}, fmt.Errorf("%d %s contracts are labeled %s, add a label to tell them apart", len(refs), typ, label(key, value))
+	}
+}
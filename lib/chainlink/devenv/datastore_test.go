@@ -0,0 +1,74 @@
+package devenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink-deployments-framework/datastore"
+
+	"github.com/smartcontractkit/chainlink/devenv/products/ocr2"
+)
+
+func TestContractByLabel(t *testing.T) {
+	const (
+		selector = uint64(1)
+		ethUSD   = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+		btcUSD   = "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"
+		link     = "0x9fE46736679d2D9a65F0992F2272dE9f3c7fa6e0"
+	)
+	mds := datastore.NewMemoryDataStore()
+	require.NoError(t, RegisterLinkToken(mds, selector, link, ContractLabels{"env": "staging"}))
+	require.NoError(t, RegisterAggregator(mds, selector, "ETH/USD", ethUSD, ContractLabels{"env": "staging"}))
+	require.NoError(t, RegisterAggregator(mds, selector, "BTC/USD", btcUSD, ContractLabels{"env": "staging"}))
+	// a feed is registered once per chain, its name is the qualifier
+	require.Error(t, RegisterAggregator(mds, selector, "ETH/USD", btcUSD, nil))
+	ds := mds.Seal()
+
+	ref, err := ContractByLabel(ds, OCR2Aggregator, LabelFeed, "BTC/USD")
+	require.NoError(t, err)
+	require.Equal(t, btcUSD, ref.Address)
+	require.Equal(t, "BTC/USD", ref.Qualifier)
+	require.True(t, ref.Labels.Contains("env=staging"))
+
+	ref, err = ContractByLabel(ds, LinkToken, "env", "staging")
+	require.NoError(t, err)
+	require.Equal(t, link, ref.Address)
+
+	_, err = ContractByLabel(ds, OCR2Aggregator, "env", "staging")
+	require.ErrorContains(t, err, "2 OCR2Aggregator contracts are labeled env=staging")
+	_, err = ContractByLabel(ds, OCR2Aggregator, LabelFeed, "SOL/USD")
+	require.ErrorContains(t, err, "no OCR2Aggregator contract labeled feed=SOL/USD")
+
+	refs := ds.Addresses().Filter(AddressRefByLabel("env", "staging"))
+	require.Len(t, refs, 3)
+}
+
+func TestRegisterContractLabels(t *testing.T) {
+	mds := datastore.NewMemoryDataStore()
+	err := RegisterContract(mds, 1, LinkToken, "0x9fE46736679d2D9a65F0992F2272dE9f3c7fa6e0", "", ContractLabels{"a=b": "c"})
+	require.ErrorContains(t, err, `invalid label key "a=b"`)
+	err = RegisterContract(mds, 1, LinkToken, "", "", nil)
+	require.ErrorContains(t, err, "no address to register LinkToken contract")
+	err = RegisterAggregator(mds, 1, "", "0x5FbDB2315678afecb367f032d93F642f64180aa3", nil)
+	require.ErrorContains(t, err, "no feed name")
+}
+
+func TestRegisterDeployedFeeds(t *testing.T) {
+	mds := datastore.NewMemoryDataStore()
+	register := RegisterDeployedFeeds("1337", "ocr2", []*ocr2.DeployedFeed{
+		{Name: "ETH/USD", Address: "0x5FbDB2315678afecb367f032d93F642f64180aa3"},
+		{Name: "BTC/USD", Address: "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"},
+	}, ContractLabels{"env": "ci"})
+	require.NoError(t, register(mds))
+	ds := mds.Seal()
+
+	refs := ds.Addresses().Filter(AddressRefByLabel(LabelProduct, "ocr2"), AddressRefByLabel("env", "ci"))
+	require.Len(t, refs, 2)
+	ref, err := ContractByLabel(ds, OCR2Aggregator, LabelFeed, "ETH/USD")
+	require.NoError(t, err)
+	require.Equal(t, "0x5FbDB2315678afecb367f032d93F642f64180aa3", ref.Address)
+
+	err = RegisterDeployedFeeds("not-a-chain", "ocr2", nil, nil)(datastore.NewMemoryDataStore())
+	require.ErrorContains(t, err, "failed to get chain details for not-a-chain")
+}
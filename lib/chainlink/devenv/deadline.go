@@ -0,0 +1,152 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultUpTimeout bounds the whole NewEnvironment bring-up, image pulls included
+	DefaultUpTimeout = 15 * time.Minute
+	// EnvVarUpTimeout overrides DefaultUpTimeout, ex.: CTF_UP_TIMEOUT=30m, it's set by cl up --timeout
+	EnvVarUpTimeout = "CTF_UP_TIMEOUT"
+)
+
+// ErrUpTimeout is returned by NewEnvironment when bring-up doesn't finish in time
+var ErrUpTimeout = errors.New("environment bring-up timed out")
+
+// UpTimeout returns bring-up timeout from CTF_UP_TIMEOUT, DefaultUpTimeout if it's not set
+func UpTimeout() (time.Duration, error) {
+	raw := os.Getenv(EnvVarUpTimeout)
+	if raw == "" {
+		return DefaultUpTimeout, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", EnvVarUpTimeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %s", EnvVarUpTimeout, raw)
+	}
+	return d, nil
+}
+
+// EventStatus is the state of a bring-up phase reported by a progress event
+type EventStatus string
+
+const (
+	EventStarted   EventStatus = "started"
+	EventCompleted EventStatus = "completed"
+	EventFailed    EventStatus = "failed"
+)
+
+// Event is a structured bring-up progress event, ex.: to render a progress bar, Duration is set once the phase is over
+type Event struct {
+	Phase    string        `json:"phase"`
+	Status   EventStatus   `json:"status"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// UpOptions are optional NewEnvironment settings
+type UpOptions struct {
+	// OnProgress is called when a bring-up phase starts, completes or fails, calls are serialized, it's not called if nil
+	OnProgress func(Event)
+	// Logger receives bring-up logs of the environment and products, the package logger L is used if nil
+	Logger *zerolog.Logger
+}
+
+// upPhase is the bring-up step in progress, it's reported when bring-up times out and to OnProgress
+type upPhase struct {
+	mu         sync.Mutex
+	name       string
+	started    time.Time
+	onProgress func(Event)
+	log        *zerolog.Logger
+	// closed stops events once the result is reported, a bring-up stuck past its deadline can still switch phases
+	closed bool
+}
+
+// set completes the previous step and records the step that starts now
+func (p *upPhase) set(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.finishLocked(nil)
+	p.name = name
+	p.started = time.Now()
+	p.emitLocked(Event{Phase: name, Status: EventStarted})
+	p.log.Info().Str("Phase", name).Msg("Bringing up the environment")
+}
+
+// finish reports the result of the step in progress, later steps are not reported
+func (p *upPhase) finish(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.finishLocked(err)
+	p.closed = true
+}
+
+func (p *upPhase) finishLocked(err error) {
+	if p.name == "" {
+		return
+	}
+	e := Event{Phase: p.name, Status: EventCompleted, Duration: time.Since(p.started)}
+	if err != nil {
+		e.Status = EventFailed
+		e.Error = err.Error()
+	}
+	p.emitLocked(e)
+}
+
+func (p *upPhase) emitLocked(e Event) {
+	if p.onProgress != nil {
+		p.onProgress(e)
+	}
+}
+
+func (p *upPhase) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.name == "" {
+		return "starting"
+	}
+	return p.name
+}
+
+// runWithDeadline runs fn with a context cancelled after timeout and returns as soon as the deadline is reached,
+// CTF components don't accept a context, so a stuck docker pull or container start would block fn forever.
+// fn keeps running in the background after the deadline, but its context is cancelled
+func runWithDeadline(ctx context.Context, timeout time.Duration, onProgress func(Event), fn func(ctx context.Context, phase *upPhase) error) error {
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, ErrUpTimeout)
+	defer cancel()
+	phase := &upPhase{onProgress: onProgress, log: ctxLogger(ctx)}
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx, phase) }()
+	var err error
+	select {
+	case err = <-done:
+		if err != nil && errors.Is(context.Cause(ctx), ErrUpTimeout) {
+			err = fmt.Errorf("%w after %s while %s: %w", ErrUpTimeout, timeout, phase, err)
+		}
+	case <-ctx.Done():
+		if errors.Is(context.Cause(ctx), ErrUpTimeout) {
+			err = fmt.Errorf("%w after %s while %s, run `cl down` to remove started containers", ErrUpTimeout, timeout, phase)
+		} else {
+			err = fmt.Errorf("environment bring-up cancelled while %s: %w", phase, context.Cause(ctx))
+		}
+	}
+	phase.finish(err)
+	return err
+}
@@ -0,0 +1,156 @@
+package devenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/devenv/products"
+)
+
+func TestUpTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		want    time.Duration
+		wantErr string
+	}{
+		{name: "default", want: DefaultUpTimeout},
+		{name: "override", env: "30m", want: 30 * time.Minute},
+		{name: "invalid", env: "soon", wantErr: "invalid CTF_UP_TIMEOUT"},
+		{name: "negative", env: "-1m", wantErr: "CTF_UP_TIMEOUT must be positive"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(EnvVarUpTimeout, tc.env)
+			got, err := UpTimeout()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRunWithDeadline(t *testing.T) {
+	t.Run("stuck phase", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		err := runWithDeadline(context.Background(), 50*time.Millisecond, nil, func(ctx context.Context, phase *upPhase) error {
+			phase.set("creating node set don")
+			<-block
+			return nil
+		})
+		require.ErrorIs(t, err, ErrUpTimeout)
+		require.ErrorContains(t, err, "while creating node set don")
+	})
+	t.Run("phase honors context", func(t *testing.T) {
+		err := runWithDeadline(context.Background(), 50*time.Millisecond, nil, func(ctx context.Context, phase *upPhase) error {
+			phase.set("verifying product is live")
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.ErrorIs(t, err, ErrUpTimeout)
+		require.ErrorContains(t, err, "while verifying product is live")
+	})
+	t.Run("finished", func(t *testing.T) {
+		err := runWithDeadline(context.Background(), time.Minute, nil, func(ctx context.Context, phase *upPhase) error {
+			phase.set("loading configuration")
+			return nil
+		})
+		require.NoError(t, err)
+	})
+	t.Run("failed", func(t *testing.T) {
+		failed := errors.New("no blockchains")
+		err := runWithDeadline(context.Background(), time.Minute, nil, func(ctx context.Context, phase *upPhase) error {
+			return failed
+		})
+		require.ErrorIs(t, err, failed)
+		require.NotErrorIs(t, err, ErrUpTimeout)
+	})
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		block := make(chan struct{})
+		defer close(block)
+		err := runWithDeadline(ctx, time.Minute, nil, func(ctx context.Context, phase *upPhase) error {
+			<-block
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorContains(t, err, "cancelled while starting")
+	})
+}
+
+func TestRunWithDeadlineProgress(t *testing.T) {
+	var events []Event
+	record := func(e Event) { events = append(events, e) }
+	statuses := func() []string {
+		out := make([]string, 0, len(events))
+		for _, e := range events {
+			out = append(out, e.Phase+" "+string(e.Status))
+		}
+		return out
+	}
+
+	err := runWithDeadline(context.Background(), time.Minute, record, func(ctx context.Context, phase *upPhase) error {
+		phase.set("loading configuration")
+		phase.set("creating node set don")
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"loading configuration started",
+		"loading configuration completed",
+		"creating node set don started",
+		"creating node set don completed",
+	}, statuses())
+	require.Zero(t, events[0].Duration)
+	require.Positive(t, events[1].Duration)
+
+	events = nil
+	err = runWithDeadline(context.Background(), time.Minute, record, func(ctx context.Context, phase *upPhase) error {
+		phase.set("configuring jobs and contracts")
+		return errors.New("no worker node set found")
+	})
+	require.Error(t, err)
+	require.Equal(t, []string{"configuring jobs and contracts started", "configuring jobs and contracts failed"}, statuses())
+	require.Equal(t, "no worker node set found", events[1].Error)
+
+	// a phase switched after the deadline is not reported
+	events = nil
+	block := make(chan struct{})
+	finished := make(chan struct{})
+	err = runWithDeadline(context.Background(), 50*time.Millisecond, record, func(ctx context.Context, phase *upPhase) error {
+		defer close(finished)
+		phase.set("pinning images")
+		<-block
+		phase.set("creating docker network")
+		return nil
+	})
+	close(block)
+	<-finished
+	require.ErrorIs(t, err, ErrUpTimeout)
+	require.Equal(t, []string{"pinning images started", "pinning images failed"}, statuses())
+	require.Contains(t, events[1].Error, "timed out after 50ms while pinning images")
+}
+
+func TestRunWithDeadlineLogger(t *testing.T) {
+	var out bytes.Buffer
+	ctx := products.WithLogger(context.Background(), zerolog.New(&out))
+	err := runWithDeadline(ctx, time.Minute, nil, func(ctx context.Context, phase *upPhase) error {
+		phase.set("creating node set don")
+		ctxLogger(ctx).Info().Msg("node set is up")
+		return nil
+	})
+	require.NoError(t, err)
+	require.Contains(t, out.String(), `"Phase":"creating node set don"`)
+	require.Contains(t, out.String(), `"message":"node set is up"`)
+}
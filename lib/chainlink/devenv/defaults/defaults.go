@@ -0,0 +1,22 @@
+// Package defaults resolves optional config values in one place, so every unset value falls back the same way:
+// explicit config first, then environment overrides where they apply, then defaults.
+package defaults
+
+import "os"
+
+// Coalesce returns the first non-zero value, zero value if all values are zero, ex.: Coalesce(cfg.Port, DefaultPort)
+func Coalesce[T comparable](values ...T) T {
+	var zero T
+	for _, v := range values {
+		if v != zero {
+			return v
+		}
+	}
+	return zero
+}
+
+// EnvOr returns environment variable value if it's set and not empty, fallback otherwise,
+// combine with Coalesce to resolve "env override, then config, then default": EnvOr(key, Coalesce(cfg, def))
+func EnvOr(key, fallback string) string {
+	return Coalesce(os.Getenv(key), fallback)
+}
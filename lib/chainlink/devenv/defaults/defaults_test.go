@@ -0,0 +1,25 @@
+package defaults
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalesce(t *testing.T) {
+	require.Equal(t, 6690, Coalesce(0, 6690))
+	require.Equal(t, 7000, Coalesce(7000, 6690))
+	require.Equal(t, 3, Coalesce(0, 0, 3, 4))
+	require.Equal(t, "", Coalesce("", ""))
+	require.Equal(t, "1s", Coalesce("", "1s"))
+	require.Equal(t, int64(0), Coalesce[int64]())
+}
+
+func TestEnvOr(t *testing.T) {
+	const key = "DEVENV_DEFAULTS_TEST"
+	t.Setenv(key, "")
+	require.Equal(t, "image:config", EnvOr(key, "image:config"))
+	t.Setenv(key, "image:env")
+	require.Equal(t, "image:env", EnvOr(key, "image:config"))
+	require.Equal(t, "image:env", EnvOr(key, Coalesce("", "image:default")))
+}
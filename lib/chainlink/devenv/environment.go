@@ -2,24 +2,74 @@ package devenv
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
 	"os"
 
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/ethereum/go-ethereum/common"
+
 	"github.com/smartcontractkit/chainlink-testing-framework/framework"
 	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
 	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/fake"
 	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/jd"
 
 	ns "github.com/smartcontractkit/chainlink-testing-framework/framework/components/simple_node_set"
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+	"github.com/smartcontractkit/chainlink/devenv/products"
 	"github.com/smartcontractkit/chainlink/devenv/products/ocr2"
 )
 
 type Cfg struct {
 	ProductType string              `toml:"product_type"`
 	Blockchains []*blockchain.Input `toml:"blockchains" validate:"required"`
-	FakeServer  *fake.Input         `toml:"fake_server" validate:"required"`
+	FakeServer  *fake.Input         `toml:"fake_server"`
 	NodeSets    []*ns.Input         `toml:"nodesets"    validate:"required"`
 	JD          *jd.Input           `toml:"jd"`
+	// FakeServers are additional fake data providers, ex.: independent data sources for several feeds
+	FakeServers []*fake.Input `toml:"fake_servers"`
+	// PinImageDigests resolves image tags to digests at bring-up, so env-out.toml records the exact images used
+	PinImageDigests bool `toml:"pin_image_digests"`
+	// Meta records who and what produced env-out.toml, only description is read from the input config
+	Meta *products.Meta `toml:"meta"`
+	// Artifacts sets the directory outputs and logs go to, CTF_ARTIFACTS_DIR and CTF_RUN_ID take precedence
+	Artifacts *products.Artifacts `toml:"artifacts"`
+	// RequireRounds is how many rounds the product must report before up returns, 0 returns once the product is live
+	RequireRounds int `toml:"require_rounds"`
+	// TelemetryIngress is started with the environment if it's set, nodes and jobs send telemetry to it
+	TelemetryIngress *TelemetryIngress `toml:"telemetry_ingress"`
+}
+
+// Fakes returns all fake servers, fake_server comes first if it's set, so a single fake server config keeps index 0
+func (c *Cfg) Fakes() []*fake.Input {
+	fakes := make([]*fake.Input, 0, len(c.FakeServers)+1)
+	if c.FakeServer != nil {
+		fakes = append(fakes, c.FakeServer)
+	}
+	return append(fakes, c.FakeServers...)
+}
+
+// validateFakes checks there is at least one fake server and fake servers don't share ports
+func (c *Cfg) validateFakes() error {
+	fakes := c.Fakes()
+	if len(fakes) == 0 {
+		return errors.New("no fake servers configured, set fake_server or fake_servers")
+	}
+	ports := make(map[int]int)
+	for i, f := range fakes {
+		if prev, ok := ports[f.Port]; ok {
+			return fmt.Errorf("fake servers %d and %d use the same port %d", prev, i, f.Port)
+		}
+		ports[f.Port] = i
+	}
+	return nil
 }
 
 func newProduct(typ string) (Product, error) {
@@ -31,26 +81,84 @@ func newProduct(typ string) (Product, error) {
 	}
 }
 
-func NewEnvironment(ctx context.Context) error {
+// NewEnvironment brings up blockchain, fakes, node sets and the product, it fails with ErrUpTimeout naming the step in progress
+// if bring-up takes longer than UpTimeout. Optional UpOptions report progress of every step and set the logger,
+// a logger injected into ctx with products.WithLogger is used as well
+func NewEnvironment(ctx context.Context, opts ...UpOptions) error {
+	timeout, err := UpTimeout()
+	if err != nil {
+		return err
+	}
+	var o UpOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Logger != nil {
+		ctx = products.WithLogger(ctx, *o.Logger)
+	}
+	return runWithDeadline(ctx, timeout, o.OnProgress, newEnvironment)
+}
+
+func newEnvironment(ctx context.Context, phase *upPhase) error {
+	phase.set("creating docker network")
 	if err := framework.DefaultNetwork(nil); err != nil {
 		return err
 	}
-	in, err := Load[Cfg]()
+	phase.set("loading configuration")
+	in, err := LoadOrDefault[Cfg]()
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
-	_, err = blockchain.NewBlockchainNetwork(in.Blockchains[0])
-	if err != nil {
-		return fmt.Errorf("failed to create blockchain network 1337: %w", err)
+	if err := in.validateFakes(); err != nil {
+		return err
 	}
-	if os.Getenv("FAKE_SERVER_IMAGE") != "" {
-		in.FakeServer.Image = os.Getenv("FAKE_SERVER_IMAGE")
+	if in.RequireRounds < 0 {
+		return fmt.Errorf("require_rounds must be non-negative, got %d", in.RequireRounds)
 	}
-	_, err = fake.NewDockerFakeDataProvider(in.FakeServer)
-	if err != nil {
-		return fmt.Errorf("failed to create fake data provider: %w", err)
+	if err := in.TelemetryIngress.Validate(); err != nil {
+		return err
+	}
+	if err := in.Artifacts.Apply(); err != nil {
+		return fmt.Errorf("could not set artifacts directory: %w", err)
+	}
+	for _, f := range in.Fakes() {
+		f.Image = defaults.EnvOr("FAKE_SERVER_IMAGE", f.Image)
+	}
+	for _, nodeSet := range in.NodeSets {
+		for _, spec := range nodeSet.NodeSpecs {
+			spec.Node.Image = defaults.EnvOr("CHAINLINK_IMAGE", spec.Node.Image)
+		}
+	}
+	phase.set("pinning images")
+	if err := pinImages(ctx, in); err != nil {
+		return err
+	}
+	// running node sets are reused to iterate on jobs and contracts without waiting for nodes to start
+	reused := false
+	if os.Getenv(EnvVarReuseNodeSets) == "true" {
+		phase.set("checking running node sets")
+		if rErr := reuseRunning(ctx, in); rErr != nil {
+			ctxLogger(ctx).Warn().Err(rErr).Msg("Running node sets can't be reused, recreating the environment")
+		} else {
+			reused = true
+		}
+	}
+	bc := in.Blockchains[0]
+	if !reused {
+		phase.set(fmt.Sprintf("creating %s blockchain network %s", bc.Type, bc.ChainID))
+		_, err = blockchain.NewBlockchainNetwork(bc)
+		if err != nil {
+			return fmt.Errorf("failed to create %s blockchain network %s: %w", bc.Type, bc.ChainID, err)
+		}
+		for i, f := range in.Fakes() {
+			phase.set(fmt.Sprintf("creating fake data provider %d", i))
+			if _, err := fake.NewDockerFakeDataProvider(f); err != nil {
+				return fmt.Errorf("failed to create fake data provider %d: %w", i, err)
+			}
+		}
 	}
 
+	phase.set("loading product config")
 	c, err := newProduct(in.ProductType)
 	if err != nil {
 		return err
@@ -58,38 +166,312 @@ func NewEnvironment(ctx context.Context) error {
 	if err = c.Load(); err != nil {
 		return fmt.Errorf("failed to load product config: %w", err)
 	}
+	if in.RequireRounds > 0 {
+		v, err := roundsVerifier(in, c)
+		if err != nil {
+			return err
+		}
+		if err := v.ValidateRounds(in.RequireRounds); err != nil {
+			return fmt.Errorf("invalid require_rounds: %w", err)
+		}
+	}
 
+	if in.TelemetryIngress != nil {
+		phase.set("creating telemetry ingress")
+	}
+	if err := useTelemetryIngress(ctx, in, c); err != nil {
+		return err
+	}
 	overrides, err := c.GenerateCLNodesBlockchainConfig(ctx, in.Blockchains[0])
 	if err != nil {
 		return fmt.Errorf("failed to generate CL nodes config: %w", err)
 	}
-	for _, ns := range in.NodeSets[0].NodeSpecs {
-		ns.Node.TestConfigOverrides = overrides
-		if os.Getenv("CHAINLINK_IMAGE") != "" {
-			ns.Node.Image = os.Getenv("CHAINLINK_IMAGE")
+	// the first node set runs product jobs, others can host dedicated nodes, ex.: bootstrap nodes,
+	// reused nodes keep the config they were started with
+	for _, nodeSet := range in.NodeSets {
+		for _, spec := range nodeSet.NodeSpecs {
+			spec.Node.TestConfigOverrides = overrides
+		}
+		if reused {
+			continue
+		}
+		phase.set("creating node set " + nodeSet.Name)
+		_, err = ns.NewSharedDBNodeSet(nodeSet, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create new shared db node set %s: %w", nodeSet.Name, err)
 		}
 	}
 
-	_, err = ns.NewSharedDBNodeSet(in.NodeSets[0], nil)
-	if err != nil {
-		return fmt.Errorf("failed to create new shared db node set: %w", err)
+	if in.JD != nil {
+		phase.set("creating job distributor")
 	}
-
+	if err := useJobDistributor(ctx, in, c); err != nil {
+		return err
+	}
+	phase.set("configuring jobs and contracts")
 	err = c.ConfigureJobsAndContracts(
 		ctx,
-		in.FakeServer,
+		in.Fakes(),
 		in.Blockchains[0],
-		in.NodeSets[0],
+		in.NodeSets,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to setup default product deployment: %w", err)
 	}
-	L.Info().Str("BootstrapNode", in.NodeSets[0].Out.CLNodes[0].Node.ExternalURL).Send()
-	for _, n := range in.NodeSets[0].Out.CLNodes[1:] {
-		L.Info().Str("Node", n.Node.ExternalURL).Send()
+	for _, nodeSet := range in.NodeSets {
+		for _, n := range nodeSet.Out.CLNodes {
+			ctxLogger(ctx).Info().Str("NodeSet", nodeSet.Name).Str("Node", n.Node.ExternalURL).Send()
+		}
 	}
+	phase.set("storing outputs")
+	in.Meta = products.NewMeta(in.Meta, os.Getenv(EnvVarTestConfigs))
 	if err := Store[Cfg](in); err != nil {
 		return fmt.Errorf("failed to write infra config: %w", err)
 	}
-	return c.Store("env-out.toml")
+	if err := c.Store("env-out.toml"); err != nil {
+		return err
+	}
+	// outputs are stored first, so the environment can be inspected or destroyed if verification fails
+	phase.set("verifying product is live")
+	if err := c.VerifyLive(ctx, in.Blockchains[0]); err != nil {
+		return fmt.Errorf("environment is up but product is not working: %w", err)
+	}
+	if in.RequireRounds > 0 {
+		phase.set(fmt.Sprintf("waiting for %d product rounds", in.RequireRounds))
+		if err := verifyRounds(ctx, in, c); err != nil {
+			return fmt.Errorf("environment is up but product is not healthy: %w", err)
+		}
+	}
+	return nil
+}
+
+// verifyRounds waits for require_rounds product rounds
+func verifyRounds(ctx context.Context, in *Cfg, c Product) error {
+	v, err := roundsVerifier(in, c)
+	if err != nil {
+		return err
+	}
+	return v.VerifyRounds(ctx, in.Blockchains[0], in.RequireRounds)
+}
+
+// roundsVerifier returns product as RoundsVerifier, products that can't count rounds fail
+func roundsVerifier(in *Cfg, c Product) (RoundsVerifier, error) {
+	v, ok := c.(RoundsVerifier)
+	if !ok {
+		return nil, fmt.Errorf("product type %s can't verify rounds, unset require_rounds", in.ProductType)
+	}
+	return v, nil
+}
+
+// ScaleNodeSet changes the number of nodes participating in a running node set and reconfigures the product
+func ScaleNodeSet(ctx context.Context, name string, count int) error {
+	in, err := LoadOutput[Cfg](products.DefaultOutputPath())
+	if err != nil {
+		return fmt.Errorf("failed to load environment output: %w", err)
+	}
+	var nodeSet *ns.Input
+	for _, n := range in.NodeSets {
+		if n.Name == name {
+			nodeSet = n
+			break
+		}
+	}
+	if nodeSet == nil || nodeSet.Out == nil {
+		return fmt.Errorf("node set %s is not found in %s", name, products.DefaultOutputPath())
+	}
+	if in.ProductType != "ocr2" {
+		return fmt.Errorf("scaling is not supported for product type: %s", in.ProductType)
+	}
+	c, err := products.LoadOutput[ocr2.Configurator](products.DefaultOutputPath())
+	if err != nil {
+		return fmt.Errorf("failed to load product output: %w", err)
+	}
+	return c.Scale(ctx, in.Blockchains[0], nodeSet, count)
+}
+
+// CheckConfigDigest computes OCR2 config digest from env-out.toml offline and compares it with the stored on-chain digest
+func CheckConfigDigest() error {
+	in, err := LoadOutput[Cfg](products.DefaultOutputPath())
+	if err != nil {
+		return fmt.Errorf("failed to load environment output: %w", err)
+	}
+	c, err := products.LoadOutput[ocr2.Configurator](products.DefaultOutputPath())
+	if err != nil {
+		return fmt.Errorf("failed to load product output: %w", err)
+	}
+	if c.OCR2.DeployedContracts == nil || c.OCR2.OCR2SetConfigOut == nil {
+		return fmt.Errorf("no OCR2 config found in %s, run the environment first", products.DefaultOutputPath())
+	}
+	digest, err := ocr2.ComputeConfigDigest(common.HexToAddress(c.OCR2.DeployedContracts.OCRv2AggregatorAddr), in.Blockchains[0].ChainID, c.OCR2.OCR2SetConfigOut)
+	if err != nil {
+		return fmt.Errorf("failed to compute config digest: %w", err)
+	}
+	L.Info().
+		Str("Computed", digest.Hex()).
+		Str("Stored", c.OCR2.OCR2SetConfigOut.ConfigDigest).
+		Uint64("ConfigCount", c.OCR2.OCR2SetConfigOut.ConfigCount).
+		Msg("OCR2 config digest")
+	if digest.Hex() != c.OCR2.OCR2SetConfigOut.ConfigDigest {
+		return fmt.Errorf("computed config digest %s doesn't match stored digest %s", digest.Hex(), c.OCR2.OCR2SetConfigOut.ConfigDigest)
+	}
+	return nil
+}
+
+// useJobDistributor brings up JD if [jd] is configured and hands it to the product if it can propose jobs through it
+func useJobDistributor(ctx context.Context, in *Cfg, c Product) error {
+	if in.JD == nil {
+		return nil
+	}
+	out, err := jd.NewJD(in.JD)
+	if err != nil {
+		return fmt.Errorf("failed to create job distributor: %w", err)
+	}
+	in.JD.Out = out
+	u, ok := c.(JobDistributorUser)
+	if !ok {
+		return nil
+	}
+	client, err := NewJDClient(ctx, JDConfig{GRPC: out.ExternalGRPCUrl, WSRPC: out.ExternalWSRPCUrl})
+	if err != nil {
+		return fmt.Errorf("failed to create job distributor client: %w", err)
+	}
+	if err := WaitForJD(ctx, client, JDReadyTimeout); err != nil {
+		return err
+	}
+	u.UseJobDistributor(client, out.InternalWSRPCUrl)
+	return nil
+}
+
+// DiffOutputs compares two stored environment outputs, ex.: of a passing and a failing run, and returns a report
+// of changed infra fields (node URLs, containers, images) and OCR2 product fields (addresses, config parameters)
+func DiffOutputs(a, b string) (string, error) {
+	inA, err := LoadOutput[Cfg](a)
+	if err != nil {
+		return "", fmt.Errorf("failed to load environment output %s: %w", a, err)
+	}
+	inB, err := LoadOutput[Cfg](b)
+	if err != nil {
+		return "", fmt.Errorf("failed to load environment output %s: %w", b, err)
+	}
+	cA, err := products.LoadOutput[ocr2.Configurator](a)
+	if err != nil {
+		return "", fmt.Errorf("failed to load product output %s: %w", a, err)
+	}
+	cB, err := products.LoadOutput[ocr2.Configurator](b)
+	if err != nil {
+		return "", fmt.Errorf("failed to load product output %s: %w", b, err)
+	}
+	return products.FormatDiff(append(products.Diff(inA, inB), products.Diff(cA, cB)...)), nil
+}
+
+// DestroyEnvironment tears down the environment, it's what cl down runs. Product resources of the environment described
+// in env-out.toml are destroyed first, then node set, telemetry ingress, fake server and blockchain containers are removed
+// in reverse order of creation. Containers, networks and volumes the framework labels as its own are removed afterwards,
+// ex.: left by a failed bring-up, so it also cleans up if there is no env-out.toml
+func DestroyEnvironment(ctx context.Context) error {
+	dc, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer dc.Close()
+	outPath := products.DefaultOutputPath()
+	if _, err := os.Stat(outPath); errors.Is(err, fs.ErrNotExist) {
+		ctxLogger(ctx).Warn().Str("Output", outPath).Msg("No environment output found, removing framework resources only")
+	} else if err := destroyOutput(ctx, dc, outPath); err != nil {
+		return err
+	}
+	if err := removeFrameworkResources(ctx, dc); err != nil {
+		return err
+	}
+	ctxLogger(ctx).Info().Msg("Environment is destroyed")
+	return nil
+}
+
+// destroyOutput destroys product resources and removes containers of the environment described in the output at path
+func destroyOutput(ctx context.Context, dc *client.Client, path string) error {
+	in, err := LoadOutput[Cfg](path)
+	if err != nil {
+		return fmt.Errorf("failed to load environment output: %w", err)
+	}
+	c, err := newProduct(in.ProductType)
+	if err != nil {
+		return err
+	}
+	if err := c.Destroy(ctx); err != nil {
+		return fmt.Errorf("failed to destroy product: %w", err)
+	}
+
+	names := make([]string, 0)
+	for i := len(in.NodeSets) - 1; i >= 0; i-- {
+		out := in.NodeSets[i].Out
+		if out == nil {
+			continue
+		}
+		for j := len(out.CLNodes) - 1; j >= 0; j-- {
+			names = append(names, out.CLNodes[j].Node.ContainerName)
+		}
+		if out.DBOut != nil {
+			names = append(names, out.DBOut.ContainerName)
+		}
+	}
+	if in.TelemetryIngress != nil && in.TelemetryIngress.Out != nil {
+		names = append(names, in.TelemetryIngress.Out.ContainerName)
+	}
+	fakes := in.Fakes()
+	for i := len(fakes) - 1; i >= 0; i-- {
+		if fakes[i].Out == nil {
+			continue
+		}
+		u, err := url.Parse(fakes[i].Out.BaseURLDocker)
+		if err != nil {
+			return fmt.Errorf("failed to parse fake server %d docker URL: %w", i, err)
+		}
+		names = append(names, u.Hostname())
+	}
+	for i := len(in.Blockchains) - 1; i >= 0; i-- {
+		if in.Blockchains[i].Out != nil {
+			names = append(names, in.Blockchains[i].Out.ContainerName)
+		}
+	}
+	// remove as much as possible, a single failure shouldn't leave the rest of the environment running
+	var errs []error
+	for _, name := range names {
+		ctxLogger(ctx).Info().Str("Container", name).Msg("Removing container")
+		if err := dc.ContainerRemove(ctx, name, container.RemoveOptions{Force: true, RemoveVolumes: true}); err != nil && !errdefs.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to remove container %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// frameworkLabel marks containers, networks and volumes created by the framework
+var frameworkLabel = filters.NewArgs(filters.Arg("label", "framework=ctf"))
+
+// removeFrameworkResources removes all framework containers, then their networks and volumes, which can't be removed while in use
+func removeFrameworkResources(ctx context.Context, dc *client.Client) error {
+	if err := framework.RemoveTestContainers(); err != nil {
+		return fmt.Errorf("failed to clean Docker resources: %w", err)
+	}
+	var errs []error
+	networks, err := dc.NetworkList(ctx, network.ListOptions{Filters: frameworkLabel})
+	if err != nil {
+		return fmt.Errorf("failed to list docker networks: %w", err)
+	}
+	for _, n := range networks {
+		ctxLogger(ctx).Info().Str("Network", n.Name).Msg("Removing network")
+		if err := dc.NetworkRemove(ctx, n.ID); err != nil && !errdefs.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to remove network %s: %w", n.Name, err))
+		}
+	}
+	volumes, err := dc.VolumeList(ctx, volume.ListOptions{Filters: frameworkLabel})
+	if err != nil {
+		return fmt.Errorf("failed to list docker volumes: %w", err)
+	}
+	for _, v := range volumes.Volumes {
+		ctxLogger(ctx).Info().Str("Volume", v.Name).Msg("Removing volume")
+		if err := dc.VolumeRemove(ctx, v.Name, true); err != nil && !errdefs.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to remove volume %s: %w", v.Name, err))
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,69 @@
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/fake"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+	"github.com/smartcontractkit/chainlink/devenv/products"
+	"github.com/smartcontractkit/chainlink/devenv/products/ocr2"
+)
+
+// FakeServerReadyWait is how we wait for a recreated fake server to become healthy
+var FakeServerReadyWait = ocr2.WaitConfig{PollIntervalMs: 500, TimeoutSec: 60}
+
+// RestartFakeServer removes and recreates only the fake data provider container with index idx, chains and nodes keep running.
+// Container name and URLs are stable so jobs reconnect, FAKE_SERVER_IMAGE is applied if set.
+func RestartFakeServer(ctx context.Context, idx int) error {
+	in, err := LoadOutput[Cfg](products.DefaultOutputPath())
+	if err != nil {
+		return fmt.Errorf("failed to load environment output: %w", err)
+	}
+	fakes := in.Fakes()
+	if idx < 0 || idx >= len(fakes) {
+		return fmt.Errorf("fake server index %d is out of range, there are %d fake servers", idx, len(fakes))
+	}
+	fs := fakes[idx]
+	if fs.Out == nil {
+		return fmt.Errorf("no fake server output found in %s, run the environment first", products.DefaultOutputPath())
+	}
+	u, err := url.Parse(fs.Out.BaseURLDocker)
+	if err != nil {
+		return fmt.Errorf("failed to parse fake server docker URL: %w", err)
+	}
+	dc, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer dc.Close()
+	ctxLogger(ctx).Info().Str("Container", u.Hostname()).Msg("Removing fake server container")
+	if err := dc.ContainerRemove(ctx, u.Hostname(), container.RemoveOptions{Force: true}); err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to remove fake server container: %w", err)
+	}
+	fs.Image = defaults.EnvOr("FAKE_SERVER_IMAGE", fs.Image)
+	fs.Out = nil
+	out, err := fake.NewDockerFakeDataProvider(fs)
+	if err != nil {
+		return fmt.Errorf("failed to create fake data provider: %w", err)
+	}
+	pc, err := products.LoadOutput[ocr2.Configurator](products.DefaultOutputPath())
+	if err != nil {
+		return fmt.Errorf("failed to load product output: %w", err)
+	}
+	r := ocr2.NewFakeServerClient(out.BaseURLHost, pc.OCR2.EAFake)
+	if err := ocr2.WaitFakeServerReady(ctx, r, FakeServerReadyWait); err != nil {
+		return err
+	}
+	if err := pc.OCR2.SeedFake(ctx, r); err != nil {
+		return fmt.Errorf("failed to seed fake server value: %w", err)
+	}
+	ctxLogger(ctx).Info().Int("Idx", idx).Str("URL", out.BaseURLHost).Str("Image", fs.Image).Msg("Fake server is restarted")
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const (
+	// JuelsModeConstant returns DefaultJuelsPerLinkRatio regardless of gas
+	JuelsModeConstant = "constant"
+	// JuelsModeGasLinked scales DefaultJuelsPerLinkRatio with the injected block base fee
+	JuelsModeGasLinked = "gas_linked"
+	// JuelsReferenceBaseFeeWei is the base fee DefaultJuelsPerLinkRatio corresponds to in gas_linked mode, 1 gwei
+	JuelsReferenceBaseFeeWei = 1_000_000_000
+)
+
+// validateJuelsMode checks juels mode is supported
+func validateJuelsMode(mode string) error {
+	switch mode {
+	case JuelsModeConstant, JuelsModeGasLinked:
+		return nil
+	default:
+		return fmt.Errorf("unknown juels mode: %q, supported: %s, %s", mode, JuelsModeConstant, JuelsModeGasLinked)
+	}
+}
+
+// parseBaseFee parses base fee in wei, it must be positive
+func parseBaseFee(s string) (*big.Int, error) {
+	fee, ok := new(big.Int).SetString(s, 10)
+	if !ok || fee.Sign() <= 0 {
+		return nil, fmt.Errorf("base fee must be a positive integer in wei, got %q", s)
+	}
+	return fee, nil
+}
+
+// juelsPerFeeCoin derives juels ratio from the base fee, the ratio grows linearly with the fee and is never below 1.
+// Constant mode or no injected base fee returns DefaultJuelsPerLinkRatio
+func juelsPerFeeCoin(mode string, baseFee *big.Int) string {
+	if mode != JuelsModeGasLinked || baseFee == nil {
+		return DefaultJuelsPerLinkRatio
+	}
+	ratio, _ := new(big.Int).SetString(DefaultJuelsPerLinkRatio, 10)
+	ratio.Mul(ratio, baseFee)
+	ratio.Quo(ratio, big.NewInt(JuelsReferenceBaseFeeWei))
+	if ratio.Sign() <= 0 {
+		ratio.SetInt64(1)
+	}
+	return ratio.String()
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StatusClientClosedRequest is recorded for EA requests a client gave up on during injected latency, nginx uses the same code
+const StatusClientClosedRequest = 499
+
+// MaxLatency caps injected EA latency, anything longer is a stuck fake rather than a slow one
+const MaxLatency = 5 * time.Minute
+
+// parseLatency parses injected latency as a Go duration with sub-second precision, ex.: 1.5s, 750ms, 0 disables it
+func parseLatency(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("latency must be a duration, ex.: 1.5s or 750ms, got %q", s)
+	}
+	if d < 0 || d > MaxLatency {
+		return 0, fmt.Errorf("latency must be between 0 and %s, got %s", MaxLatency, d)
+	}
+	return d, nil
+}
+
+// sleepCtx waits for d, returns early with an error if a client is gone
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -1,7 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -15,41 +24,130 @@ var L = log.Output(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.DebugLev
 
 const (
 	DefaultJuelsPerLinkRatio = "15"
+	// ShutdownTimeout is how long we wait for in-flight requests to finish on SIGINT/SIGTERM
+	ShutdownTimeout = 10 * time.Second
+	// DefaultGinMode keeps gin quiet under load, requests are logged by requestLogger instead
+	DefaultGinMode = gin.ReleaseMode
+	// EnvInitialResult is the value /ea returns at startup, ex.: FAKE_INITIAL_RESULT=200, fake starts without a value if it's not set
+	EnvInitialResult = "FAKE_INITIAL_RESULT"
 )
 
-// some initial value, otherwise OCR2 jobs won't start
-var result = "200"
-
-// a very simple mock that allow us to control EA answers in tests
-func main() {
-	_, err := fake.NewFakeDataProvider(&fake.Input{Port: fake.DefaultFakeServicePort})
-	if err != nil {
-		panic(err)
+// ginMode returns gin mode from GIN_MODE, falls back to DefaultGinMode, unknown modes are rejected
+func ginMode() (string, error) {
+	switch m := os.Getenv(gin.EnvGinMode); m {
+	case "":
+		return DefaultGinMode, nil
+	case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown %s %q, expected one of: %s, %s, %s", gin.EnvGinMode, m, gin.DebugMode, gin.ReleaseMode, gin.TestMode)
 	}
-	err = fake.Func("POST", "/juelsPerFeeCoinSource", func(ctx *gin.Context) {
+}
+
+// ready is reported by /health, it's false until all routes are registered and during shutdown
+var ready atomic.Bool
+
+// newRouter creates fake server routes, all mutable state is kept in s, metrics are served on /metrics.
+// Custom middleware, ex.: auth or latency injection, is applied to all routes after the default recovery, request logging and metrics
+func newRouter(s *State, middleware ...gin.HandlerFunc) *gin.Engine {
+	m := newMetrics(s)
+	r := gin.New()
+	r.Use(defaultMiddleware()...)
+	r.Use(m.middleware())
+	r.Use(middleware...)
+	r.GET("/metrics", m.handler())
+	r.GET("/health", func(ctx *gin.Context) {
+		if !ready.Load() {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready"})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	r.POST("/juelsPerFeeCoinSource", func(ctx *gin.Context) {
 		ctx.JSON(200, gin.H{
 			"data": map[string]any{
-				"result": DefaultJuelsPerLinkRatio,
+				"result": s.Juels(),
 			},
 		})
 	})
-	if err != nil {
-		panic(err)
-	}
-
-	err = fake.Func("POST", "/trigger_deviation", func(ctx *gin.Context) {
-		result = ctx.Query("result")
+	r.POST("/set_juels_mode", func(ctx *gin.Context) {
+		mode := ctx.Query("mode")
+		if err := validateJuelsMode(mode); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		s.SetJuelsMode(mode)
+		L.Info().Str("Mode", mode).Msg("Changing juels mode")
+		ctx.JSON(200, gin.H{
+			"result": "ok",
+		})
+	})
+	r.POST("/set_base_fee", func(ctx *gin.Context) {
+		fee, err := parseBaseFee(ctx.Query("wei"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		s.SetBaseFee(fee)
+		L.Debug().Str("BaseFee", fee.String()).Msg("Base fee injected")
+		ctx.JSON(200, gin.H{
+			"result": "ok",
+		})
+	})
+	r.POST("/set_latency", func(ctx *gin.Context) {
+		d, err := parseLatency(ctx.Query("duration"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		s.SetLatency(d)
+		L.Info().Dur("Latency", d).Msg("Changing EA latency")
+		ctx.JSON(200, gin.H{
+			"result": "ok",
+		})
+	})
+	r.POST("/trigger_deviation", func(ctx *gin.Context) {
+		result := ctx.Query("result")
+		s.SetResult(result)
 		L.Info().Str("Result", result).Msg("Changing returned result")
 		ctx.JSON(200, gin.H{
 			"result": "ok",
 		})
 	})
-
-	if err != nil {
-		panic(err)
-	}
-
-	err = fake.Func("POST", "/ea", func(ctx *gin.Context) {
+	r.POST("/set_profile", func(ctx *gin.Context) {
+		var p Profile
+		if err := ctx.ShouldBindQuery(&p); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := p.Validate(); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		s.StartProfile(&p)
+		L.Info().Any("Profile", p).Msg("Starting EA profile")
+		ctx.JSON(200, gin.H{
+			"result": "ok",
+		})
+	})
+	r.GET("/value", func(ctx *gin.Context) {
+		ctx.JSON(200, gin.H{
+			"result": s.Result(),
+		})
+	})
+	r.POST("/ea", func(ctx *gin.Context) {
+		if err := sleepCtx(ctx.Request.Context(), s.Latency()); err != nil {
+			L.Debug().Err(err).Msg("Client gone before injected latency passed")
+			m.injectedError(InjectedErrorLatencyTimeout)
+			// nothing is written to a gone client, the status only keeps the request from being counted as served
+			ctx.Status(StatusClientClosedRequest)
+			return
+		}
+		result := s.Result()
+		if result == "" {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "no value is set, seed it with POST /trigger_deviation"})
+			return
+		}
 		L.Info().Str("Result", result).Msg("Returning feed value result")
 		ctx.JSON(200, gin.H{
 			"data": map[string]any{
@@ -57,8 +155,47 @@ func main() {
 			},
 		})
 	})
+	return r
+}
+
+// a very simple mock that allow us to control EA answers in tests
+func main() {
+	mode, err := ginMode()
+	if err != nil {
+		L.Fatal().Err(err).Msg("Invalid gin mode")
+	}
+	gin.SetMode(mode)
+	// OCR2 jobs don't start until /ea returns a value, the environment seeds ocr2.initial_value before jobs are created
+	r := newRouter(NewState(os.Getenv(EnvInitialResult)))
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", fake.DefaultFakeServicePort),
+		Handler:           r,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	ln, err := net.Listen("tcp", srv.Addr)
 	if err != nil {
 		panic(err)
 	}
-	select {}
+	go func() {
+		L.Info().Str("Addr", srv.Addr).Msg("Starting fake server")
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			L.Fatal().Err(err).Msg("Fake server failed")
+		}
+	}()
+	ready.Store(true)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	L.Info().Msg("Shutdown signal received, stopping fake server")
+	ready.Store(false)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		L.Error().Err(err).Msg("Fake server graceful shutdown failed")
+		return
+	}
+	L.Info().Msg("Fake server stopped")
 }
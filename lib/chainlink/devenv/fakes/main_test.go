@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestConcurrentAccess hammers all endpoints that touch fake state concurrently, run with -race
+func TestConcurrentAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := httptest.NewServer(newRouter(NewState("200")))
+	defer srv.Close()
+
+	const workers, requests = 8, 50
+	paths := []struct {
+		method string
+		path   func(i int) string
+	}{
+		{method: http.MethodPost, path: func(i int) string { return fmt.Sprintf("/trigger_deviation?result=%d", i) }},
+		{method: http.MethodPost, path: func(int) string { return "/ea" }},
+		{method: http.MethodGet, path: func(int) string { return "/value" }},
+		{method: http.MethodPost, path: func(int) string { return "/set_latency?duration=0s" }},
+		{method: http.MethodGet, path: func(int) string { return "/metrics" }},
+		{method: http.MethodPost, path: func(i int) string {
+			return fmt.Sprintf("/set_profile?kind=random_walk&start=%d&step=1&min=0&max=1000&interval_sec=1&duration_sec=1", i)
+		}},
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*len(paths)*requests)
+	for w := 0; w < workers; w++ {
+		for _, p := range paths {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < requests; i++ {
+					req, err := http.NewRequest(p.method, srv.URL+p.path(i), nil)
+					if err != nil {
+						errs <- err
+						return
+					}
+					resp, err := http.DefaultClient.Do(req)
+					if err != nil {
+						errs <- err
+						return
+					}
+					_ = resp.Body.Close()
+					if resp.StatusCode != http.StatusOK {
+						errs <- fmt.Errorf("%s %s: unexpected status %d", p.method, req.URL.Path, resp.StatusCode)
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestUnseeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := httptest.NewServer(newRouter(NewState("")))
+	defer srv.Close()
+
+	post := func(path string) (int, string) {
+		resp, err := http.Post(srv.URL+path, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+	if status, body := post("/ea"); status != http.StatusServiceUnavailable || !strings.Contains(body, "no value is set") {
+		t.Fatalf("unseeded /ea: got %d %s, want %d", status, body, http.StatusServiceUnavailable)
+	}
+	if status, _ := post("/trigger_deviation?result=5"); status != http.StatusOK {
+		t.Fatalf("/trigger_deviation: got %d", status)
+	}
+	if status, body := post("/ea"); status != http.StatusOK || !strings.Contains(body, `"result":"5"`) {
+		t.Fatalf("seeded /ea: got %d %s", status, body)
+	}
+}
+
+func TestGinMode(t *testing.T) {
+	tests := []struct {
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{env: "", want: gin.ReleaseMode},
+		{env: gin.DebugMode, want: gin.DebugMode},
+		{env: "verbose", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Setenv(gin.EnvGinMode, tc.env)
+		mode, err := ginMode()
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("GIN_MODE=%q: unexpected error: %v", tc.env, err)
+		}
+		if mode != tc.want {
+			t.Errorf("GIN_MODE=%q: got mode %q, want %q", tc.env, mode, tc.want)
+		}
+	}
+}
+
+func TestJuelsPerFeeCoin(t *testing.T) {
+	tests := []struct {
+		mode    string
+		baseFee *big.Int
+		want    string
+	}{
+		{mode: JuelsModeConstant, baseFee: big.NewInt(50e9), want: DefaultJuelsPerLinkRatio},
+		{mode: JuelsModeGasLinked, want: DefaultJuelsPerLinkRatio},
+		{mode: JuelsModeGasLinked, baseFee: big.NewInt(1e9), want: "15"},
+		{mode: JuelsModeGasLinked, baseFee: big.NewInt(100e9), want: "1500"},
+		{mode: JuelsModeGasLinked, baseFee: big.NewInt(1), want: "1"},
+	}
+	for _, tc := range tests {
+		if got := juelsPerFeeCoin(tc.mode, tc.baseFee); got != tc.want {
+			t.Errorf("mode %s, base fee %s: got %s, want %s", tc.mode, tc.baseFee, got, tc.want)
+		}
+	}
+	if err := validateJuelsMode("gas"); err == nil {
+		t.Error("unknown juels mode must be rejected")
+	}
+	if _, err := parseBaseFee("0"); err == nil {
+		t.Error("zero base fee must be rejected")
+	}
+}
+
+func TestLatency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := httptest.NewServer(newRouter(NewState("200")))
+	defer srv.Close()
+
+	post := func(path string) int {
+		resp, err := http.Post(srv.URL+path, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := post("/set_latency?duration=150ms"); code != http.StatusOK {
+		t.Fatalf("set_latency: unexpected status %d", code)
+	}
+	start := time.Now()
+	if code := post("/ea"); code != http.StatusOK {
+		t.Fatalf("ea: unexpected status %d", code)
+	}
+	if took := time.Since(start); took < 150*time.Millisecond {
+		t.Errorf("ea responded in %s, before injected latency", took)
+	}
+	for _, d := range []string{"", "1", "-1s", "6m"} {
+		if code := post("/set_latency?duration=" + d); code != http.StatusBadRequest {
+			t.Errorf("latency %q: got status %d, want %d", d, code, http.StatusBadRequest)
+		}
+	}
+	if d, err := parseLatency("1.25s"); err != nil || d != 1250*time.Millisecond {
+		t.Errorf("sub-second latency: got %s, %v", d, err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := httptest.NewServer(newRouter(NewState("200")))
+	defer srv.Close()
+
+	for _, path := range []string{"/ea", "/ea", "/set_latency?duration=1.5s", "/unknown"} {
+		resp, err := http.Post(srv.URL+path, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	// a client giving up during injected latency is counted as an injected error
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/ea", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := http.DefaultClient.Do(req); err == nil {
+		_ = resp.Body.Close()
+		t.Fatal("ea must not respond before injected latency passed")
+	}
+
+	want := []string{
+		`fake_requests_total{method="POST",path="/ea",status="200"} 2`,
+		`fake_requests_total{method="POST",path="/set_latency",status="200"} 1`,
+		`fake_requests_total{method="POST",path="unmatched",status="404"} 1`,
+		`fake_request_duration_seconds_count{path="/ea"}`,
+		`fake_result_value 200`,
+		`fake_injected_latency_seconds 1.5`,
+		`fake_injected_errors_total{kind="latency_timeout"} 1`,
+		`fake_requests_total{method="POST",path="/ea",status="499"} 1`,
+	}
+	var body string
+	// the aborted request is counted by the middleware once its handler returns, so metrics are polled until it is
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		resp, err := http.Get(srv.URL + "/metrics")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body = string(b)
+		if strings.Contains(body, `fake_requests_total{method="POST",path="/ea",status="499"} 1`) {
+			break
+		}
+	}
+	for _, w := range want {
+		if !strings.Contains(body, w) {
+			t.Errorf("metrics have no %q", w)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// MetricsNamespace prefixes all fake metrics, ex.: fake_requests_total
+	MetricsNamespace = "fake"
+	// InjectedErrorLatencyTimeout is an EA request a client gave up on during injected latency
+	InjectedErrorLatencyTimeout = "latency_timeout"
+	// unmatchedPath labels requests to unknown routes, so random paths don't blow up label cardinality
+	unmatchedPath = "unmatched"
+)
+
+// requestDurationBuckets cover fast control requests and EA responses delayed by injected latency, in seconds
+var requestDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 7.5, 10, 30, 60}
+
+// Metrics are fake's Prometheus metrics, every router has its own registry, so routers in tests don't collide
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	injectedErrors  *prometheus.CounterVec
+}
+
+// newMetrics registers fake metrics, current result and injected latency are read from s on every scrape
+func newMetrics(s *State) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "requests_total",
+			Help:      "Requests served by route, method and status",
+		}, []string{"path", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "request_duration_seconds",
+			Help:      "Request duration by route, including injected latency",
+			Buckets:   requestDurationBuckets,
+		}, []string{"path"}),
+		injectedErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "injected_errors_total",
+			Help:      "EA requests the fake failed on purpose by kind, ex.: latency_timeout",
+		}, []string{"kind"}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.requestDuration,
+		m.injectedErrors,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "result_value",
+			Help:      "Value EA currently returns, NaN if it's not a number",
+		}, func() float64 {
+			return resultValue(s.Result())
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "injected_latency_seconds",
+			Help:      "Latency injected into EA responses",
+		}, func() float64 {
+			return s.Latency().Seconds()
+		}),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	// injected errors are exported as 0 before the first one, so rates can be computed from the start
+	m.injectedErrors.WithLabelValues(InjectedErrorLatencyTimeout)
+	return m
+}
+
+// middleware counts and times every request by its route pattern
+func (m *Metrics) middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		path := ctx.FullPath()
+		if path == "" {
+			path = unmatchedPath
+		}
+		m.requests.WithLabelValues(path, ctx.Request.Method, strconv.Itoa(ctx.Writer.Status())).Inc()
+		m.requestDuration.WithLabelValues(path).Observe(time.Since(start).Seconds())
+	}
+}
+
+// handler serves metrics in Prometheus text or OpenMetrics format, whichever the scraper asks for
+func (m *Metrics) handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+}
+
+// injectedError counts an EA request the fake failed on purpose
+func (m *Metrics) injectedError(kind string) {
+	m.injectedErrors.WithLabelValues(kind).Inc()
+}
+
+// resultValue parses EA result for the result gauge, results are integers but any float is accepted
+func resultValue(result string) float64 {
+	v, err := strconv.ParseFloat(result, 64)
+	if err != nil {
+		return math.NaN()
+	}
+	return v
+}
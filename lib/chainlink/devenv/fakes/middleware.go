@@ -0,0 +1,26 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultMiddleware is applied to all fake routes before any custom middleware
+func defaultMiddleware() []gin.HandlerFunc {
+	return []gin.HandlerFunc{gin.Recovery(), requestLogger()}
+}
+
+// requestLogger logs method, path, status and latency of every request
+func requestLogger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		L.Debug().
+			Str("Method", ctx.Request.Method).
+			Str("Path", ctx.Request.URL.Path).
+			Int("Status", ctx.Writer.Status()).
+			Dur("Latency", time.Since(start)).
+			Msg("Request")
+	}
+}
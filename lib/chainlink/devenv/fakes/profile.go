@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+)
+
+const (
+	ProfileRandomWalk = "random_walk"
+)
+
+// Profile changes EA result over time instead of a single discrete value
+type Profile struct {
+	Kind        string `form:"kind" json:"kind"`
+	Start       int64  `form:"start" json:"start"`
+	Step        int64  `form:"step" json:"step"`
+	Min         int64  `form:"min" json:"min"`
+	Max         int64  `form:"max" json:"max"`
+	IntervalSec int64  `form:"interval_sec" json:"interval_sec"`
+	DurationSec int64  `form:"duration_sec" json:"duration_sec"`
+}
+
+// Validate checks profile parameters
+func (p *Profile) Validate() error {
+	if p.Kind != ProfileRandomWalk {
+		return fmt.Errorf("unknown profile kind: %q, supported: %s", p.Kind, ProfileRandomWalk)
+	}
+	if p.Step <= 0 || p.IntervalSec <= 0 || p.DurationSec <= 0 {
+		return errors.New("step, interval_sec and duration_sec must be positive")
+	}
+	if p.Min > p.Max || p.Start < p.Min || p.Start > p.Max {
+		return fmt.Errorf("start %d must be within [%d, %d]", p.Start, p.Min, p.Max)
+	}
+	return nil
+}
+
+// Run changes state result every interval until ctx is done, the last value is kept after that
+func (p *Profile) Run(ctx context.Context, s *State) {
+	value := p.Start
+	s.setProfileResult(ctx, strconv.FormatInt(value, 10))
+	ticker := time.NewTicker(time.Duration(p.IntervalSec) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			L.Info().Str("Result", s.Result()).Msg("EA profile is finished")
+			return
+		case <-ticker.C:
+			//nolint:gosec // no need for crypto rand in fakes
+			value += rand.Int64N(2*p.Step+1) - p.Step
+			value = max(p.Min, min(p.Max, value))
+			if !s.setProfileResult(ctx, strconv.FormatInt(value, 10)) {
+				return
+			}
+			L.Debug().Int64("Result", value).Msg("EA profile value changed")
+		}
+	}
+}
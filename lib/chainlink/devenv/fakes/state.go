@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// State is fake's mutable state shared between handlers and running EA profile
+type State struct {
+	mu          sync.RWMutex
+	result      string
+	stopProfile context.CancelFunc
+	// juelsMode and baseFee select what juelsPerFeeCoinSource returns
+	juelsMode string
+	baseFee   *big.Int
+	// latency delays /ea responses, ex.: right past MaxDurationObservation to get observations dropped
+	latency time.Duration
+}
+
+// NewState creates fake state with initial EA result
+func NewState(result string) *State {
+	return &State{result: result, juelsMode: JuelsModeConstant}
+}
+
+// SetJuelsMode changes how juels per fee coin ratio is derived
+func (s *State) SetJuelsMode(mode string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.juelsMode = mode
+}
+
+// SetBaseFee injects the current block base fee gas_linked juels mode derives the ratio from
+func (s *State) SetBaseFee(fee *big.Int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.baseFee = new(big.Int).Set(fee)
+}
+
+// SetLatency changes how long /ea waits before responding
+func (s *State) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// Latency returns injected /ea latency
+func (s *State) Latency() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latency
+}
+
+// Juels returns current juels per fee coin ratio
+func (s *State) Juels() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return juelsPerFeeCoin(s.juelsMode, s.baseFee)
+}
+
+// Result returns current EA result
+func (s *State) Result() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.result
+}
+
+// SetResult sets EA result and stops running profile, if any
+func (s *State) SetResult(result string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelProfile()
+	s.result = result
+}
+
+// StartProfile stops running profile, if any, and starts a new one
+func (s *State) StartProfile(p *Profile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelProfile()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.DurationSec)*time.Second)
+	s.stopProfile = cancel
+	go p.Run(ctx, s)
+}
+
+// setProfileResult sets result from a profile, it's ignored if profile was already stopped
+func (s *State) setProfileResult(ctx context.Context, result string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ctx.Err() != nil {
+		return false
+	}
+	s.result = result
+	return true
+}
+
+func (s *State) cancelProfile() {
+	if s.stopProfile != nil {
+		s.stopProfile()
+		s.stopProfile = nil
+	}
+}
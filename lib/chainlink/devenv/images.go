@@ -0,0 +1,129 @@
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+)
+
+var imageDigestRe = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ValidateImageRef checks digest format of an image pinned by digest, ex.: repo@sha256:<64 hex chars>, tag references are not checked
+func ValidateImageRef(ref string) error {
+	_, digest, ok := strings.Cut(ref, "@")
+	if !ok {
+		return nil
+	}
+	if !imageDigestRe.MatchString(digest) {
+		return fmt.Errorf("invalid image digest in %s, expected sha256:<64 lowercase hex chars>", ref)
+	}
+	return nil
+}
+
+// imageRefs returns pointers to all images used by environment containers, empty images use component defaults
+func imageRefs(in *Cfg) map[string]*string {
+	refs := make(map[string]*string)
+	for i, bc := range in.Blockchains {
+		refs[fmt.Sprintf("blockchain %d", i)] = &bc.Image
+	}
+	for i, f := range in.Fakes() {
+		refs[fmt.Sprintf("fake server %d", i)] = &f.Image
+	}
+	if in.TelemetryIngress != nil {
+		refs["telemetry ingress"] = &in.TelemetryIngress.Image
+	}
+	for _, n := range in.NodeSets {
+		if n.DbInput != nil {
+			refs[fmt.Sprintf("node set %s db", n.Name)] = &n.DbInput.Image
+		}
+		for i, spec := range n.NodeSpecs {
+			refs[fmt.Sprintf("node set %s node %d", n.Name, i)] = &spec.Node.Image
+		}
+	}
+	for name, ref := range refs {
+		if *ref == "" {
+			delete(refs, name)
+		}
+	}
+	return refs
+}
+
+// pinImages validates image references and, if pin_image_digests is set, replaces tags with digests they currently resolve to,
+// so env-out.toml records the exact images used
+func pinImages(ctx context.Context, in *Cfg) error {
+	refs := imageRefs(in)
+	for name, ref := range refs {
+		if err := ValidateImageRef(*ref); err != nil {
+			return fmt.Errorf("invalid %s image: %w", name, err)
+		}
+	}
+	var dc *client.Client
+	if in.PinImageDigests {
+		var err error
+		dc, err = client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+		if err != nil {
+			return fmt.Errorf("failed to create docker client: %w", err)
+		}
+		defer dc.Close()
+	}
+	for name, ref := range refs {
+		if dc != nil && !strings.Contains(*ref, "@") {
+			pinned, err := resolveImageDigest(ctx, dc, *ref)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %s image digest: %w", name, err)
+			}
+			*ref = pinned
+		}
+		ctxLogger(ctx).Info().Str("Component", name).Str("Image", *ref).Bool("Pinned", strings.Contains(*ref, "@")).Msg("Using image")
+	}
+	return nil
+}
+
+// resolveImageDigest returns repo@digest reference for a tag, image is pulled if it's not present locally.
+// Locally built images have no registry digest, their tag is kept
+func resolveImageDigest(ctx context.Context, dc *client.Client, ref string) (string, error) {
+	if err := pullImage(ctx, dc, ref); err != nil {
+		return "", err
+	}
+	info, err := dc.ImageInspect(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image %s: %w", ref, err)
+	}
+	repo := ref
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		repo = ref[:i]
+	}
+	for _, d := range info.RepoDigests {
+		if strings.HasPrefix(d, repo+"@") {
+			return d, nil
+		}
+	}
+	ctxLogger(ctx).Warn().Str("Image", ref).Msg("Image has no registry digest, it's probably built locally, keeping the tag")
+	return ref, nil
+}
+
+// pullImage pulls image if it's not present locally
+func pullImage(ctx context.Context, dc *client.Client, ref string) error {
+	_, err := dc.ImageInspect(ctx, ref)
+	if err == nil {
+		return nil
+	}
+	if !errdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to inspect image %s: %w", ref, err)
+	}
+	rc, err := dc.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", ref, err)
+	}
+	defer rc.Close()
+	if _, err := io.Copy(io.Discard, rc); err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", ref, err)
+	}
+	return nil
+}
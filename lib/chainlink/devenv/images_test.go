@@ -0,0 +1,34 @@
+package devenv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateImageRef(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		name    string
+		ref     string
+		wantErr bool
+	}{
+		{name: "tag", ref: "public.ecr.aws/chainlink/chainlink:2.26.0"},
+		{name: "digest", ref: "public.ecr.aws/chainlink/chainlink@" + digest},
+		{name: "tag and digest", ref: "postgres:15.0@" + digest},
+		{name: "short digest", ref: "postgres@sha256:abc", wantErr: true},
+		{name: "unknown algorithm", ref: "postgres@md5:" + strings.Repeat("a", 64), wantErr: true},
+		{name: "uppercase digest", ref: "postgres@sha256:" + strings.Repeat("A", 64), wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateImageRef(tc.ref)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/fake"
 
 	nodeset "github.com/smartcontractkit/chainlink-testing-framework/framework/components/simple_node_set"
+
+	"github.com/smartcontractkit/chainlink/devenv/products"
 )
 
 // Product describes a minimal set of methods that each legacy product must implement
@@ -20,11 +22,39 @@ type Product interface {
 		ctx context.Context,
 		bc *blockchain.Input,
 	) (string, error)
-	// ConfigureJobsAndContracts configures both on-chain and off-chain parts of a product
+	// ConfigureJobsAndContracts configures both on-chain and off-chain parts of a product,
+	// jobs select the fake server they use by its index in fs, the first node set in ns runs product jobs
 	ConfigureJobsAndContracts(
 		ctx context.Context,
-		fs *fake.Input,
+		fs []*fake.Input,
 		bc *blockchain.Input,
-		ns *nodeset.Input,
+		ns []*nodeset.Input,
 	) error
+	// VerifyLive waits until product reports its first on-chain result, it's called after outputs are stored
+	VerifyLive(ctx context.Context, bc *blockchain.Input) error
+	// Destroy removes product resources that live outside of environment containers, it's called before containers are removed
+	Destroy(ctx context.Context) error
+}
+
+// JobDistributorUser is implemented by products that can propose jobs through Job Distributor,
+// it's called before ConfigureJobsAndContracts if [jd] is configured
+type JobDistributorUser interface {
+	// UseJobDistributor sets JD client and JD WSRPC URL reachable from node containers
+	UseJobDistributor(jd products.JobDistributor, wsrpcURL string)
+}
+
+// RoundsVerifier is implemented by products that report rounds, it's called after VerifyLive if require_rounds is set,
+// so up returns only once the product keeps reporting
+type RoundsVerifier interface {
+	// VerifyRounds waits until the product reports n rounds and fails naming how many rounds were seen if it falls short
+	VerifyRounds(ctx context.Context, bc *blockchain.Input, n int) error
+	// ValidateRounds checks n rounds can be reported before verification times out, it's called once product config is loaded
+	ValidateRounds(n int) error
+}
+
+// TelemetryIngressUser is implemented by products that can send telemetry, it's called before CL node config is generated
+// if [telemetry_ingress] is configured
+type TelemetryIngressUser interface {
+	// UseTelemetryIngress points nodes and jobs at ingress URL reachable from node containers, serverPubKey is ingress CSA key
+	UseTelemetryIngress(url, serverPubKey string)
 }
@@ -0,0 +1,122 @@
+package devenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// SaveEnvironmentLogs writes stdout and stderr of fake server and blockchain containers to dir, one <container>.log file each,
+// CL node logs are saved by framework.SaveContainerLogs. A container that can't be read doesn't stop the others
+func SaveEnvironmentLogs(ctx context.Context, in *Cfg, dir string) ([]string, error) {
+	names := environmentContainers(in)
+	if len(names) == 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create logs directory %s: %w", dir, err)
+	}
+	dc, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer dc.Close()
+	files := make([]string, 0, len(names))
+	var errs []error
+	for _, name := range names {
+		path := filepath.Join(dir, name+".log")
+		if err := saveContainerLogs(ctx, dc, name, path); err != nil {
+			errs = append(errs, fmt.Errorf("could not save logs of container %s: %w", name, err))
+			continue
+		}
+		ctxLogger(ctx).Info().Str("Container", name).Str("Path", path).Msg("Container logs saved")
+		files = append(files, path)
+	}
+	return files, errors.Join(errs...)
+}
+
+// ContainerLogs returns demultiplexed stdout and stderr of a container, ex.: to check what a node logged during a test
+func ContainerLogs(ctx context.Context, name string) ([]byte, error) {
+	dc, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer dc.Close()
+	rc, err := dc.ContainerLogs(ctx, name, container.LogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return nil, fmt.Errorf("could not read logs of container %s: %w", name, err)
+	}
+	defer rc.Close()
+	var buf bytes.Buffer
+	if _, err := stdcopy.StdCopy(&buf, &buf, rc); err != nil {
+		return nil, fmt.Errorf("could not read logs of container %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// saveContainerLogs writes demultiplexed stdout and stderr of the container to path
+func saveContainerLogs(ctx context.Context, dc *client.Client, name, path string) error {
+	rc, err := dc.ContainerLogs(ctx, name, container.LogsOptions{ShowStdout: true, ShowStderr: true, Timestamps: true})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = stdcopy.StdCopy(f, f, rc)
+	return err
+}
+
+// environmentContainers returns names of fake server and blockchain containers recorded in outputs,
+// names are taken from docker URLs when outputs don't record them
+func environmentContainers(in *Cfg) []string {
+	if in == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, f := range in.Fakes() {
+		if f.Out != nil {
+			add(urlHost(f.Out.BaseURLDocker))
+		}
+	}
+	for _, bc := range in.Blockchains {
+		if bc.Out == nil {
+			continue
+		}
+		if bc.Out.ContainerName != "" {
+			add(bc.Out.ContainerName)
+			continue
+		}
+		for _, n := range bc.Out.Nodes {
+			add(urlHost(n.InternalHTTPUrl))
+		}
+	}
+	return names
+}
+
+// urlHost returns host of the URL without port, it's empty if URL can't be parsed
+func urlHost(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
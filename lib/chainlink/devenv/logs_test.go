@@ -0,0 +1,25 @@
+package devenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/fake"
+)
+
+func TestEnvironmentContainers(t *testing.T) {
+	in := &Cfg{
+		FakeServer:  &fake.Input{Out: &fake.Output{BaseURLDocker: "http://fake-server:9111"}},
+		FakeServers: []*fake.Input{{Out: &fake.Output{BaseURLDocker: "http://fake-server-1:9112"}}, {}},
+		Blockchains: []*blockchain.Input{
+			{Out: &blockchain.Output{ContainerName: "blockchain-src"}},
+			{Out: &blockchain.Output{Nodes: []*blockchain.Node{{InternalHTTPUrl: "http://blockchain-dst:8555"}}}},
+			{},
+		},
+	}
+	require.Equal(t, []string{"fake-server", "fake-server-1", "blockchain-src", "blockchain-dst"}, environmentContainers(in))
+	require.Empty(t, environmentContainers(&Cfg{}))
+	require.Nil(t, environmentContainers(nil))
+}
@@ -0,0 +1,93 @@
+package products
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+)
+
+const (
+	// EnvVarArtifactsDir is the root generated files go to, ex.: CTF_ARTIFACTS_DIR=/tmp/ctf, current directory is used if unset
+	EnvVarArtifactsDir = "CTF_ARTIFACTS_DIR"
+	// EnvVarRunID names the per-run subdirectory of CTF_ARTIFACTS_DIR, ex.: CTF_RUN_ID=pr-123, DefaultRunID is used if unset
+	EnvVarRunID = "CTF_RUN_ID"
+	// DefaultRunID is the run subdirectory used if CTF_RUN_ID is not set, so later commands find outputs of the last run
+	DefaultRunID = "default"
+)
+
+// Artifacts sets where generated files go, ex.: [artifacts] in env.toml, CTF_ARTIFACTS_DIR and CTF_RUN_ID take precedence
+type Artifacts struct {
+	// Dir is the artifacts root, current directory is used if unset
+	Dir string `toml:"dir"`
+	// RunID is the per-run subdirectory of Dir, DefaultRunID if unset
+	RunID string `toml:"run_id"`
+}
+
+// Apply exports config values as CTF_ARTIFACTS_DIR and CTF_RUN_ID unless they are already set,
+// so every helper resolving artifact paths sees the same directory, nil config is a no-op
+func (a *Artifacts) Apply() error {
+	if a == nil {
+		return nil
+	}
+	if err := os.Setenv(EnvVarArtifactsDir, defaults.EnvOr(EnvVarArtifactsDir, a.Dir)); err != nil {
+		return err
+	}
+	return os.Setenv(EnvVarRunID, defaults.EnvOr(EnvVarRunID, a.RunID))
+}
+
+// ArtifactsDir returns the directory generated files of the current run go to, ex.: /tmp/ctf/pr-123,
+// current directory if CTF_ARTIFACTS_DIR is not set
+func ArtifactsDir() string {
+	root := os.Getenv(EnvVarArtifactsDir)
+	if root == "" {
+		return "."
+	}
+	return filepath.Join(root, defaults.EnvOr(EnvVarRunID, DefaultRunID))
+}
+
+// ArtifactPath resolves where a generated file or directory goes, ex.: env-out.toml or job specs.
+// If CTF_ARTIFACTS_DIR is not set, name is absolute or already in ArtifactsDir name is returned as is,
+// otherwise it's placed in ArtifactsDir. Leading ".." of name point at the directory the environment runs in,
+// ex.: ../../env-out.toml of tests, so a relative ArtifactsDir is resolved from there and all runs share one run directory
+func ArtifactPath(name string) string {
+	if os.Getenv(EnvVarArtifactsDir) == "" || filepath.IsAbs(name) {
+		return name
+	}
+	dir := ArtifactsDir()
+	if within(dir, name) {
+		return name
+	}
+	up, rest := "", filepath.Clean(name)
+	for rest == ".." || strings.HasPrefix(rest, ".."+string(filepath.Separator)) {
+		up = filepath.Join(up, "..")
+		rest = strings.TrimPrefix(strings.TrimPrefix(rest, ".."), string(filepath.Separator))
+	}
+	if filepath.IsAbs(dir) {
+		return filepath.Join(dir, rest)
+	}
+	if within(dir, rest) {
+		return filepath.Join(up, rest)
+	}
+	return filepath.Join(up, dir, rest)
+}
+
+// within reports whether path is dir or inside it, both are made absolute first, so paths with ".." are compared correctly
+func within(dir, path string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// DefaultOutputPath returns env-out.toml of the current run, commands working on a running environment read it
+func DefaultOutputPath() string {
+	return ArtifactPath(DefaultOutputFilePath)
+}
@@ -0,0 +1,73 @@
+package products
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactPath(t *testing.T) {
+	t.Setenv(EnvVarArtifactsDir, "")
+	require.Equal(t, ".", ArtifactsDir())
+	require.Equal(t, "env-out.toml", ArtifactPath("env-out.toml"))
+	require.Equal(t, "../../env-out.toml", ArtifactPath("../../env-out.toml"))
+
+	t.Setenv(EnvVarArtifactsDir, "ctf")
+	require.Equal(t, filepath.Join("ctf", DefaultRunID), ArtifactsDir())
+	t.Setenv(EnvVarRunID, "pr-1")
+	tests := map[string]string{
+		"env-out.toml":                "ctf/pr-1/env-out.toml",
+		"../../env-out.toml":          "../../ctf/pr-1/env-out.toml",
+		"../../ctf/pr-1/env-out.toml": "../../ctf/pr-1/env-out.toml",
+		"../ctf-out/env-out.toml":     "../ctf/pr-1/ctf-out/env-out.toml",
+		"logs-TestLoad/clean/metrics": "ctf/pr-1/logs-TestLoad/clean/metrics",
+		"ctf/pr-1/env-out.toml":       "ctf/pr-1/env-out.toml",
+		"/tmp/job-specs":              "/tmp/job-specs",
+		"configs/../env-default.toml": "ctf/pr-1/env-default.toml",
+	}
+	for name, expected := range tests {
+		require.Equal(t, filepath.FromSlash(expected), ArtifactPath(name), name)
+	}
+	require.Equal(t, filepath.FromSlash("ctf/pr-1/env-out.toml"), DefaultOutputPath())
+
+	// an absolute artifacts directory is used as is, wherever tests run from
+	root := t.TempDir()
+	t.Setenv(EnvVarArtifactsDir, root)
+	require.Equal(t, filepath.Join(root, "pr-1", "env-out.toml"), ArtifactPath("../../env-out.toml"))
+	require.Equal(t, filepath.Join(root, "pr-1", "env-out.toml"), ArtifactPath(filepath.Join(root, "pr-1", "env-out.toml")))
+}
+
+func TestOutputPathArtifactsDir(t *testing.T) {
+	t.Setenv(EnvVarArtifactsDir, "ctf")
+	t.Setenv(EnvVarRunID, "pr-1")
+	tests := map[string]string{
+		"env.toml,overrides.toml": "ctf/pr-1/env-out.toml",
+		"-":                       "ctf/pr-1/env-out.toml",
+		"ctf/pr-1/env-out.toml":   "ctf/pr-1/env-out-out.toml",
+		"env-cache.toml":          "env-cache.toml",
+	}
+	for configs, expected := range tests {
+		t.Setenv(EnvVarTestConfigs, configs)
+		got, err := OutputPath()
+		require.NoError(t, err)
+		require.Equal(t, filepath.FromSlash(expected), got, configs)
+	}
+}
+
+func TestArtifactsApply(t *testing.T) {
+	t.Setenv(EnvVarArtifactsDir, "")
+	t.Setenv(EnvVarRunID, "from-env")
+	require.NoError(t, (*Artifacts)(nil).Apply())
+	require.NoError(t, (&Artifacts{Dir: "ctf", RunID: "from-config"}).Apply())
+	require.Equal(t, filepath.Join("ctf", "from-env"), ArtifactsDir())
+}
+
+func TestFSStoreCreatesDirs(t *testing.T) {
+	dir := t.TempDir()
+	s := NewFSStore(dir)
+	require.NoError(t, s.Write(filepath.Join("run", "env-out.toml"), []byte("a = 1")))
+	data, err := s.Read(filepath.Join("run", "env-out.toml"))
+	require.NoError(t, err)
+	require.Equal(t, "a = 1", string(data))
+}
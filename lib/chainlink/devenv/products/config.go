@@ -1,10 +1,15 @@
 package products
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
-	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/pelletier/go-toml/v2"
 	"github.com/rs/zerolog"
@@ -13,63 +18,166 @@ import (
 
 const (
 	EnvVarTestConfigs = "CTF_CONFIGS"
+	// StdinConfigPath is a special CTF_CONFIGS entry that reads TOML from stdin, ex.: CTF_CONFIGS=env.toml,-
+	StdinConfigPath = "-"
+	// DefaultOutputFilePath is the output file name used when the base config is read from stdin.
+	DefaultOutputFilePath = "env-out.toml"
+	// EnvVarStrictConfigs enables strict TOML decoding, unknown keys are reported as errors, ex.: CTF_STRICT_CONFIGS=true
+	EnvVarStrictConfigs = "CTF_STRICT_CONFIGS"
 )
 
 var L = log.Output(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.DebugLevel).With().Fields(map[string]any{"component": "product_config"}).Logger()
 
-func Load[T any]() (*T, error) {
+var (
+	stdinOnce sync.Once
+	stdinData []byte
+	stdinErr  error
+)
+
+// ReadStdin reads stdin once and caches the result, so both infra and product configs can be loaded from the same stdin input.
+func ReadStdin() ([]byte, error) {
+	stdinOnce.Do(func() {
+		stdinData, stdinErr = io.ReadAll(os.Stdin)
+	})
+	return stdinData, stdinErr
+}
+
+// ConfigPaths splits CTF_CONFIGS value into paths, stdin ("-") can be used only once.
+func ConfigPaths(configs string) ([]string, error) {
+	paths := strings.Split(configs, ",")
+	stdinEntries := 0
+	for _, path := range paths {
+		if path == StdinConfigPath {
+			stdinEntries++
+		}
+	}
+	if stdinEntries > 1 {
+		return nil, errors.New("stdin config path \"-\" can be specified only once")
+	}
+	return paths, nil
+}
+
+// Load loads product TOML configurations from CTF_CONFIGS, "-" entry is read from stdin and merged in its listed position.
+// Configs are read from the optional store, filesystem is used by default.
+func Load[T any](store ...ConfigStore) (*T, error) {
 	var config T
-	paths := strings.Split(os.Getenv(EnvVarTestConfigs), ",")
+	s := SelectStore(".", store)
+	paths, err := ConfigPaths(os.Getenv(EnvVarTestConfigs))
+	if err != nil {
+		return nil, err
+	}
 	for _, path := range paths {
-		data, err := os.ReadFile(path)
+		var data []byte
+		if path == StdinConfigPath {
+			data, err = ReadStdin()
+		} else {
+			data, err = s.Read(path)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to read product config file path %s: %w", path, err)
 		}
 		L.Trace().Str("ProductConfig", string(data)).Send()
 
-		decoder := toml.NewDecoder(strings.NewReader(string(data)))
-
-		if err := decoder.Decode(&config); err != nil {
-			return nil, fmt.Errorf("failed to decode TOML config, strict mode: %w", err)
+		if err := DecodeTOML(path, data, &config); err != nil {
+			return nil, err
 		}
 	}
 	return &config, nil
 }
 
-// Store writes config to a file, adds -cache.toml suffix if it's an initial configuration.
-func Store[T any](path string, cfg *T) error {
-	baseConfigPath, err := BaseConfigPath(EnvVarTestConfigs)
+// DecodeTOML decodes TOML data from path into cfg. If CTF_STRICT_CONFIGS=true unknown keys inside of cfg's own sections
+// are reported with their full key and file, unknown top-level sections are allowed because infra and product configs share files.
+func DecodeTOML[T any](path string, data []byte, cfg *T) error {
+	decoder := toml.NewDecoder(bytes.NewReader(data))
+	if os.Getenv(EnvVarStrictConfigs) == "true" {
+		decoder.DisallowUnknownFields()
+	}
+	err := decoder.Decode(cfg)
+	var sme *toml.StrictMissingError
+	if !errors.As(err, &sme) {
+		if err != nil {
+			return fmt.Errorf("failed to decode TOML config %s: %w", path, err)
+		}
+		return nil
+	}
+	known := tomlSections(reflect.TypeOf(cfg).Elem())
+	unknown := make([]string, 0)
+	for _, e := range sme.Errors {
+		key := e.Key()
+		if len(key) > 1 && known[key[0]] {
+			unknown = append(unknown, strings.Join(key, "."))
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown keys in TOML config %s, strict mode: %s", path, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// tomlSections returns top-level TOML keys of a struct type
+func tomlSections(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return keys
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		keys[name] = true
+	}
+	return keys
+}
+
+// Store appends config to an output file, adds -out.toml suffix if it's an initial configuration.
+// Output is written to the optional store, filesystem rooted at path is used by default.
+func Store[T any](path string, cfg *T, store ...ConfigStore) error {
+	outCacheName, err := OutputPath()
 	if err != nil {
 		return err
 	}
-	newCacheName := strings.ReplaceAll(baseConfigPath, ".toml", "")
-	var outCacheName string
-	if strings.Contains(newCacheName, "cache") {
-		L.Info().Str("Cache", baseConfigPath).Msg("Cache file already exists, overriding")
-		outCacheName = baseConfigPath
-	} else {
-		outCacheName = strings.ReplaceAll(baseConfigPath, ".toml", "") + "-out.toml"
+	if strings.Contains(outCacheName, "cache") {
+		L.Info().Str("Cache", outCacheName).Msg("Cache file already exists, overriding")
 	}
 	L.Info().Str("OutputFile", outCacheName).Msg("Storing configuration output")
 	d, err := toml.Marshal(cfg)
 	if err != nil {
 		return err
 	}
-	f, err := os.OpenFile(filepath.Join(path, outCacheName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-	if err != nil {
+	s := SelectStore(path, store)
+	existing, err := s.Read(outCacheName)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
-	defer f.Close()
-	if _, err := f.Write(d); err != nil {
-		return err
+	return s.Write(outCacheName, append(existing, d...))
+}
+
+// OutputPath returns the file outputs are written to for the current CTF_CONFIGS, ex.: env.toml -> env-out.toml,
+// it's placed in ArtifactsDir if CTF_ARTIFACTS_DIR is set
+func OutputPath() (string, error) {
+	baseConfigPath, err := BaseConfigPath(EnvVarTestConfigs)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case baseConfigPath == StdinConfigPath:
+		return ArtifactPath(DefaultOutputFilePath), nil
+	case strings.Contains(strings.ReplaceAll(baseConfigPath, ".toml", ""), "cache"):
+		return baseConfigPath, nil
+	default:
+		return ArtifactPath(strings.ReplaceAll(baseConfigPath, ".toml", "") + "-out.toml"), nil
 	}
-	return nil
 }
 
 // LoadOutput loads config output file from path.
-func LoadOutput[T any](path string) (*T, error) {
+func LoadOutput[T any](path string, store ...ConfigStore) (*T, error) {
 	_ = os.Setenv(EnvVarTestConfigs, path)
-	return Load[T]()
+	return Load[T](store...)
 }
 
 // BaseConfigPath returns base config path, ex. env.toml,overrides.toml -> env.toml.
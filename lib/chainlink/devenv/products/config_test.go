@@ -0,0 +1,28 @@
+package products
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type strictCfg struct {
+	OCR2 *testCfg `toml:"ocr2"`
+}
+
+func TestDecodeTOMLStrict(t *testing.T) {
+	data := []byte("[ocr2]\nname = \"don\"\nnodez = 4\n\n[[blockchains]]\ntype = \"anvil\"\n")
+
+	var lenient strictCfg
+	require.NoError(t, DecodeTOML("env.toml", data, &lenient))
+	require.Equal(t, "don", lenient.OCR2.Name)
+
+	t.Setenv(EnvVarStrictConfigs, "true")
+	var strict strictCfg
+	err := DecodeTOML("env.toml", data, &strict)
+	require.ErrorContains(t, err, "ocr2.nodez")
+	require.ErrorContains(t, err, "env.toml")
+	require.NotContains(t, err.Error(), "blockchains")
+
+	require.NoError(t, DecodeTOML("env.toml", []byte("[ocr2]\nname = \"don\"\n\n[[blockchains]]\ntype = \"anvil\"\n"), &strict))
+}
@@ -0,0 +1,203 @@
+package products
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change is a single value that differs between two configs, Path uses TOML keys, ex.: ocr2.deployed_contracts.ocr2_aggregator_address
+type Change struct {
+	Path string
+	A    string
+	B    string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Path, c.A, c.B)
+}
+
+// Diff compares two configs of the same type field by field, fields without a TOML representation are skipped
+func Diff[T any](a, b *T) []Change {
+	var changes []Change
+	diffValues(&changes, "", reflect.ValueOf(a), reflect.ValueOf(b))
+	return changes
+}
+
+// FormatDiff renders changes as a readable report, one changed value per line
+func FormatDiff(changes []Change) string {
+	if len(changes) == 0 {
+		return "no differences\n"
+	}
+	var sb strings.Builder
+	for _, c := range changes {
+		sb.WriteString(c.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func diffValues(changes *[]Change, path string, a, b reflect.Value) {
+	a, b = deref(a), deref(b)
+	if isLeaf(a, b) {
+		as, bs := formatValue(a), formatValue(b)
+		if as != bs {
+			*changes = append(*changes, Change{Path: path, A: as, B: bs})
+		}
+		return
+	}
+	// a value missing on one side is diffed against nothing, so every leaf of it is reported
+	v := a
+	if !v.IsValid() {
+		v = b
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			name, ok := fieldName(t.Field(i))
+			if !ok {
+				continue
+			}
+			diffValues(changes, join(path, name), field(a, i), field(b, i))
+		}
+	case reflect.Map:
+		keys := map[string]reflect.Value{}
+		for _, m := range []reflect.Value{a, b} {
+			if !m.IsValid() {
+				continue
+			}
+			for _, k := range m.MapKeys() {
+				keys[fmt.Sprint(k.Interface())] = k
+			}
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			diffValues(changes, join(path, name), mapIndex(a, keys[name]), mapIndex(b, keys[name]))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range max(length(a), length(b)) {
+			diffValues(changes, fmt.Sprintf("%s[%d]", path, i), index(a, i), index(b, i))
+		}
+	}
+}
+
+var (
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// isLeaf reports whether values are compared as a whole, ex.: scalars, byte slices, addresses, timestamps and big numbers
+func isLeaf(a, b reflect.Value) bool {
+	v := a
+	if !v.IsValid() {
+		v = b
+	}
+	if !v.IsValid() {
+		return true
+	}
+	t := v.Type()
+	if t.Implements(stringerType) || t.Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(stringerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return false
+	case reflect.Slice, reflect.Array:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+	return true
+}
+
+// formatValue prints a dereferenced value the way it reads in TOML, missing values are printed as <none>
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<none>"
+	}
+	if v.CanAddr() {
+		v = v.Addr()
+	} else {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p
+	}
+	switch x := v.Interface().(type) {
+	case encoding.TextMarshaler:
+		if text, err := x.MarshalText(); err == nil {
+			return string(text)
+		}
+	case fmt.Stringer:
+		return x.String()
+	case *[]byte:
+		return fmt.Sprintf("0x%x", *x)
+	}
+	return fmt.Sprint(v.Elem().Interface())
+}
+
+// deref follows pointers and interfaces, nil is returned as an invalid value
+func deref(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func field(v reflect.Value, i int) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+	return v.Field(i)
+}
+
+func mapIndex(v, k reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+	return v.MapIndex(k)
+}
+
+func length(v reflect.Value) int {
+	if !v.IsValid() {
+		return 0
+	}
+	return v.Len()
+}
+
+func index(v reflect.Value, i int) reflect.Value {
+	if i >= length(v) {
+		return reflect.Value{}
+	}
+	return v.Index(i)
+}
+
+// fieldName returns TOML key of a struct field, unexported and "-" fields are skipped
+func fieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return f.Name, true
+	}
+	return name, true
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
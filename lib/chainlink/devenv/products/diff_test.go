@@ -0,0 +1,68 @@
+package products
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type diffNode struct {
+	URL string `toml:"url"`
+}
+
+type diffCfg struct {
+	Aggregator string               `toml:"aggregator"`
+	Nodes      []*diffNode          `toml:"nodes"`
+	Forwarders map[string]string    `toml:"forwarders"`
+	MinAnswer  *big.Int             `toml:"min_answer"`
+	Offchain   []byte               `toml:"offchain"`
+	CreatedAt  time.Time            `toml:"created_at"`
+	Out        *diffNode            `toml:"out"`
+	Chains     map[string]*diffNode `toml:"chains"`
+	Runtime    string               `toml:"-"`
+	hidden     string
+}
+
+func TestDiff(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	a := &diffCfg{
+		Aggregator: "0x1",
+		Nodes:      []*diffNode{{URL: "http://node0"}, {URL: "http://node1"}},
+		Forwarders: map[string]string{"0xa": "0xf1", "0xb": "0xf2"},
+		MinAnswer:  big.NewInt(1),
+		Offchain:   []byte{1, 2},
+		CreatedAt:  ts,
+		Runtime:    "a",
+		hidden:     "a",
+	}
+	same := *a
+	same.Runtime, same.hidden = "b", "b"
+	require.Empty(t, Diff(a, &same))
+	require.Equal(t, "no differences\n", FormatDiff(Diff(a, &same)))
+
+	b := &diffCfg{
+		Aggregator: "0x2",
+		Nodes:      []*diffNode{{URL: "http://node0"}, {URL: "http://node1-new"}, {URL: "http://node2"}},
+		Forwarders: map[string]string{"0xa": "0xf1", "0xc": "0xf3"},
+		MinAnswer:  big.NewInt(2),
+		Offchain:   []byte{1, 3},
+		CreatedAt:  ts.Add(time.Hour),
+		Out:        &diffNode{URL: "http://out"},
+		Chains:     map[string]*diffNode{"1337": {URL: "http://anvil"}},
+	}
+	require.Equal(t, []Change{
+		{Path: "aggregator", A: "0x1", B: "0x2"},
+		{Path: "nodes[1].url", A: "http://node1", B: "http://node1-new"},
+		{Path: "nodes[2].url", A: "<none>", B: "http://node2"},
+		{Path: "forwarders.0xb", A: "0xf2", B: "<none>"},
+		{Path: "forwarders.0xc", A: "<none>", B: "0xf3"},
+		{Path: "min_answer", A: "1", B: "2"},
+		{Path: "offchain", A: "0x0102", B: "0x0103"},
+		{Path: "created_at", A: "2025-01-02T03:04:05Z", B: "2025-01-02T04:04:05Z"},
+		{Path: "out.url", A: "<none>", B: "http://out"},
+		{Path: "chains.1337.url", A: "<none>", B: "http://anvil"},
+	}, Diff(a, b))
+	require.Equal(t, "aggregator: 0x1 -> 0x2\n", FormatDiff(Diff(a, b)[:1]))
+}
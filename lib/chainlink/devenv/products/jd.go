@@ -0,0 +1,14 @@
+package products
+
+import (
+	csav1 "github.com/smartcontractkit/chainlink-protos/job-distributor/v1/csa"
+	jobv1 "github.com/smartcontractkit/chainlink-protos/job-distributor/v1/job"
+	nodev1 "github.com/smartcontractkit/chainlink-protos/job-distributor/v1/node"
+)
+
+// JobDistributor is a Job Distributor client products propose jobs through, ex.: devenv.JobDistributor
+type JobDistributor interface {
+	jobv1.JobServiceClient
+	nodev1.NodeServiceClient
+	csav1.CSAServiceClient
+}
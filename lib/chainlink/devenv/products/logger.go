@@ -0,0 +1,25 @@
+package products
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+type loggerKey struct{}
+
+// WithLogger returns ctx carrying l, library functions called with it log to l instead of their package logger,
+// ex.: to capture logs in a test or to route them into an application's own sink
+func WithLogger(ctx context.Context, l zerolog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, &l)
+}
+
+// Logger returns the logger ctx carries, fallback if ctx has none
+func Logger(ctx context.Context, fallback *zerolog.Logger) *zerolog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*zerolog.Logger); ok {
+			return l
+		}
+	}
+	return fallback
+}
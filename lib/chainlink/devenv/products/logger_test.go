@@ -0,0 +1,29 @@
+package products
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	var fallbackOut, injectedOut bytes.Buffer
+	fallback := zerolog.New(&fallbackOut)
+
+	Logger(context.Background(), &fallback).Info().Msg("default")
+	require.Contains(t, fallbackOut.String(), `"message":"default"`)
+
+	ctx := WithLogger(context.Background(), zerolog.New(&injectedOut).With().Str("test", t.Name()).Logger())
+	Logger(ctx, &fallback).Info().Msg("injected")
+	require.Contains(t, injectedOut.String(), `"test":"TestLogger"`)
+	require.Contains(t, injectedOut.String(), `"message":"injected"`)
+	require.NotContains(t, fallbackOut.String(), "injected")
+
+	// derived contexts keep the logger
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	require.Same(t, Logger(ctx, &fallback), Logger(child, &fallback))
+}
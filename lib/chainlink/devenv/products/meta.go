@@ -0,0 +1,65 @@
+package products
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+)
+
+// Meta describes what produced an output file, so a shared env-out.toml can be correlated with the code that built it
+type Meta struct {
+	// Description is free-form text kept from the input config, ex.: [meta] description = "nightly soak"
+	Description string `toml:"description"`
+	// CreatedBy is the user and the program that created the output
+	CreatedBy string `toml:"created_by"`
+	// CreatedAt is UTC creation time
+	CreatedAt time.Time `toml:"created_at"`
+	// GitCommit is the VCS revision the program was built from, "-dirty" marks uncommitted changes,
+	// it's empty for "go run" and "go test" builds which carry no VCS info
+	GitCommit string `toml:"git_commit"`
+	// Configs are input configs the output was produced from, ex.: CTF_CONFIGS=env.toml,overrides.toml
+	Configs []string `toml:"configs"`
+}
+
+// NewMeta returns metadata of an output produced now from configs, description is kept from in if it's set
+func NewMeta(in *Meta, configs string) *Meta {
+	m := &Meta{
+		CreatedBy: fmt.Sprintf("%s (%s)", defaults.Coalesce(os.Getenv("USER"), os.Getenv("USERNAME"), "unknown"), filepath.Base(os.Args[0])),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		GitCommit: gitCommit(debug.ReadBuildInfo),
+	}
+	if in != nil {
+		m.Description = in.Description
+	}
+	if configs != "" {
+		m.Configs = strings.Split(configs, ",")
+	}
+	return m
+}
+
+// gitCommit returns the VCS revision stamped into build info, empty if there is none
+func gitCommit(readBuildInfo func() (*debug.BuildInfo, bool)) string {
+	bi, ok := readBuildInfo()
+	if !ok {
+		return ""
+	}
+	var revision string
+	var modified bool
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if revision != "" && modified {
+		revision += "-dirty"
+	}
+	return revision
+}
@@ -0,0 +1,62 @@
+package products
+
+import (
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitCommit(t *testing.T) {
+	buildInfo := func(settings ...debug.BuildSetting) func() (*debug.BuildInfo, bool) {
+		return func() (*debug.BuildInfo, bool) { return &debug.BuildInfo{Settings: settings}, true }
+	}
+	tests := []struct {
+		name string
+		read func() (*debug.BuildInfo, bool)
+		want string
+	}{
+		{name: "no build info", read: func() (*debug.BuildInfo, bool) { return nil, false }},
+		{name: "no vcs info", read: buildInfo()},
+		{name: "clean", read: buildInfo(debug.BuildSetting{Key: "vcs.revision", Value: "abc123"}, debug.BuildSetting{Key: "vcs.modified", Value: "false"}), want: "abc123"},
+		{name: "dirty", read: buildInfo(debug.BuildSetting{Key: "vcs.revision", Value: "abc123"}, debug.BuildSetting{Key: "vcs.modified", Value: "true"}), want: "abc123-dirty"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, gitCommit(tc.read))
+		})
+	}
+}
+
+func TestNewMeta(t *testing.T) {
+	t.Setenv("USER", "ci")
+	m := NewMeta(&Meta{Description: "nightly soak", CreatedBy: "someone else"}, "env.toml,overrides.toml")
+	require.Equal(t, "nightly soak", m.Description)
+	require.Contains(t, m.CreatedBy, "ci (")
+	require.Equal(t, []string{"env.toml", "overrides.toml"}, m.Configs)
+	require.WithinDuration(t, time.Now(), m.CreatedAt, time.Minute)
+
+	require.Empty(t, NewMeta(nil, "").Description)
+}
+
+func TestMetaIsOptional(t *testing.T) {
+	type withMeta struct {
+		OCR2 *testCfg `toml:"ocr2"`
+		Meta *Meta    `toml:"meta"`
+	}
+	m := NewMeta(&Meta{Description: "nightly soak"}, "env.toml")
+	data, err := toml.Marshal(&withMeta{OCR2: &testCfg{Name: "don"}, Meta: m})
+	require.NoError(t, err)
+
+	var out withMeta
+	require.NoError(t, DecodeTOML("env-out.toml", data, &out))
+	require.Equal(t, m, out.Meta)
+
+	// loaders without meta section keep working in strict mode
+	t.Setenv(EnvVarStrictConfigs, "true")
+	var strict strictCfg
+	require.NoError(t, DecodeTOML("env-out.toml", data, &strict))
+	require.Equal(t, "don", strict.OCR2.Name)
+}
@@ -0,0 +1,96 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+)
+
+// DefaultAggregatorDecimals is used if ocr2.ocr2.decimals is not set
+const DefaultAggregatorDecimals = 18
+
+var (
+	// int192 bounds, aggregator stores answers and their limits as int192
+	minInt192 = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 191))
+	maxInt192 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 191), big.NewInt(1))
+)
+
+// AggregatorDeployParams are OCR2 aggregator constructor arguments
+type AggregatorDeployParams struct {
+	LinkToken                 common.Address
+	MinimumAnswer             *big.Int
+	MaximumAnswer             *big.Int
+	BillingAccessController   common.Address
+	RequesterAccessController common.Address
+	Decimals                  uint8
+	Description               string
+}
+
+// NewAggregatorDeployParams assembles aggregator constructor arguments from [ocr2.ocr2] and validates them
+func NewAggregatorDeployParams(o *OCRv2OffChainOptions, linkToken common.Address) (*AggregatorDeployParams, error) {
+	if o == nil {
+		return nil, errors.New("no [ocr2.ocr2] aggregator options found")
+	}
+	p := &AggregatorDeployParams{
+		LinkToken:                 linkToken,
+		MinimumAnswer:             o.MinimumAnswer,
+		MaximumAnswer:             o.MaximumAnswer,
+		BillingAccessController:   o.BillingAccessController,
+		RequesterAccessController: o.RequesterAccessController,
+		Decimals:                  defaults.Coalesce(o.Decimals, DefaultAggregatorDecimals),
+		Description:               o.Description,
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate checks constructor arguments the aggregator would otherwise accept and misbehave with
+func (p *AggregatorDeployParams) Validate() error {
+	if p.LinkToken == (common.Address{}) {
+		return errors.New("aggregator LINK token address is not set")
+	}
+	if p.MinimumAnswer == nil || p.MaximumAnswer == nil {
+		return errors.New("aggregator minimum_answer and maximum_answer are required")
+	}
+	for name, v := range map[string]*big.Int{"minimum_answer": p.MinimumAnswer, "maximum_answer": p.MaximumAnswer} {
+		if v.Cmp(minInt192) < 0 || v.Cmp(maxInt192) > 0 {
+			return fmt.Errorf("aggregator %s %s doesn't fit int192", name, v)
+		}
+	}
+	if p.MinimumAnswer.Cmp(p.MaximumAnswer) >= 0 {
+		return fmt.Errorf("aggregator minimum_answer %s must be less than maximum_answer %s", p.MinimumAnswer, p.MaximumAnswer)
+	}
+	return nil
+}
+
+// deployAggregator deploys OCR2 aggregator and waits until it's deployed
+func deployAggregator(ctx context.Context, c *ethclient.Client, auth *bind.TransactOpts, p *AggregatorDeployParams, w WaitConfig) (common.Address, *ocr2aggregator.OCR2Aggregator, error) {
+	addr, tx, ocr2i, err := ocr2aggregator.DeployOCR2Aggregator(
+		auth,
+		c,
+		p.LinkToken,
+		p.MinimumAnswer,
+		p.MaximumAnswer,
+		p.BillingAccessController,
+		p.RequesterAccessController,
+		p.Decimals,
+		p.Description,
+	)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("could not create ocr2 aggregator contract: %w", err)
+	}
+	if _, err := WaitDeployed(ctx, c, tx, w); err != nil {
+		return common.Address{}, nil, err
+	}
+	return addr, ocr2i, nil
+}
@@ -0,0 +1,65 @@
+package ocr2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAggregatorDeployParams(t *testing.T) {
+	link := common.HexToAddress("0xDc64a140Aa3E981100a9becA4E685f962f0cF6C9")
+	billing := common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	requester := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	valid := func() *OCRv2OffChainOptions {
+		return &OCRv2OffChainOptions{
+			MinimumAnswer:             big.NewInt(1),
+			MaximumAnswer:             big.NewInt(50000000000000000),
+			Description:               "fake-ea-price",
+			Decimals:                  8,
+			BillingAccessController:   billing,
+			RequesterAccessController: requester,
+		}
+	}
+
+	p, err := NewAggregatorDeployParams(valid(), link)
+	require.NoError(t, err)
+	require.Equal(t, &AggregatorDeployParams{
+		LinkToken:                 link,
+		MinimumAnswer:             big.NewInt(1),
+		MaximumAnswer:             big.NewInt(50000000000000000),
+		BillingAccessController:   billing,
+		RequesterAccessController: requester,
+		Decimals:                  8,
+		Description:               "fake-ea-price",
+	}, p)
+
+	noDecimals := valid()
+	noDecimals.Decimals = 0
+	p, err = NewAggregatorDeployParams(noDecimals, link)
+	require.NoError(t, err)
+	require.Equal(t, uint8(DefaultAggregatorDecimals), p.Decimals)
+
+	tests := []struct {
+		name    string
+		mutate  func(o *OCRv2OffChainOptions)
+		link    common.Address
+		wantErr string
+	}{
+		{name: "no link", mutate: func(o *OCRv2OffChainOptions) {}, wantErr: "LINK token address is not set"},
+		{name: "no bounds", mutate: func(o *OCRv2OffChainOptions) { o.MaximumAnswer = nil }, link: link, wantErr: "minimum_answer and maximum_answer are required"},
+		{name: "min above max", mutate: func(o *OCRv2OffChainOptions) { o.MinimumAnswer = big.NewInt(1e18) }, link: link, wantErr: "must be less than maximum_answer"},
+		{name: "max overflows int192", mutate: func(o *OCRv2OffChainOptions) { o.MaximumAnswer = new(big.Int).Lsh(big.NewInt(1), 191) }, link: link, wantErr: "maximum_answer 3138550867693340381917894711603833208051177722232017256448 doesn't fit int192"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := valid()
+			tc.mutate(o)
+			_, err := NewAggregatorDeployParams(o, tc.link)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+	_, err = NewAggregatorDeployParams(nil, link)
+	require.ErrorContains(t, err, "no [ocr2.ocr2]")
+}
@@ -0,0 +1,118 @@
+package ocr2
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// CadenceChange is the direction round cadence is expected to move after a config change
+type CadenceChange string
+
+const (
+	CadenceFaster CadenceChange = "faster"
+	CadenceSlower CadenceChange = "slower"
+)
+
+// DefaultCadenceRatio is how many times cadence must change if CadenceCheck MinRatio is not set
+const DefaultCadenceRatio = 1.2
+
+// CadenceCheck asserts a new config supersedes the old one not only by digest: rounds reported under it move round cadence
+// in the expected direction within Rounds rounds, ex.: lower delta round makes rounds faster
+type CadenceCheck struct {
+	Expect CadenceChange
+	// Rounds is how many rounds after the config change cadence is measured over, the first rounds may still follow the old config
+	Rounds int
+	// MinRatio is how many times faster or slower cadence must become, DefaultCadenceRatio if 0
+	MinRatio float64
+}
+
+// Validate checks expected direction is known and there are enough rounds to measure cadence
+func (c *CadenceCheck) Validate() error {
+	if !slices.Contains([]CadenceChange{CadenceFaster, CadenceSlower}, c.Expect) {
+		return fmt.Errorf("cadence change must be %s or %s, got %q", CadenceFaster, CadenceSlower, c.Expect)
+	}
+	if c.Rounds < 2 {
+		return fmt.Errorf("cadence must be measured over at least 2 rounds, got %d", c.Rounds)
+	}
+	if c.MinRatio != 0 && c.MinRatio < 1 {
+		return fmt.Errorf("cadence min ratio must be at least 1, got %f", c.MinRatio)
+	}
+	return nil
+}
+
+// Check compares cadence of rounds before the config change with cadence of the first Rounds rounds after it,
+// both cadences are returned for logging even if the check fails
+func (c *CadenceCheck) Check(before, after []RoundData) (time.Duration, time.Duration, error) {
+	if err := c.Validate(); err != nil {
+		return 0, 0, err
+	}
+	old, err := RoundCadence(before)
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not measure cadence before config change: %w", err)
+	}
+	after = uniqueRounds(after)
+	if len(after) < c.Rounds {
+		return old, 0, fmt.Errorf("only %d rounds were reported after config change, expected %d", len(after), c.Rounds)
+	}
+	cur, err := RoundCadence(after[:c.Rounds])
+	if err != nil {
+		return old, 0, fmt.Errorf("could not measure cadence after config change: %w", err)
+	}
+	ratio := c.MinRatio
+	if ratio == 0 {
+		ratio = DefaultCadenceRatio
+	}
+	switch c.Expect {
+	case CadenceFaster:
+		if float64(cur)*ratio > float64(old) {
+			return old, cur, fmt.Errorf("rounds are not %.2fx faster after config change: every %s before, every %s after", ratio, old, cur)
+		}
+	case CadenceSlower:
+		if float64(old)*ratio > float64(cur) {
+			return old, cur, fmt.Errorf("rounds are not %.2fx slower after config change: every %s before, every %s after", ratio, old, cur)
+		}
+	}
+	return old, cur, nil
+}
+
+// RoundCadence returns the median interval between consecutive rounds by their on-chain UpdatedAt,
+// rounds repeated by out of range values are counted once, median ignores rounds delayed by a transition
+func RoundCadence(rounds []RoundData) (time.Duration, error) {
+	rounds = uniqueRounds(rounds)
+	if len(rounds) < 2 {
+		return 0, fmt.Errorf("at least 2 rounds are needed to measure cadence, got %d", len(rounds))
+	}
+	intervals := make([]time.Duration, 0, len(rounds)-1)
+	for i := 1; i < len(rounds); i++ {
+		prev, cur := rounds[i-1].UpdatedAt, rounds[i].UpdatedAt
+		if prev == nil || cur == nil {
+			return 0, errors.New("round has no updatedAt")
+		}
+		intervals = append(intervals, time.Duration(cur.Int64()-prev.Int64())*time.Second)
+	}
+	slices.Sort(intervals)
+	return intervals[len(intervals)/2], nil
+}
+
+// RoundsSince returns rounds updated in start block or later, ex.: rounds reported once a new config is live on all nodes.
+// UpdatedAt is a block timestamp, so it's compared with start block timestamp, host clock can drift from chain time
+func RoundsSince(rounds []RoundData, start *gethtypes.Header) []RoundData {
+	res := make([]RoundData, 0, len(rounds))
+	for _, rd := range rounds {
+		if rd.UpdatedAt != nil && rd.UpdatedAt.Uint64() >= start.Time {
+			res = append(res, rd)
+		}
+	}
+	return res
+}
+
+// uniqueRounds drops consecutive repeats of the same round
+func uniqueRounds(rounds []RoundData) []RoundData {
+	return slices.CompactFunc(slices.Clone(rounds), func(a, b RoundData) bool {
+		return a.RoundId != nil && b.RoundId != nil && a.RoundId.Cmp(b.RoundId) == 0
+	})
+}
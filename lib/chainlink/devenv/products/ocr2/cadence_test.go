@@ -0,0 +1,79 @@
+package ocr2
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// roundsEvery returns n consecutive rounds starting from round id updated every interval seconds from start
+func roundsEvery(id, start, interval int64, n int) []RoundData {
+	rounds := make([]RoundData, 0, n)
+	for i := range int64(n) {
+		rounds = append(rounds, RoundData{RoundId: big.NewInt(id + i), UpdatedAt: big.NewInt(start + i*interval)})
+	}
+	return rounds
+}
+
+func TestRoundCadence(t *testing.T) {
+	// the median ignores a single slow transition round
+	rounds := append(roundsEvery(1, 100, 10, 3), roundsEvery(4, 160, 10, 3)...)
+	got, err := RoundCadence(rounds)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, got)
+
+	// a repeated round of an out of range value is counted once
+	repeated := roundsEvery(1, 100, 20, 2)
+	repeated = append(repeated[:2:2], repeated[1], roundsEvery(3, 140, 20, 1)[0])
+	got, err = RoundCadence(repeated)
+	require.NoError(t, err)
+	require.Equal(t, 20*time.Second, got)
+
+	_, err = RoundCadence(roundsEvery(1, 100, 10, 1))
+	require.ErrorContains(t, err, "at least 2 rounds are needed")
+}
+
+func TestRoundsSince(t *testing.T) {
+	rounds := roundsEvery(1, 100, 10, 5)
+	got := RoundsSince(rounds, &gethtypes.Header{Time: 120})
+	require.Len(t, got, 3)
+	require.Equal(t, int64(3), got[0].RoundId.Int64())
+	require.Empty(t, RoundsSince(rounds, &gethtypes.Header{Time: 200}))
+}
+
+func TestCadenceCheck(t *testing.T) {
+	slow := roundsEvery(1, 100, 20, 4)
+	// the first round after the change still follows the old cadence
+	fast := append(roundsEvery(5, 180, 20, 1), roundsEvery(6, 185, 5, 4)...)
+
+	tests := []struct {
+		name    string
+		check   CadenceCheck
+		before  []RoundData
+		after   []RoundData
+		wantErr string
+	}{
+		{name: "faster", check: CadenceCheck{Expect: CadenceFaster, Rounds: 5}, before: slow, after: fast},
+		{name: "slower", check: CadenceCheck{Expect: CadenceSlower, Rounds: 4, MinRatio: 2}, before: fast, after: slow},
+		{name: "not faster", check: CadenceCheck{Expect: CadenceFaster, Rounds: 4}, before: slow, after: slow, wantErr: "rounds are not 1.20x faster"},
+		{name: "not enough faster", check: CadenceCheck{Expect: CadenceFaster, Rounds: 5, MinRatio: 5}, before: slow, after: fast, wantErr: "rounds are not 5.00x faster"},
+		{name: "too few rounds", check: CadenceCheck{Expect: CadenceFaster, Rounds: 6}, before: slow, after: fast, wantErr: "only 5 rounds were reported"},
+		{name: "no rounds before", check: CadenceCheck{Expect: CadenceFaster, Rounds: 2}, before: slow[:1], after: fast, wantErr: "could not measure cadence before config change"},
+		{name: "unknown direction", check: CadenceCheck{Expect: "sideways", Rounds: 2}, before: slow, after: fast, wantErr: "cadence change must be faster or slower"},
+		{name: "one round", check: CadenceCheck{Expect: CadenceFaster, Rounds: 1}, before: slow, after: fast, wantErr: "at least 2 rounds"},
+		{name: "ratio below 1", check: CadenceCheck{Expect: CadenceFaster, Rounds: 2, MinRatio: 0.5}, before: slow, after: fast, wantErr: "min ratio must be at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.check.Check(tt.before, tt.after)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
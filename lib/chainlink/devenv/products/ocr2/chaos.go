@@ -0,0 +1,116 @@
+package ocr2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Chaos actions, stop and pause affect containers, delay and loss affect container network
+const (
+	ChaosActionStop  = "stop"
+	ChaosActionPause = "pause"
+	ChaosActionDelay = "delay"
+	ChaosActionLoss  = "loss"
+)
+
+// pumbaTCImage is the image Pumba uses to run tc inside of container network namespace
+const pumbaTCImage = "gaiadocker/iproute2"
+
+// ChaosSpec describes a single chaos experiment on CL node containers
+type ChaosSpec struct {
+	// Action is one of stop, pause, delay or loss
+	Action string `toml:"action"`
+	// Nodes selects target nodes by index in the node set, 0 is the bootstrap node, all nodes are selected if empty
+	Nodes []int `toml:"nodes"`
+	// DurationSec is how long the action lasts, stopped containers are restarted after it
+	DurationSec int64 `toml:"duration_sec"`
+	// RecoveryWaitSec is how long to wait after the action before the next round is checked
+	RecoveryWaitSec int64 `toml:"recovery_wait_sec"`
+	// DelayMs is network delay added by delay action
+	DelayMs int64 `toml:"delay_ms"`
+	// LossPercent is percent of packets dropped by loss action
+	LossPercent int `toml:"loss_percent"`
+}
+
+// Validate checks action, durations and node selector
+func (c *ChaosSpec) Validate() error {
+	switch c.Action {
+	case ChaosActionStop, ChaosActionPause:
+	case ChaosActionDelay:
+		if c.DelayMs <= 0 {
+			return fmt.Errorf("chaos action %s requires positive delay_ms, got %d", c.Action, c.DelayMs)
+		}
+	case ChaosActionLoss:
+		if c.LossPercent <= 0 || c.LossPercent > 100 {
+			return fmt.Errorf("chaos action %s requires loss_percent in range 1-100, got %d", c.Action, c.LossPercent)
+		}
+	default:
+		return fmt.Errorf("unknown chaos action %q, use one of: %s, %s, %s, %s", c.Action, ChaosActionStop, ChaosActionPause, ChaosActionDelay, ChaosActionLoss)
+	}
+	if c.DurationSec <= 0 {
+		return fmt.Errorf("chaos duration_sec must be positive, got %d", c.DurationSec)
+	}
+	if c.RecoveryWaitSec < 0 {
+		return fmt.Errorf("chaos recovery_wait_sec must be non-negative, got %d", c.RecoveryWaitSec)
+	}
+	seen := make(map[int]bool, len(c.Nodes))
+	for _, idx := range c.Nodes {
+		if idx < 0 {
+			return fmt.Errorf("chaos nodes can't contain negative index %d", idx)
+		}
+		if seen[idx] {
+			return fmt.Errorf("chaos nodes contain duplicate index %d", idx)
+		}
+		seen[idx] = true
+	}
+	return nil
+}
+
+// RecoveryWait returns how long to wait after the action
+func (c *ChaosSpec) RecoveryWait() time.Duration {
+	return time.Duration(c.RecoveryWaitSec) * time.Second
+}
+
+// PumbaCommand translates spec to a Pumba command targeting node containers of a node set,
+// read more about commands here https://github.com/alexei-led/pumba
+func (c *ChaosSpec) PumbaCommand(nodeSet string) (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	duration := (time.Duration(c.DurationSec) * time.Second).String()
+	target := c.pumbaTarget(nodeSet)
+	switch c.Action {
+	case ChaosActionStop:
+		return fmt.Sprintf("stop --duration=%s --restart %s", duration, target), nil
+	case ChaosActionPause:
+		return fmt.Sprintf("pause --duration=%s %s", duration, target), nil
+	case ChaosActionDelay:
+		return fmt.Sprintf("netem --tc-image=%s --duration=%s delay --time=%d %s", pumbaTCImage, duration, c.DelayMs, target), nil
+	default:
+		return fmt.Sprintf("netem --tc-image=%s --duration=%s loss --percent=%d %s", pumbaTCImage, duration, c.LossPercent, target), nil
+	}
+}
+
+// pumbaTarget returns container name regexp, it's anchored at the end so node1 doesn't match node10
+func (c *ChaosSpec) pumbaTarget(nodeSet string) string {
+	if len(c.Nodes) == 0 {
+		return fmt.Sprintf("re2:%s-node[0-9]+$", nodeSet)
+	}
+	idx := make([]string, 0, len(c.Nodes))
+	for _, n := range c.Nodes {
+		idx = append(idx, strconv.Itoa(n))
+	}
+	return fmt.Sprintf("re2:%s-node(%s)$", nodeSet, strings.Join(idx, "|"))
+}
+
+// validateChaos checks all chaos specs, error points to the invalid entry
+func validateChaos(specs []*ChaosSpec) error {
+	for i, s := range specs {
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("invalid chaos spec %d: %w", i, err)
+		}
+	}
+	return nil
+}
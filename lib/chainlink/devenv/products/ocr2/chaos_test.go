@@ -0,0 +1,83 @@
+package ocr2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChaosSpecPumbaCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		spec ChaosSpec
+		cmd  string
+		err  string
+	}{
+		{
+			name: "stop bootstrap node",
+			spec: ChaosSpec{Action: ChaosActionStop, Nodes: []int{0}, DurationSec: 10, RecoveryWaitSec: 10},
+			cmd:  "stop --duration=10s --restart re2:don-node(0)$",
+		},
+		{
+			name: "pause several nodes",
+			spec: ChaosSpec{Action: ChaosActionPause, Nodes: []int{1, 3}, DurationSec: 5},
+			cmd:  "pause --duration=5s re2:don-node(1|3)$",
+		},
+		{
+			name: "delay all nodes",
+			spec: ChaosSpec{Action: ChaosActionDelay, DurationSec: 10, DelayMs: 1000},
+			cmd:  "netem --tc-image=gaiadocker/iproute2 --duration=10s delay --time=1000 re2:don-node[0-9]+$",
+		},
+		{
+			name: "packet loss",
+			spec: ChaosSpec{Action: ChaosActionLoss, Nodes: []int{2}, DurationSec: 60, LossPercent: 30},
+			cmd:  "netem --tc-image=gaiadocker/iproute2 --duration=1m0s loss --percent=30 re2:don-node(2)$",
+		},
+		{
+			name: "unknown action",
+			spec: ChaosSpec{Action: "kill", DurationSec: 10},
+			err:  `unknown chaos action "kill"`,
+		},
+		{
+			name: "zero duration",
+			spec: ChaosSpec{Action: ChaosActionStop},
+			err:  "duration_sec must be positive",
+		},
+		{
+			name: "negative recovery wait",
+			spec: ChaosSpec{Action: ChaosActionStop, DurationSec: 10, RecoveryWaitSec: -1},
+			err:  "recovery_wait_sec must be non-negative",
+		},
+		{
+			name: "delay without delay_ms",
+			spec: ChaosSpec{Action: ChaosActionDelay, DurationSec: 10},
+			err:  "requires positive delay_ms",
+		},
+		{
+			name: "loss above 100 percent",
+			spec: ChaosSpec{Action: ChaosActionLoss, DurationSec: 10, LossPercent: 101},
+			err:  "requires loss_percent in range 1-100",
+		},
+		{
+			name: "negative node index",
+			spec: ChaosSpec{Action: ChaosActionStop, DurationSec: 10, Nodes: []int{-1}},
+			err:  "negative index -1",
+		},
+		{
+			name: "duplicate node index",
+			spec: ChaosSpec{Action: ChaosActionStop, DurationSec: 10, Nodes: []int{1, 1}},
+			err:  "duplicate index 1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := tc.spec.PumbaCommand("don")
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.cmd, cmd)
+		})
+	}
+}
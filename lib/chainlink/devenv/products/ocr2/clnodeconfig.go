@@ -0,0 +1,188 @@
+package ocr2
+
+import (
+	"fmt"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
+)
+
+// CLNodeConfig mirrors CL node config sections the environment sets, ex.: [[EVM]], [OCR2], [P2P.V2],
+// modify fields before Render to override a part of the config without string splicing
+type CLNodeConfig struct {
+	EVM         []*EVMConfig `toml:"EVM"`
+	Feature     FeatureConfig
+	OCR2        OCR2NodeConfig
+	P2P         P2PConfig
+	Log         LogConfig
+	Pyroscope   *PyroscopeConfig `toml:",omitempty"`
+	WebServer   WebServerConfig
+	JobPipeline JobPipelineConfig
+	// TelemetryIngress is set only if [ocr2.telemetry] is enabled
+	TelemetryIngress *TelemetryIngressConfig `toml:",omitempty"`
+}
+
+// EVMConfig is a single [[EVM]] chain
+type EVMConfig struct {
+	LogPollInterval          string
+	BlockBackfillDepth       int64
+	LinkContractAddress      string
+	ChainID                  string
+	MinIncomingConfirmations int64
+	MinContractPayment       string
+	FinalityDepth            int64
+	Transactions             EVMTransactionsConfig
+	Nodes                    []*EVMNodeConfig
+}
+
+// EVMTransactionsConfig is [EVM.Transactions] of a chain
+type EVMTransactionsConfig struct {
+	ForwardersEnabled bool
+}
+
+// EVMNodeConfig is an RPC node of a chain, URLs must be reachable from node containers
+type EVMNodeConfig struct {
+	Name    string
+	WsUrl   string //nolint:revive // CL node config key
+	HttpUrl string //nolint:revive // CL node config key
+}
+
+// FeatureConfig is [Feature], node features the environment relies on
+type FeatureConfig struct {
+	FeedsManager bool
+	LogPoller    bool
+	UICSAKeys    bool
+}
+
+// OCR2NodeConfig is [OCR2] of a node, not to be confused with the OCR2 product config
+type OCR2NodeConfig struct {
+	Enabled                      bool
+	SimulateTransactions         bool
+	DefaultTransactionQueueDepth int
+}
+
+// P2PConfig is [P2P], only networking stack v2 is used
+type P2PConfig struct {
+	V2 P2PV2Config
+}
+
+// P2PV2Config is [P2P.V2], nodes listen on all interfaces
+type P2PV2Config struct {
+	Enabled         bool
+	ListenAddresses []string
+}
+
+// LogConfig is [Log]
+type LogConfig struct {
+	JSONConsole bool
+	Level       string
+	File        LogFileConfig
+}
+
+// LogFileConfig is [Log.File], MaxSize 0b disables logging to a file
+type LogFileConfig struct {
+	MaxSize string
+}
+
+// PyroscopeConfig points nodes at a Pyroscope server, profiling is off if it's nil
+type PyroscopeConfig struct {
+	ServerAddress string
+	Environment   string
+}
+
+// WebServerConfig is [WebServer], long sessions and high rate limits keep API clients of long tests logged in
+type WebServerConfig struct {
+	SessionTimeout   string
+	HTTPWriteTimeout string
+	SecureCookies    bool
+	HTTPPort         int
+	TLS              WebServerTLSConfig
+	RateLimit        WebServerRateLimitConfig
+}
+
+// WebServerTLSConfig is [WebServer.TLS], HTTPSPort 0 disables HTTPS
+type WebServerTLSConfig struct {
+	HTTPSPort int
+}
+
+// WebServerRateLimitConfig is [WebServer.RateLimit]
+type WebServerRateLimitConfig struct {
+	Authenticated   int
+	Unauthenticated int
+}
+
+// JobPipelineConfig is [JobPipeline]
+type JobPipelineConfig struct {
+	HTTPRequest JobPipelineHTTPRequestConfig
+}
+
+// JobPipelineHTTPRequestConfig is [JobPipeline.HTTPRequest] of bridge and http tasks
+type JobPipelineHTTPRequestConfig struct {
+	DefaultTimeout string
+}
+
+// TelemetryIngressConfig is [TelemetryIngress]
+type TelemetryIngressConfig struct {
+	Logging   bool
+	Endpoints []*TelemetryEndpointConfig
+}
+
+// TelemetryEndpointConfig is a [[TelemetryIngress.Endpoints]] ingress of a chain
+type TelemetryEndpointConfig struct {
+	Network      string
+	ChainID      string
+	URL          string
+	ServerPubKey string
+}
+
+// Render marshals the config to TOML CL nodes are started with
+func (c *CLNodeConfig) Render() (string, error) {
+	b, err := toml.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal CL node config: %w", err)
+	}
+	return string(b), nil
+}
+
+// CLNodeConfig builds CL node config for a blockchain from product config, nodes of all node sets share it
+func (m *Configurator) CLNodeConfig(bc *blockchain.Input) (*CLNodeConfig, error) {
+	if err := checkBlockchainOut(bc); err != nil {
+		return nil, err
+	}
+	node := bc.Out.Nodes[0]
+	chainID := bc.Out.ChainID
+	cs, err := m.OCR2.chainSettings(bc.Type, chainID)
+	if err != nil {
+		return nil, err
+	}
+	p2p, err := m.OCR2.p2pSettings()
+	if err != nil {
+		return nil, err
+	}
+	return &CLNodeConfig{
+		EVM: []*EVMConfig{{
+			LogPollInterval:          cs.LogPollInterval,
+			BlockBackfillDepth:       cs.BlockBackfillDepth,
+			LinkContractAddress:      m.OCR2.LinkContractAddress,
+			ChainID:                  chainID,
+			MinIncomingConfirmations: cs.MinIncomingConfirmations,
+			MinContractPayment:       "0.0000001 link",
+			FinalityDepth:            cs.FinalityDepth,
+			Transactions:             EVMTransactionsConfig{ForwardersEnabled: m.OCR2.ForwardingAllowed},
+			Nodes:                    []*EVMNodeConfig{{Name: "default", WsUrl: node.InternalWSUrl, HttpUrl: node.InternalHTTPUrl}},
+		}},
+		Feature:   FeatureConfig{FeedsManager: true, LogPoller: true, UICSAKeys: true},
+		OCR2:      OCR2NodeConfig{Enabled: true, DefaultTransactionQueueDepth: 1},
+		P2P:       P2PConfig{V2: P2PV2Config{Enabled: true, ListenAddresses: []string{fmt.Sprintf("0.0.0.0:%d", p2p.ListenPort)}}},
+		Log:       LogConfig{JSONConsole: true, Level: "debug", File: LogFileConfig{MaxSize: "0b"}},
+		Pyroscope: &PyroscopeConfig{ServerAddress: "http://host.docker.internal:4040", Environment: "local"},
+		WebServer: WebServerConfig{
+			SessionTimeout:   "999h0m0s",
+			HTTPWriteTimeout: "3m",
+			HTTPPort:         6688,
+			RateLimit:        WebServerRateLimitConfig{Authenticated: 5000, Unauthenticated: 5000},
+		},
+		JobPipeline:      JobPipelineConfig{HTTPRequest: JobPipelineHTTPRequestConfig{DefaultTimeout: "1m"}},
+		TelemetryIngress: m.OCR2.Telemetry.nodeConfig(chainID),
+	}, nil
+}
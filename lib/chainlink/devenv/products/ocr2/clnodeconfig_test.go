@@ -0,0 +1,47 @@
+package ocr2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
+)
+
+func anvilOut(chainID string) *blockchain.Input {
+	return &blockchain.Input{Type: "anvil", ChainID: chainID, Out: &blockchain.Output{
+		ChainID: chainID,
+		Nodes:   []*blockchain.Node{{InternalWSUrl: "ws://anvil:8545", InternalHTTPUrl: "http://anvil:8545"}},
+	}}
+}
+
+func TestCLNodeConfigGolden(t *testing.T) {
+	m := &Configurator{OCR2: &OCR2{
+		ChainFinalityDepth:  5,
+		LinkContractAddress: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		Telemetry:           &Telemetry{Enabled: true, URL: "ingress:5000", ServerPubKey: "key"},
+	}}
+	cfg, err := m.CLNodeConfig(anvilOut("1337"))
+	require.NoError(t, err)
+	got, err := cfg.Render()
+	require.NoError(t, err)
+	requireGolden(t, "cl_node_config.toml", got)
+}
+
+func TestCLNodeConfigOverride(t *testing.T) {
+	m := &Configurator{OCR2: &OCR2{Chains: map[string]*ChainSettings{"1337": {LogPollInterval: "500ms"}}, P2P: &P2PSettings{ListenPort: 7000}}}
+	cfg, err := m.CLNodeConfig(anvilOut("1337"))
+	require.NoError(t, err)
+	require.Equal(t, "500ms", cfg.EVM[0].LogPollInterval)
+	require.Equal(t, []string{"0.0.0.0:7000"}, cfg.P2P.V2.ListenAddresses)
+	require.Nil(t, cfg.TelemetryIngress)
+
+	// fields are changed before rendering, ex.: profiling is turned off
+	cfg.Pyroscope = nil
+	cfg.Log.Level = "info"
+	got, err := cfg.Render()
+	require.NoError(t, err)
+	require.NotContains(t, got, "[Pyroscope]")
+	require.NotContains(t, got, "[TelemetryIngress]")
+	require.Contains(t, got, "Level = 'info'")
+}
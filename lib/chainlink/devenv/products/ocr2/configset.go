@@ -0,0 +1,132 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+	"github.com/smartcontractkit/libocr/offchainreporting2/types"
+	"github.com/smartcontractkit/libocr/offchainreporting2plus/chains/evmutil"
+)
+
+// configSetFromReceipt returns ConfigSet event emitted by the aggregator in SetConfig transaction receipt
+func configSetFromReceipt(ocr2i *ocr2aggregator.OCR2Aggregator, aggregator common.Address, receipt *gethtypes.Receipt) (*ocr2aggregator.OCR2AggregatorConfigSet, error) {
+	for _, l := range receipt.Logs {
+		if l.Address != aggregator {
+			continue
+		}
+		if ev, err := ocr2i.ParseConfigSet(*l); err == nil {
+			return ev, nil
+		}
+	}
+	return nil, fmt.Errorf("no ConfigSet event of aggregator %s in transaction %s", aggregator.Hex(), receipt.TxHash.Hex())
+}
+
+// ComputeConfigDigest computes the digest of a stored config offline the same way aggregator does on setConfig,
+// compare it with the on-chain digest to check env-out.toml matches the deployed config
+func ComputeConfigDigest(contractAddr common.Address, chainID string, cfg *OCRv2Config) (types.ConfigDigest, error) {
+	if cfg == nil {
+		return types.ConfigDigest{}, errors.New("no OCR2 config to compute digest from")
+	}
+	id, err := strconv.ParseUint(chainID, 10, 64)
+	if err != nil {
+		return types.ConfigDigest{}, fmt.Errorf("invalid chain ID %q: %w", chainID, err)
+	}
+	return configDigest(context.Background(), id, contractAddr, &ocr2aggregator.OCR2AggregatorConfigSet{
+		ConfigCount:           cfg.ConfigCount,
+		Signers:               cfg.Signers,
+		Transmitters:          cfg.Transmitters,
+		F:                     cfg.F,
+		OnchainConfig:         cfg.OnchainConfig,
+		OffchainConfigVersion: cfg.OffchainConfigVersion,
+		OffchainConfig:        cfg.OffchainConfig,
+	})
+}
+
+// configDigest computes the digest of config from ConfigSet event the same way aggregator does
+func configDigest(ctx context.Context, chainID uint64, aggregator common.Address, ev *ocr2aggregator.OCR2AggregatorConfigSet) (types.ConfigDigest, error) {
+	signers := make([]types.OnchainPublicKey, 0, len(ev.Signers))
+	for _, s := range ev.Signers {
+		signers = append(signers, s.Bytes())
+	}
+	transmitters := make([]types.Account, 0, len(ev.Transmitters))
+	for _, t := range ev.Transmitters {
+		transmitters = append(transmitters, types.Account(t.Hex()))
+	}
+	digester := evmutil.EVMOffchainConfigDigester{ChainID: chainID, ContractAddress: aggregator}
+	return digester.ConfigDigest(ctx, types.ContractConfig{
+		ConfigCount:           ev.ConfigCount,
+		Signers:               signers,
+		Transmitters:          transmitters,
+		F:                     ev.F,
+		OnchainConfig:         ev.OnchainConfig,
+		OffchainConfigVersion: ev.OffchainConfigVersion,
+		OffchainConfig:        ev.OffchainConfig,
+	})
+}
+
+// ConfigDetails is aggregator config as returned by LatestConfigDetails
+type ConfigDetails = struct {
+	ConfigCount  uint32
+	BlockNumber  uint32
+	ConfigDigest [32]byte
+}
+
+// ConfigDigestAndEpoch is aggregator config digest and epoch as returned by LatestConfigDigestAndEpoch
+type ConfigDigestAndEpoch = struct {
+	ScanLogs     bool
+	ConfigDigest [32]byte
+	Epoch        uint32
+}
+
+// ConfigReader reads the config aggregator currently has, ex.: *ocr2aggregator.OCR2Aggregator
+type ConfigReader interface {
+	LatestConfigDetails(opts *bind.CallOpts) (ConfigDetails, error)
+	LatestConfigDigestAndEpoch(opts *bind.CallOpts) (ConfigDigestAndEpoch, error)
+}
+
+// verifyConfigSet parses ConfigSet event from SetConfig receipt and checks the aggregator reports it as its latest config,
+// LatestConfigDetails must return its digest, count and block and LatestConfigDigestAndEpoch the same digest nodes read
+func verifyConfigSet(ctx context.Context, ocr2i *ocr2aggregator.OCR2Aggregator, r ConfigReader, aggregator common.Address, receipt *gethtypes.Receipt) (*ocr2aggregator.OCR2AggregatorConfigSet, error) {
+	ev, err := configSetFromReceipt(ocr2i, aggregator, receipt)
+	if err != nil {
+		return nil, err
+	}
+	got := types.ConfigDigest(ev.ConfigDigest)
+	d, err := r.LatestConfigDetails(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("could not read latest config details: %w", err)
+	}
+	if latest := types.ConfigDigest(d.ConfigDigest); latest != got {
+		return nil, fmt.Errorf("aggregator latest config digest %s doesn't match ConfigSet digest %s", latest.Hex(), got.Hex())
+	}
+	if uint64(d.ConfigCount) != ev.ConfigCount {
+		return nil, fmt.Errorf("aggregator latest config count %d doesn't match ConfigSet config count %d", d.ConfigCount, ev.ConfigCount)
+	}
+	if uint64(d.BlockNumber) != ev.Raw.BlockNumber {
+		return nil, fmt.Errorf("aggregator latest config block %d doesn't match ConfigSet block %d", d.BlockNumber, ev.Raw.BlockNumber)
+	}
+	de, err := r.LatestConfigDigestAndEpoch(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("could not read latest config digest and epoch: %w", err)
+	}
+	if latest := types.ConfigDigest(de.ConfigDigest); latest != got {
+		return nil, fmt.Errorf("aggregator latest config digest and epoch digest %s doesn't match ConfigSet digest %s", latest.Hex(), got.Hex())
+	}
+	return ev, nil
+}
+
+// waitConfigSet waits for SetConfig transaction to be mined and returns its ConfigSet event verified against the aggregator
+func waitConfigSet(ctx context.Context, c *ethclient.Client, ocr2i *ocr2aggregator.OCR2Aggregator, aggregator common.Address, tx *gethtypes.Transaction, w WaitConfig) (*ocr2aggregator.OCR2AggregatorConfigSet, error) {
+	receipt, err := WaitMined(ctx, c, tx, w)
+	if err != nil {
+		return nil, err
+	}
+	return verifyConfigSet(ctx, ocr2i, ocr2i, aggregator, receipt)
+}
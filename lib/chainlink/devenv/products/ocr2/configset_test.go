@@ -0,0 +1,212 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+	"github.com/smartcontractkit/libocr/offchainreporting2/types"
+	"github.com/smartcontractkit/libocr/offchainreporting2plus/chains/evmutil"
+	"github.com/stretchr/testify/require"
+)
+
+// configSetLog packs ConfigSet event the same way aggregator emits it
+func configSetLog(t *testing.T, aggregator common.Address, digest types.ConfigDigest, cc types.ContractConfig) *gethtypes.Log {
+	t.Helper()
+	parsed, err := abi.JSON(strings.NewReader(ocr2aggregator.OCR2AggregatorABI))
+	require.NoError(t, err)
+	ev := parsed.Events["ConfigSet"]
+	signers := make([]common.Address, 0, len(cc.Signers))
+	for _, s := range cc.Signers {
+		signers = append(signers, common.BytesToAddress(s))
+	}
+	transmitters := make([]common.Address, 0, len(cc.Transmitters))
+	for _, tr := range cc.Transmitters {
+		transmitters = append(transmitters, common.HexToAddress(string(tr)))
+	}
+	data, err := ev.Inputs.NonIndexed().Pack(
+		uint32(0),
+		[32]byte(digest),
+		cc.ConfigCount,
+		signers,
+		transmitters,
+		cc.F,
+		cc.OnchainConfig,
+		cc.OffchainConfigVersion,
+		cc.OffchainConfig,
+	)
+	require.NoError(t, err)
+	return &gethtypes.Log{Address: aggregator, Topics: []common.Hash{ev.ID}, Data: data}
+}
+
+// configReader returns fixed aggregator config
+type configReader struct {
+	details ConfigDetails
+	latest  ConfigDigestAndEpoch
+	err     error
+}
+
+func (r *configReader) LatestConfigDetails(_ *bind.CallOpts) (ConfigDetails, error) {
+	return r.details, r.err
+}
+
+func (r *configReader) LatestConfigDigestAndEpoch(_ *bind.CallOpts) (ConfigDigestAndEpoch, error) {
+	return r.latest, r.err
+}
+
+func TestVerifyConfigSet(t *testing.T) {
+	ctx := context.Background()
+	chainID := uint64(1337)
+	aggregator := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	ocr2i, err := ocr2aggregator.NewOCR2Aggregator(aggregator, nil)
+	require.NoError(t, err)
+
+	cc := types.ContractConfig{
+		ConfigCount: 2,
+		Signers: []types.OnchainPublicKey{
+			common.HexToAddress("0x0000000000000000000000000000000000000011").Bytes(),
+			common.HexToAddress("0x0000000000000000000000000000000000000012").Bytes(),
+		},
+		Transmitters: []types.Account{
+			types.Account(common.HexToAddress("0x0000000000000000000000000000000000000021").Hex()),
+			types.Account(common.HexToAddress("0x0000000000000000000000000000000000000022").Hex()),
+		},
+		F:                     1,
+		OnchainConfig:         []byte{1, 2, 3},
+		OffchainConfigVersion: 2,
+		OffchainConfig:        []byte{4, 5, 6},
+	}
+	digest, err := evmutil.EVMOffchainConfigDigester{ChainID: chainID, ContractAddress: aggregator}.ConfigDigest(ctx, cc)
+	require.NoError(t, err)
+	stale := types.ConfigDigest{1}
+	log := func(addr common.Address) *gethtypes.Log {
+		l := configSetLog(t, addr, digest, cc)
+		l.BlockNumber = 10
+		return l
+	}
+	onchain := func() *configReader {
+		return &configReader{
+			details: ConfigDetails{ConfigCount: 2, BlockNumber: 10, ConfigDigest: digest},
+			latest:  ConfigDigestAndEpoch{ConfigDigest: digest},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		logs    []*gethtypes.Log
+		reader  func(r *configReader)
+		wantErr string
+	}{
+		{
+			name: "aggregator reports the config",
+			logs: []*gethtypes.Log{log(aggregator)},
+		},
+		{
+			name: "event of another contract is ignored",
+			logs: []*gethtypes.Log{log(other), log(aggregator)},
+		},
+		{
+			name:    "no event",
+			logs:    []*gethtypes.Log{log(other)},
+			wantErr: "no ConfigSet event",
+		},
+		{
+			name:    "latest config details digest differs",
+			logs:    []*gethtypes.Log{log(aggregator)},
+			reader:  func(r *configReader) { r.details.ConfigDigest = stale },
+			wantErr: "aggregator latest config digest",
+		},
+		{
+			name:    "config count differs",
+			logs:    []*gethtypes.Log{log(aggregator)},
+			reader:  func(r *configReader) { r.details.ConfigCount = 1 },
+			wantErr: "config count 1 doesn't match ConfigSet config count 2",
+		},
+		{
+			name:    "config block differs",
+			logs:    []*gethtypes.Log{log(aggregator)},
+			reader:  func(r *configReader) { r.details.BlockNumber = 9 },
+			wantErr: "config block 9 doesn't match ConfigSet block 10",
+		},
+		{
+			name:    "latest config digest and epoch digest differs",
+			logs:    []*gethtypes.Log{log(aggregator)},
+			reader:  func(r *configReader) { r.latest.ConfigDigest = stale },
+			wantErr: "digest and epoch digest",
+		},
+		{
+			name:    "aggregator can't be read",
+			logs:    []*gethtypes.Log{log(aggregator)},
+			reader:  func(r *configReader) { r.err = errors.New("rpc is down") },
+			wantErr: "rpc is down",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := onchain()
+			if tc.reader != nil {
+				tc.reader(r)
+			}
+			ev, err := verifyConfigSet(ctx, ocr2i, r, aggregator, &gethtypes.Receipt{Logs: tc.logs})
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, digest, types.ConfigDigest(ev.ConfigDigest))
+			require.Equal(t, cc.ConfigCount, ev.ConfigCount)
+			require.Equal(t, cc.F, ev.F)
+			require.Equal(t, cc.OffchainConfig, ev.OffchainConfig)
+		})
+	}
+}
+
+func TestComputeConfigDigest(t *testing.T) {
+	aggregator := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	cfg := &OCRv2Config{
+		ConfigCount: 1,
+		Signers: []common.Address{
+			common.HexToAddress("0x0000000000000000000000000000000000000011"),
+			common.HexToAddress("0x0000000000000000000000000000000000000012"),
+			common.HexToAddress("0x0000000000000000000000000000000000000013"),
+			common.HexToAddress("0x0000000000000000000000000000000000000014"),
+		},
+		Transmitters: []common.Address{
+			common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+			common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"),
+			common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906"),
+			common.HexToAddress("0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65"),
+		},
+		F:                     1,
+		OnchainConfig:         []byte{1, 2, 3},
+		OffchainConfigVersion: 2,
+		OffchainConfig:        []byte{4, 5, 6},
+	}
+
+	// known-good digest, keccak256 of ABI encoded config with EVM digest prefix 0x0001
+	digest, err := ComputeConfigDigest(aggregator, "1337", cfg)
+	require.NoError(t, err)
+	require.Equal(t, "000145368fd73182fbc6ab073f487a6ead1922f0cb7b0ce3b9107e9ad2859b18", digest.Hex())
+
+	// every input is part of the digest
+	other, err := ComputeConfigDigest(aggregator, "1338", cfg)
+	require.NoError(t, err)
+	require.NotEqual(t, digest, other)
+	next := *cfg
+	next.ConfigCount = 2
+	other, err = ComputeConfigDigest(aggregator, "1337", &next)
+	require.NoError(t, err)
+	require.NotEqual(t, digest, other)
+
+	_, err = ComputeConfigDigest(aggregator, "anvil", cfg)
+	require.ErrorContains(t, err, `invalid chain ID "anvil"`)
+	_, err = ComputeConfigDigest(aggregator, "1337", nil)
+	require.ErrorContains(t, err, "no OCR2 config")
+}
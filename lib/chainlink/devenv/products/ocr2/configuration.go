@@ -2,10 +2,12 @@ package ocr2
 
 import (
 	"context"
-	"crypto/ed25519"
+	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"os"
 	"strings"
@@ -14,22 +16,19 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/go-resty/resty/v2"
 	"github.com/google/uuid"
-	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
 	"github.com/smartcontractkit/libocr/offchainreporting2/confighelper"
-	"github.com/smartcontractkit/libocr/offchainreporting2/reportingplugin/median"
 	"github.com/smartcontractkit/libocr/offchainreporting2/types"
 	"golang.org/x/sync/errgroup"
 	"gopkg.in/guregu/null.v4"
 
-	"github.com/smartcontractkit/chainlink-evm/gethwrappers/shared/generated/link_token"
 	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
 	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
 	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/fake"
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
 	"github.com/smartcontractkit/chainlink/devenv/products"
 
 	nodeset "github.com/smartcontractkit/chainlink-testing-framework/framework/components/simple_node_set"
@@ -40,31 +39,210 @@ const (
 	ConfigureProductContractsJobs
 )
 
+const (
+	DefaultLogPollInterval    = "1s"
+	DefaultBlockBackfillDepth = 100
+	DefaultP2PPort            = 6690
+)
+
 var L = log.Output(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.DebugLevel).With().Fields(map[string]any{"component": "ocr2"}).Logger()
 
+// ctxLogger returns the logger injected with products.WithLogger, L if there is none
+func ctxLogger(ctx context.Context) *zerolog.Logger {
+	return products.Logger(ctx, &L)
+}
+
 type OCR2 struct {
-	OCR2                     *OCRv2OffChainOptions  `toml:"ocr2"`
-	OCR2SetConfig            *OCRv2SetConfigOptions `toml:"ocr2_set_config"`
-	OCR2SetConfigOut         *OCRv2Config           `toml:"ocr2_set_config_out"`
-	OCR2MedianOffchainConfig *MedianOffchainConfig  `toml:"ocr2_median_offchain_config"`
-	EAFake                   *EAFake                `toml:"ea_fake"`
-	Jobs                     *Jobs                  `toml:"jobs"`
-	LinkContractAddress      string                 `toml:"link_contract_address"`
-	CLNodesFundingETH        float64                `toml:"cl_nodes_funding_eth"`
-	CLNodesFundingLink       float64                `toml:"cl_nodes_funding_link"`
-	ChainFinalityDepth       int64                  `toml:"chain_finality_depth"`
-	VerificationTimeoutSec   int64                  `toml:"verification_timeout_sec"`
-	GasSettings              *GasSettings           `toml:"gas_settings"`
-	DeployedContracts        *DeployedContracts     `toml:"deployed_contracts"`
+	PluginType               string                    `toml:"plugin_type"`
+	OCR2                     *OCRv2OffChainOptions     `toml:"ocr2"`
+	OCR2SetConfig            *OCRv2SetConfigOptions    `toml:"ocr2_set_config"`
+	OCR2SetConfigOut         *OCRv2Config              `toml:"ocr2_set_config_out"`
+	OCR2MedianOffchainConfig *MedianOffchainConfig     `toml:"ocr2_median_offchain_config"`
+	EAFake                   *EAFake                   `toml:"ea_fake"`
+	Jobs                     *Jobs                     `toml:"jobs"`
+	LinkContractAddress      string                    `toml:"link_contract_address"`
+	CLNodesFundingETH        float64                   `toml:"cl_nodes_funding_eth"`
+	CLNodesFundingLink       float64                   `toml:"cl_nodes_funding_link"`
+	ChainFinalityDepth       int64                     `toml:"chain_finality_depth"`
+	VerificationTimeoutSec   int64                     `toml:"verification_timeout_sec"`
+	GasSettings              *GasSettings              `toml:"gas_settings"`
+	DeployedContracts        *DeployedContracts        `toml:"deployed_contracts"`
+	Chains                   map[string]*ChainSettings `toml:"chains"`
+	P2P                      *P2PSettings              `toml:"p2p"`
+	// ConfigSeed makes generated offchain config reproducible, random secrets are used if it's empty
+	ConfigSeed string `toml:"config_seed"`
+	// VerificationConfirmations is how many blocks behind the head tests read aggregator state, 0 reads the latest block
+	VerificationConfirmations uint64 `toml:"verification_confirmations"`
+	// ForwardingAllowed deploys an authorized forwarder per node and makes OCR2 jobs transmit through it
+	ForwardingAllowed bool `toml:"forwarding_allowed"`
+	// Chaos lists chaos experiments load test applies to CL nodes, one per round
+	Chaos []*ChaosSpec `toml:"chaos"`
+	// SkipSetPayees skips aggregator payees setup, it's faster but payment-gated transmissions may not work
+	SkipSetPayees bool `toml:"skip_set_payees"`
+	// Wait tunes how transactions and the first round are polled, DefaultTxWait is used if it's not set,
+	// transactions on Anvil are confirmed on new heads unless confirm is set
+	Wait *WaitConfig `toml:"wait"`
+	// Telemetry points nodes and jobs at a telemetry ingress, it's disabled if not set
+	Telemetry *Telemetry `toml:"telemetry"`
+	// Bootstrap selects the node set and nodes running bootstrap jobs, node 0 of the worker node set is used if not set
+	Bootstrap *Bootstrap `toml:"bootstrap"`
+	// KeyBundles selects the OCR2 key bundle every worker node signs with, the first EVM bundle is used if not set
+	KeyBundles *KeyBundles `toml:"key_bundles"`
+	// Feeds deploys an aggregator per feed with its own decimals, bounds and description, a single [ocr2.ocr2] feed is deployed if not set
+	Feeds []*Feed `toml:"feeds"`
+	// RequireInitialValue seeds fake EAs with InitialValue before jobs are created, OCR2 jobs don't start until EA returns a value,
+	// it's true if not set
+	RequireInitialValue *bool `toml:"require_initial_value"`
+	// InitialValue is the value fake EAs are seeded with, DefaultEAValue is used if it's 0
+	InitialValue int `toml:"initial_value"`
+	// GasBudget bounds gas used by transmissions in load tests, gas is not checked if not set
+	GasBudget *GasBudget `toml:"gas_budget"`
+	// FeeToken replaces LINK transmitters are paid in, standard LINK is deployed if not set
+	FeeToken *FeeToken `toml:"fee_token"`
+	// NodeFunding overrides cl_nodes_funding_eth and cl_nodes_funding_link by node set index, ex.: to starve a node of ETH,
+	// nodes without an override get global amounts
+	NodeFunding []*NodeFunding `toml:"node_funding"`
+}
+
+// P2PSettings separates the port CL nodes listen on from the port other nodes reach the bootstrap node on,
+// they differ when container ports are remapped
+type P2PSettings struct {
+	ListenPort     int `toml:"listen_port"`
+	AdvertisedPort int `toml:"advertised_port"`
+}
+
+// p2pSettings returns validated P2P ports, unset ports fall back to DefaultP2PPort
+func (o *OCR2) p2pSettings() (*P2PSettings, error) {
+	p := &P2PSettings{ListenPort: DefaultP2PPort, AdvertisedPort: DefaultP2PPort}
+	if o.P2P != nil {
+		p.ListenPort = defaults.Coalesce(o.P2P.ListenPort, DefaultP2PPort)
+		p.AdvertisedPort = defaults.Coalesce(o.P2P.AdvertisedPort, DefaultP2PPort)
+	}
+	for name, port := range map[string]int{"listen_port": p.ListenPort, "advertised_port": p.AdvertisedPort} {
+		if port < 1 || port > 65535 {
+			return nil, fmt.Errorf("p2p %s must be in range 1-65535, got %d", name, port)
+		}
+	}
+	return p, nil
+}
+
+// ChainSettings are CL node chain settings, keyed by chain ID in config, ex.: [ocr2.chains.1337]
+type ChainSettings struct {
+	LogPollInterval          string `toml:"log_poll_interval"`
+	BlockBackfillDepth       int64  `toml:"block_backfill_depth"`
+	FinalityDepth            int64  `toml:"finality_depth"`
+	MinIncomingConfirmations int64  `toml:"min_incoming_confirmations"`
+}
+
+// backendChainSettings returns default chain settings for a blockchain backend type,
+// Geth (clique) chains are slower and less final than Anvil so they get more conservative defaults
+func (o *OCR2) backendChainSettings(bcType string) *ChainSettings {
+	switch bcType {
+	case "geth":
+		return &ChainSettings{
+			LogPollInterval:          "3s",
+			BlockBackfillDepth:       DefaultBlockBackfillDepth,
+			FinalityDepth:            10,
+			MinIncomingConfirmations: 3,
+		}
+	default:
+		return &ChainSettings{
+			LogPollInterval:          DefaultLogPollInterval,
+			BlockBackfillDepth:       DefaultBlockBackfillDepth,
+			FinalityDepth:            o.ChainFinalityDepth,
+			MinIncomingConfirmations: 1,
+		}
+	}
+}
+
+// chainSettings returns validated chain settings for chainID, unset fields fall back to blockchain backend defaults
+func (o *OCR2) chainSettings(bcType, chainID string) (*ChainSettings, error) {
+	cs := o.backendChainSettings(bcType)
+	if override, ok := o.Chains[chainID]; ok && override != nil {
+		cs.LogPollInterval = defaults.Coalesce(override.LogPollInterval, cs.LogPollInterval)
+		cs.BlockBackfillDepth = defaults.Coalesce(override.BlockBackfillDepth, cs.BlockBackfillDepth)
+		cs.FinalityDepth = defaults.Coalesce(override.FinalityDepth, cs.FinalityDepth)
+		cs.MinIncomingConfirmations = defaults.Coalesce(override.MinIncomingConfirmations, cs.MinIncomingConfirmations)
+	}
+	interval, err := time.ParseDuration(cs.LogPollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log_poll_interval for chain %s: %w", chainID, err)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("log_poll_interval for chain %s must be positive, got %s", chainID, cs.LogPollInterval)
+	}
+	if cs.BlockBackfillDepth < 0 {
+		return nil, fmt.Errorf("block_backfill_depth for chain %s must be non-negative, got %d", chainID, cs.BlockBackfillDepth)
+	}
+	if cs.FinalityDepth < 0 {
+		return nil, fmt.Errorf("finality_depth for chain %s must be non-negative, got %d", chainID, cs.FinalityDepth)
+	}
+	if cs.MinIncomingConfirmations < 1 {
+		return nil, fmt.Errorf("min_incoming_confirmations for chain %s must be positive, got %d", chainID, cs.MinIncomingConfirmations)
+	}
+	return cs, nil
 }
 
 type DeployedContracts struct {
 	OCRv2AggregatorAddr string `toml:"ocr2_aggregator_address"`
+	// Forwarders maps node transmitter addresses to their authorized forwarders, empty if forwarding is disabled
+	Forwarders map[string]string `toml:"forwarders"`
+	// Feeds lists aggregators of every feed, the first one is also OCRv2AggregatorAddr
+	Feeds []*DeployedFeed `toml:"feeds"`
 }
 
 type GasSettings struct {
 	FeeCapMultiplier int64 `toml:"fee_cap_multiplier"`
 	TipCapMultiplier int64 `toml:"tip_cap_multiplier"`
+	// Deploy, SetConfig and Fund override default multipliers for their transaction type
+	Deploy    *GasMultipliers `toml:"deploy"`
+	SetConfig *GasMultipliers `toml:"set_config"`
+	Fund      *GasMultipliers `toml:"fund"`
+	// Guard holds off deploy, set config and fund transactions during base fee spikes, transactions are not guarded if unset
+	Guard *GasGuard `toml:"guard"`
+}
+
+// guard returns gas guard, nil if gas settings or guard are not set
+func (g *GasSettings) guard() *GasGuard {
+	if g == nil {
+		return nil
+	}
+	return g.Guard
+}
+
+// GasMultipliers are EIP1559 multipliers of a transaction type, unset (0) values fall back to defaults
+type GasMultipliers struct {
+	FeeCapMultiplier int64 `toml:"fee_cap_multiplier"`
+	TipCapMultiplier int64 `toml:"tip_cap_multiplier"`
+}
+
+// GasOperation is a transaction type gas multipliers can be overridden for
+type GasOperation string
+
+const (
+	// GasOpDeploy is contract deployment and setup
+	GasOpDeploy GasOperation = "deploy"
+	// GasOpSetConfig is aggregator payees and config changes
+	GasOpSetConfig GasOperation = "set_config"
+	// GasOpFund is ETH and LINK funding of CL nodes
+	GasOpFund GasOperation = "fund"
+)
+
+// Multipliers returns fee and tip cap multipliers of an operation, default multipliers are used for unset overrides
+func (g *GasSettings) Multipliers(op GasOperation) (int64, int64) {
+	var override *GasMultipliers
+	switch op {
+	case GasOpDeploy:
+		override = g.Deploy
+	case GasOpSetConfig:
+		override = g.SetConfig
+	case GasOpFund:
+		override = g.Fund
+	}
+	if override == nil {
+		return g.FeeCapMultiplier, g.TipCapMultiplier
+	}
+	return defaults.Coalesce(override.FeeCapMultiplier, g.FeeCapMultiplier), defaults.Coalesce(override.TipCapMultiplier, g.TipCapMultiplier)
 }
 
 type MedianOffchainConfig struct {
@@ -75,14 +253,120 @@ type MedianOffchainConfig struct {
 	AlphaAcceptInfinite bool   `toml:"alpha_accept_infinite"`
 }
 
+const (
+	JobTypeBootstrap = "bootstrap"
+	JobTypeOCR2      = "offchainreporting2"
+)
+
 type Jobs struct {
 	MaxTaskDurationSec int64 `toml:"max_task_duration_sec"`
+	// MaxTaskDurationSecByType overrides MaxTaskDurationSec per job type, ex.: offchainreporting2 = 120
+	MaxTaskDurationSecByType map[string]int64 `toml:"max_task_duration_sec_by_type"`
+	// ContractConfigConfirmations is how many blocks config must be confirmed for, node default is used if unset
+	ContractConfigConfirmations int64 `toml:"contract_config_confirmations"`
+	// ObservationSource sets error handling of OCR job pipeline, single bridge pipeline is used if unset
+	ObservationSource *ObservationSource `toml:"observation_source"`
+	// BridgeHeaders are sent with every bridge request, ex.: Authorization = "Bearer token" for EAs that require auth
+	BridgeHeaders map[string]string `toml:"bridge_headers"`
+	// FakeServer is the index of the environment fake server EA and juels bridges point to,
+	// 0 is fake_server or the first of fake_servers
+	FakeServer int `toml:"fake_server"`
+	// ViaJD proposes jobs through Job Distributor and approves them on nodes instead of creating them directly
+	ViaJD bool `toml:"via_jd"`
+	// ProposalWait tunes how node connection to JD and proposed jobs are awaited, DefaultJDProposalWait is used if it's not set,
+	// it's separate from [ocr2.wait], which is meant for transactions
+	ProposalWait *WaitConfig `toml:"proposal_wait"`
+	// Relay sets relay and relay config of bootstrap and worker jobs, evm relay on the environment chain is used if unset
+	Relay *Relay `toml:"relay"`
+}
+
+// viaJD reports whether jobs are proposed through Job Distributor
+func (j *Jobs) viaJD() bool {
+	return j != nil && j.ViaJD
+}
+
+// proposalWait returns how JD connection and job proposals are awaited
+func (j *Jobs) proposalWait() WaitConfig {
+	if j == nil {
+		return DefaultJDProposalWait
+	}
+	return j.ProposalWait.Or(DefaultJDProposalWait)
+}
+
+// FakeServerIndex returns the index of the fake server jobs use
+func (j *Jobs) FakeServerIndex() int {
+	if j == nil {
+		return 0
+	}
+	return j.FakeServer
+}
+
+// SelectFake returns the fake server jobs use, an index out of range of fakes is an error
+func (j *Jobs) SelectFake(fakes []*fake.Input) (*fake.Input, error) {
+	idx := j.FakeServerIndex()
+	if idx < 0 || idx >= len(fakes) {
+		return nil, fmt.Errorf("jobs fake_server index %d is out of range, there are %d fake servers", idx, len(fakes))
+	}
+	return fakes[idx], nil
+}
+
+// bridgeHeaders returns headers added to bridge tasks, nil means no extra headers
+func (j *Jobs) bridgeHeaders() map[string]string {
+	if j == nil {
+		return nil
+	}
+	return j.BridgeHeaders
+}
+
+// observationSource returns pipeline error handling settings, nil means default single bridge pipeline
+func (j *Jobs) observationSource() *ObservationSource {
+	if j == nil {
+		return nil
+	}
+	return j.ObservationSource
+}
+
+// contractConfigConfirmations returns validated config confirmations, 0 means node default
+func (j *Jobs) contractConfigConfirmations() (uint16, error) {
+	if j == nil {
+		return 0, nil
+	}
+	if j.ContractConfigConfirmations < 0 || j.ContractConfigConfirmations > math.MaxUint16 {
+		return 0, fmt.Errorf("contract_config_confirmations must be in range 0-%d, got %d", math.MaxUint16, j.ContractConfigConfirmations)
+	}
+	return uint16(j.ContractConfigConfirmations), nil
+}
+
+// maxTaskDuration returns max task duration for a job type, per-type value takes precedence over the global one.
+// Bootstrap jobs have no pipeline so they only get a duration when it's set explicitly for their type.
+func (j *Jobs) maxTaskDuration(jobType string) (string, error) {
+	if j == nil {
+		return "", nil
+	}
+	if sec, ok := j.MaxTaskDurationSecByType[jobType]; ok {
+		if sec <= 0 {
+			return "", fmt.Errorf("max_task_duration_sec_by_type for job type %s must be positive, got %d", jobType, sec)
+		}
+		return (time.Duration(sec) * time.Second).String(), nil
+	}
+	if jobType == JobTypeBootstrap {
+		return "", nil
+	}
+	if j.MaxTaskDurationSec <= 0 {
+		return "", fmt.Errorf("max_task_duration_sec must be positive, got %d", j.MaxTaskDurationSec)
+	}
+	return (time.Duration(j.MaxTaskDurationSec) * time.Second).String(), nil
 }
 
 type EAFake struct {
-	MinValue         int64 `toml:"min_value"`
-	MaxValue         int64 `toml:"max_value"`
-	ChangesPerMinute int64 `toml:"changes_per_minute"`
+	MinValue          int64 `toml:"min_value"`
+	MaxValue          int64 `toml:"max_value"`
+	ChangesPerMinute  int64 `toml:"changes_per_minute"`
+	RequestTimeoutSec int64 `toml:"request_timeout_sec"`
+	// Retries is how many times failed fake server requests are retried, 0 uses the default, negative disables retries
+	Retries int `toml:"retry_count"`
+	// SkipProbe disables the check that a worker node can reach fake server docker URL before jobs are created
+	SkipProbe bool `toml:"skip_probe"`
 }
 
 type ConfigPhase int
@@ -123,10 +407,24 @@ type OCRv2Config struct {
 	OffchainConfig        []byte
 	OffchainConfigVersion uint64
 	F                     uint8
+	// ConfigDigest is the on-chain digest of the applied config
+	ConfigDigest string
+	// RequestHash identifies options and oracle set the config was generated from
+	RequestHash string
+	// ConfigSetTxHash and ConfigSetBlock locate SetConfig transaction that emitted ConfigSet event
+	ConfigSetTxHash string
+	ConfigSetBlock  uint64
+	// ConfigCount is the number of configs set on the aggregator including this one, it's part of the digest
+	ConfigCount uint64
+	// ConfigSet is the event emitted by the aggregator, it's not stored
+	ConfigSet *ocr2aggregator.OCR2AggregatorConfigSet `toml:"-"`
 }
 
 type Configurator struct {
 	OCR2 *OCR2 `toml:"ocr2"`
+	// jd and jdWSRPC are set by UseJobDistributor, jobs are proposed through JD if jobs.via_jd is set
+	jd      products.JobDistributor
+	jdWSRPC string
 }
 
 func NewOCR2Configurator() *Configurator {
@@ -138,6 +436,45 @@ func (m *Configurator) Load() error {
 	if err != nil {
 		return fmt.Errorf("failed to load product config: %w", err)
 	}
+	if err := validateChaos(cfg.OCR2.Chaos); err != nil {
+		return err
+	}
+	if cfg.OCR2.Wait != nil {
+		if err := cfg.OCR2.Wait.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := cfg.OCR2.Telemetry.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.OCR2.KeyBundles.Validate(); err != nil {
+		return err
+	}
+	if err := validateFeeds(cfg.OCR2.Feeds); err != nil {
+		return err
+	}
+	if err := cfg.OCR2.GasSettings.guard().Validate(); err != nil {
+		return err
+	}
+	if err := cfg.OCR2.GasBudget.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.OCR2.FeeToken.Validate(); err != nil {
+		return err
+	}
+	if err := validateNodeFunding(cfg.OCR2.NodeFunding); err != nil {
+		return err
+	}
+	if cfg.OCR2.Jobs != nil {
+		if err := cfg.OCR2.Jobs.Relay.validate(); err != nil {
+			return err
+		}
+		if w := cfg.OCR2.Jobs.ProposalWait; w != nil {
+			if err := w.Validate(); err != nil {
+				return fmt.Errorf("invalid jobs proposal_wait: %w", err)
+			}
+		}
+	}
 	m.OCR2 = cfg.OCR2
 	return nil
 }
@@ -149,97 +486,181 @@ func (m *Configurator) Store(path string) error {
 	return nil
 }
 
+// VerifyLive waits for the first aggregator round, so the feed is known to be working when the environment is up,
+// verification_timeout_sec = 0 disables the check
+func (m *Configurator) VerifyLive(ctx context.Context, bc *blockchain.Input) error {
+	if m.OCR2.VerificationTimeoutSec <= 0 {
+		ctxLogger(ctx).Info().Msg("verification_timeout_sec is not set, skipping feed check")
+		return nil
+	}
+	aggregators, err := m.feedAggregators(ctx, bc)
+	if err != nil {
+		return err
+	}
+	w := m.verificationWait()
+	for i, feed := range m.OCR2.DeployedContracts.AllFeeds() {
+		ctxLogger(ctx).Info().Str("Feed", feed.Name).Str("Timeout", w.Timeout().String()).Msg("Waiting for the first OCR2 round")
+		rd, err := WaitForFirstRound(ctx, aggregators[i], w)
+		if err != nil {
+			return fmt.Errorf("feed %s: %w", feed.Name, err)
+		}
+		ctxLogger(ctx).Info().
+			Str("Feed", feed.Name).
+			Str("RoundID", rd.RoundId.String()).
+			Msgf("Feed is live, first answer: %s", FormatAnswer(rd.Answer, feed.Decimals))
+	}
+	return nil
+}
+
+// VerifyRounds waits until every feed reports n rounds, so the feed is known to keep reporting and not only to have
+// reported once, it's called after VerifyLive and times out after verification_timeout_sec if it's set
+func (m *Configurator) VerifyRounds(ctx context.Context, bc *blockchain.Input, n int) error {
+	aggregators, err := m.feedAggregators(ctx, bc)
+	if err != nil {
+		return err
+	}
+	w := m.verificationWait()
+	for i, feed := range m.OCR2.DeployedContracts.AllFeeds() {
+		ctxLogger(ctx).Info().Str("Feed", feed.Name).Int("Rounds", n).Str("Timeout", w.Timeout().String()).Msg("Waiting for OCR2 rounds")
+		rd, err := WaitForRounds(ctx, aggregators[i], n, w)
+		if err != nil {
+			return fmt.Errorf("feed %s: %w", feed.Name, err)
+		}
+		ctxLogger(ctx).Info().
+			Str("Feed", feed.Name).
+			Str("RoundID", rd.RoundId.String()).
+			Msgf("Feed reported %d rounds, latest answer: %s", n, FormatAnswer(rd.Answer, feed.Decimals))
+	}
+	return nil
+}
+
+// ValidateRounds checks n rounds fit into verification timeout, EA value doesn't change while rounds are verified,
+// so after the first round the feed only reports on median delta_sec heartbeat
+func (m *Configurator) ValidateRounds(n int) error {
+	mc := m.OCR2.OCR2MedianOffchainConfig
+	if n <= 1 || mc == nil {
+		return nil
+	}
+	if mc.DeltaCSec == 0 {
+		return fmt.Errorf("require_rounds %d needs heartbeat rounds, but median delta_sec is 0, EA value doesn't change during verification, so only the first round is reported", n)
+	}
+	need := time.Duration(n-1) * time.Duration(mc.DeltaCSec) * time.Second
+	if timeout := m.verificationWait().Timeout(); timeout > 0 && need >= timeout {
+		return fmt.Errorf("require_rounds %d takes at least %s with median delta_sec %d, but verification times out after %s, lower require_rounds or delta_sec, or raise verification_timeout_sec", n, need, mc.DeltaCSec, timeout)
+	}
+	return nil
+}
+
+// feedAggregators connects to aggregators of all deployed feeds in AllFeeds order
+func (m *Configurator) feedAggregators(ctx context.Context, bc *blockchain.Input) ([]*ocr2aggregator.OCR2Aggregator, error) {
+	if m.OCR2.DeployedContracts == nil {
+		return nil, errors.New("no deployed OCR2 aggregator found")
+	}
+	if err := checkBlockchainOut(bc); err != nil {
+		return nil, err
+	}
+	c, _, _, err := ETHClient(ctx, bc.Out.Nodes[0].ExternalHTTPUrl, m.OCR2.GasSettings.FeeCapMultiplier, m.OCR2.GasSettings.TipCapMultiplier)
+	if err != nil {
+		return nil, fmt.Errorf("could not create basic eth client: %w", err)
+	}
+	feeds := m.OCR2.DeployedContracts.AllFeeds()
+	aggregators := make([]*ocr2aggregator.OCR2Aggregator, 0, len(feeds))
+	for _, feed := range feeds {
+		ocr2i, err := ocr2aggregator.NewOCR2Aggregator(common.HexToAddress(feed.Address), c)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to OCR2 aggregator of feed %s: %w", feed.Name, err)
+		}
+		aggregators = append(aggregators, ocr2i)
+	}
+	return aggregators, nil
+}
+
+// verificationWait is how rounds are polled after bring-up,
+// verification_timeout_sec takes precedence over [ocr2.wait] timeout, which is meant for transactions
+func (m *Configurator) verificationWait() WaitConfig {
+	w := m.OCR2.Wait.Or(firstRoundWait)
+	if m.OCR2.VerificationTimeoutSec > 0 {
+		w.TimeoutSec = m.OCR2.VerificationTimeoutSec
+	}
+	return w
+}
+
+// Destroy removes OCR2 resources living outside of environment containers, jobs and contracts are removed
+// together with node set and blockchain, so there is nothing to clean up
+func (m *Configurator) Destroy(ctx context.Context) error {
+	ctxLogger(ctx).Info().Msg("OCR2 has no resources outside of node set and blockchain containers")
+	return nil
+}
+
+// GenerateCLNodesBlockchainConfig renders CLNodeConfig, use CLNodeConfig to change it before rendering
 func (m *Configurator) GenerateCLNodesBlockchainConfig(ctx context.Context, bc *blockchain.Input) (string, error) {
-	L.Info().Msg("Applying default CL nodes configuration")
-	// configure node set and generate CL nodes configs
-	node := bc.Out.Nodes[0]
-	chainID := bc.Out.ChainID
-	netConfig := fmt.Sprintf(`
-       [[EVM]]
-       LogPollInterval = '1s'
-       BlockBackfillDepth = 100
-       LinkContractAddress = '%s'
-       ChainID = '%s'
-       MinIncomingConfirmations = 1
-       MinContractPayment = '0.0000001 link'
-       FinalityDepth = %d
-
-       [[EVM.Nodes]]
-       Name = 'default'
-       WsUrl = '%s'
-       HttpUrl = '%s'
-
-       [Feature]
-       FeedsManager = true
-       LogPoller = true
-       UICSAKeys = true
-       [OCR2]
-       Enabled = true
-       SimulateTransactions = false
-       DefaultTransactionQueueDepth = 1
-       [P2P.V2]
-       Enabled = true
-       ListenAddresses = ['0.0.0.0:6690']
-
-   	   [Log]
-   JSONConsole = true
-   Level = 'debug'
-   [Pyroscope]
-   ServerAddress = 'http://host.docker.internal:4040'
-   Environment = 'local'
-   [WebServer]
-          SessionTimeout = '999h0m0s'
-          HTTPWriteTimeout = '3m'
-   SecureCookies = false
-   HTTPPort = 6688
-   [WebServer.TLS]
-   HTTPSPort = 0
-       [WebServer.RateLimit]
-       Authenticated = 5000
-       Unauthenticated = 5000
-   [JobPipeline]
-   [JobPipeline.HTTPRequest]
-   DefaultTimeout = '1m'
-       [Log.File]
-       MaxSize = '0b'
-`, m.OCR2.LinkContractAddress,
-		chainID,
-		m.OCR2.ChainFinalityDepth,
-		node.InternalWSUrl,
-		node.InternalHTTPUrl,
-	)
-	L.Info().Msg("Nodes network configuration is finished")
+	ctxLogger(ctx).Info().Msg("Applying default CL nodes configuration")
+	cfg, err := m.CLNodeConfig(bc)
+	if err != nil {
+		return "", err
+	}
+	netConfig, err := cfg.Render()
+	if err != nil {
+		return "", err
+	}
+	ctxLogger(ctx).Info().Msg("Nodes network configuration is finished")
 	return netConfig, nil
 }
 
 func (m *Configurator) ConfigureJobsAndContracts(
 	ctx context.Context,
-	fake *fake.Input,
+	fakes []*fake.Input,
 	bc *blockchain.Input,
-	ns *nodeset.Input,
+	nodeSets []*nodeset.Input,
 ) error {
-	L.Info().Msg("Connecting to CL nodes")
-	cl, err := clclient.New(ns.Out.CLNodes)
+	if err := checkBlockchainOut(bc); err != nil {
+		return err
+	}
+	if len(nodeSets) == 0 || nodeSets[0].Out == nil {
+		return errors.New("no worker node set found")
+	}
+	// JD is checked before contracts are deployed, so a missing JD fails fast
+	create, err := m.jobCreator(ctx)
+	if err != nil {
+		return err
+	}
+	// the first node set runs OCR2 jobs, bootstrap nodes are selected by [ocr2.bootstrap]
+	ns := nodeSets[0]
+	topo, err := m.OCR2.Bootstrap.topology(ns, nodeSets)
 	if err != nil {
 		return err
 	}
+	jobsFake, err := m.OCR2.Jobs.SelectFake(fakes)
+	if err != nil {
+		return err
+	}
+	ctxLogger(ctx).Info().Msg("Connecting to CL nodes")
+	cl, err := clclient.New(ns.Out.CLNodes)
+	if err != nil {
+		return fmt.Errorf("could not connect to CL nodes: %w", err)
+	}
 	pkey := getNetworkPrivateKey()
 	if pkey == "" {
 		return errors.New("PRIVATE_KEY environment variable not set")
 	}
 
-	transmitters := make([]common.Address, 0)
-	ethKeyAddresses := make([]string, 0)
-	for i, nc := range cl {
-		addr, cErr := nc.ReadPrimaryETHKey(bc.Out.ChainID)
-		if cErr != nil {
-			return cErr
+	// nodes call fake server by its docker URL, a worker checks it's reachable before contracts and jobs are created
+	if !m.OCR2.EAFake.skipProbe() && len(topo.workers) > 0 {
+		if pErr := probeFakeFromNode(ctx, cl[topo.workers[0]], jobsFake); pErr != nil {
+			return pErr
 		}
-		ethKeyAddresses = append(ethKeyAddresses, addr.Attributes.Address)
-		transmitters = append(transmitters, common.HexToAddress(addr.Attributes.Address))
-		L.Info().
+	}
+	infos, err := CollectNodeInfo(ctx, cl, bc.Out.ChainID, m.OCR2.KeyBundles)
+	if err != nil {
+		return err
+	}
+	transmitters := make([]common.Address, 0, len(infos))
+	for i, info := range infos {
+		transmitters = append(transmitters, info.ETHAddress)
+		ctxLogger(ctx).Info().
 			Int("Idx", i).
-			Str("ETH", addr.Attributes.Address).
+			Str("ETH", info.ETHAddress.Hex()).
+			Str("PeerID", info.P2PPeerID).
 			Msg("Node info")
 	}
 	bcNode := bc.Out.Nodes[0]
@@ -252,120 +673,189 @@ func (m *Configurator) ConfigureJobsAndContracts(
 	if err != nil {
 		return fmt.Errorf("could not create basic eth client: %w", err)
 	}
-	for _, addr := range ethKeyAddresses {
-		if cErr := FundNodeEIP1559(ctx, c, pkey, addr, m.OCR2.CLNodesFundingETH); cErr != nil {
-			return cErr
+	if err := m.OCR2.GasSettings.guard().Await(ctx, c); err != nil {
+		return fmt.Errorf("gas guard wait before funding and deployment failed: %w", err)
+	}
+	txWait := m.OCR2.txWait(bc.Type, bcNode.ExternalWSUrl)
+	// nodes funded with less than others, or not at all, stay transmitters and payees, they just can't afford to transmit
+	ethFunding, linkFunding, err := m.OCR2.nodeFunding(len(transmitters))
+	if err != nil {
+		return err
+	}
+	fundFeeCapMult, fundTipCapMult := m.OCR2.GasSettings.Multipliers(GasOpFund)
+	minETH := make([]*big.Int, len(transmitters))
+	for i, addr := range transmitters {
+		minETH[i], err = ToWei(ethFunding[i])
+		if err != nil {
+			return fmt.Errorf("invalid ETH funding of node %d: %w", i, err)
+		}
+		if minETH[i].Sign() == 0 {
+			ctxLogger(ctx).Warn().Int("Idx", i).Str("ETH", addr.Hex()).Msg("Node ETH funding is 0, node is not funded")
+			continue
+		}
+		if cErr := FundNodeEIP1559(ctx, c, pkey, addr.Hex(), ethFunding[i], fundFeeCapMult, fundTipCapMult, txWait); cErr != nil {
+			return fmt.Errorf("could not fund node %s: %w", addr, cErr)
 		}
 	}
-	ocrv2Config, ocr2Addr, err := m.configureContracts(
+	if err := VerifyBalancesEach(ctx, "ETH", transmitters, minETH, func(ctx context.Context, addr common.Address) (*big.Int, error) {
+		return c.BalanceAt(ctx, addr, nil)
+	}); err != nil {
+		return err
+	}
+	ocrv2Config, deployed, err := m.configureContracts(
 		ctx,
 		c,
 		auth,
 		cl,
+		infos,
 		rootAddr,
 		transmitters,
-		m.OCR2.CLNodesFundingLink,
+		linkFunding,
+		txWait,
 	)
 	if err != nil {
-		return err
+		return fmt.Errorf("could not configure contracts: %w", err)
 	}
 	m.OCR2.OCR2SetConfigOut = ocrv2Config
-	if cErr := m.configureJobs(ctx, fake, bc, ns, cl, ocr2Addr); cErr != nil {
-		return cErr
+	for i, nc := range cl {
+		fwd, ok := deployed.Forwarders[transmitters[i].Hex()]
+		if !ok {
+			continue
+		}
+		if cErr := trackForwarder(ctx, nc, bc.Out.ChainID, fwd); cErr != nil {
+			return fmt.Errorf("could not track forwarder on node %d: %w", i, cErr)
+		}
 	}
-	r := resty.New().SetBaseURL(fake.Out.BaseURLHost)
-
-	_, err = r.R().Post(`/trigger_deviation?result=200`)
-	if err != nil {
-		return fmt.Errorf("could not set ea fake values: %w", err)
+	// jobs don't start without a value, so fakes are seeded before jobs are created
+	for i, f := range fakes {
+		if sErr := m.OCR2.SeedFake(ctx, NewFakeServerClient(f.Out.BaseURLHost, m.OCR2.EAFake)); sErr != nil {
+			return fmt.Errorf("could not seed ea fake %d: %w", i, sErr)
+		}
 	}
-	L.Info().
-		Msg("Setting fake external adapter (data feed) values")
-	m.OCR2.DeployedContracts = &DeployedContracts{OCRv2AggregatorAddr: ocr2Addr}
+	for _, feed := range deployed.Feeds {
+		if cErr := m.configureJobs(ctx, create, jobsFake, bc, topo, cl, infos, feed.Address); cErr != nil {
+			return fmt.Errorf("could not configure jobs of feed %s: %w", feed.Name, cErr)
+		}
+	}
+	m.OCR2.DeployedContracts = deployed
 	return nil
 }
 
-// deployLinkAndMint is a universal action that deploys link token and mints required amount of LINK token for all the nodes.
-func deployLinkAndMint(ctx context.Context, c *ethclient.Client, auth *bind.TransactOpts, rootAddr string, transmitters []common.Address, linkFunding float64) (*link_token.LinkToken, error) {
-	addr, tx, lt, err := link_token.DeployLinkToken(auth, c)
-	if err != nil {
-		return nil, fmt.Errorf("could not create link token contract: %w", err)
+// deployLinkAndMint is a universal action that deploys link token, or fee token configured instead of it, and funds every node
+// with its linkFunding tokens, amounts are scaled by decimals of the token. Deployed tokens are minted, existing ones are transferred from the root account
+func deployLinkAndMint(ctx context.Context, c *ethclient.Client, auth, fundAuth *bind.TransactOpts, rootAddr string, transmitters []common.Address, linkFunding []float64, ft *FeeToken, w WaitConfig) (feeToken, error) {
+	if len(linkFunding) != len(transmitters) {
+		return nil, fmt.Errorf("got %d transmitters but %d funding amounts", len(transmitters), len(linkFunding))
 	}
-	_, err = bind.WaitDeployed(ctx, c, tx)
+	lt, deployed, err := deployFeeToken(ctx, c, auth, ft, w)
 	if err != nil {
 		return nil, err
 	}
-	L.Info().Str("Address", addr.Hex()).Msg("Deployed link token contract")
-	tx, err = lt.GrantMintRole(auth, common.HexToAddress(rootAddr))
+	symbol, err := lt.Symbol(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		return nil, fmt.Errorf("could not grant mint role: %w", err)
+		return nil, fmt.Errorf("could not read fee token symbol: %w", err)
 	}
-	_, err = bind.WaitMined(ctx, c, tx)
+	decimals, err := lt.Decimals(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("could not read %s decimals: %w", symbol, err)
 	}
-	// mint for public keys of nodes directly instead of transferring
-	for _, transmitter := range transmitters {
-		amount := new(big.Float).Mul(big.NewFloat(linkFunding), big.NewFloat(1e18))
-		amountWei, _ := amount.Int(nil)
-		L.Info().Msgf("Minting LINK for transmitter address: %s", transmitter.Hex())
-		tx, err = lt.Mint(auth, transmitter, amountWei)
+	amounts := make([]*big.Int, len(transmitters))
+	for i := range transmitters {
+		amounts[i], err = ToTokenUnits(linkFunding[i], decimals)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s funding of node %d: %w", symbol, i, err)
+		}
+	}
+	if deployed {
+		tx, err := lt.GrantMintRole(auth, common.HexToAddress(rootAddr))
+		if err != nil {
+			return nil, fmt.Errorf("could not grant mint role: %w", err)
+		}
+		_, err = WaitMined(ctx, c, tx, w)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// mint for public keys of nodes directly instead of transferring, tokens the environment can't mint are transferred
+	for i, transmitter := range transmitters {
+		if amounts[i].Sign() == 0 {
+			ctxLogger(ctx).Warn().Int("Idx", i).Msgf("Node %s funding is 0, transmitter address is not funded: %s", symbol, transmitter.Hex())
+			continue
+		}
+		ctxLogger(ctx).Info().Uint8("Decimals", decimals).Bool("Mint", deployed).Msgf("Funding transmitter address with %s: %s", symbol, transmitter.Hex())
+		tx, err := fundWithToken(lt, fundAuth, transmitter, amounts[i], deployed)
 		if err != nil {
-			return nil, fmt.Errorf("could not transfer link token contract: %w", err)
+			return nil, fmt.Errorf("could not fund transmitter %s with %s: %w", transmitter.Hex(), symbol, err)
 		}
-		_, err = bind.WaitMined(ctx, c, tx)
+		_, err = WaitMined(ctx, c, tx, w)
 		if err != nil {
 			return nil, err
 		}
 	}
+	if err := VerifyBalancesEach(ctx, symbol, transmitters, amounts, func(ctx context.Context, addr common.Address) (*big.Int, error) {
+		return lt.BalanceOf(&bind.CallOpts{Context: ctx}, addr)
+	}); err != nil {
+		return nil, err
+	}
 	return lt, nil
 }
 
-func UpdateOCR2ConfigOffChainValues(ctx context.Context, bc *blockchain.Input, o *OCR2, ocr2i *ocr2aggregator.OCR2Aggregator, cl []*clclient.ChainlinkClient, o2 *OCRv2SetConfigOptions) error {
+// UpdateOCR2ConfigOffChainValues applies new set config options to the aggregator and returns the new config digest.
+// Applying the same options to the same oracle set again is a no-op and returns the current digest.
+// o.OCR2SetConfigOut is the config of the primary feed, other feeds keep the config set at deploy.
+func UpdateOCR2ConfigOffChainValues(ctx context.Context, bc *blockchain.Input, o *OCR2, ocr2i *ocr2aggregator.OCR2Aggregator, cl []*clclient.ChainlinkClient, o2 *OCRv2SetConfigOptions) (types.ConfigDigest, error) {
 	if o2 == nil {
-		return nil
+		return LatestConfigDigest(ctx, ocr2i)
+	}
+	if err := checkBlockchainOut(bc); err != nil {
+		return types.ConfigDigest{}, err
 	}
+	feeCapMult, tipCapMult := o.GasSettings.Multipliers(GasOpSetConfig)
 	c, auth, _, err := ETHClient(
 		ctx,
 		bc.Out.Nodes[0].ExternalHTTPUrl,
-		o.GasSettings.FeeCapMultiplier,
-		o.GasSettings.TipCapMultiplier,
+		feeCapMult,
+		tipCapMult,
 	)
 	if err != nil {
-		return fmt.Errorf("could not create basic eth client: %w", err)
+		return types.ConfigDigest{}, fmt.Errorf("could not create basic eth client: %w", err)
+	}
+	if err := o.GasSettings.guard().Await(ctx, c); err != nil {
+		return types.ConfigDigest{}, fmt.Errorf("gas guard wait before set config failed: %w", err)
 	}
 	// generating oracle identities and setting up OCRv2
-	s, ids, err := getOracleIdentities(cl)
-	if err != nil {
-		return fmt.Errorf("could not get oracle identities: %w", err)
-	}
-	signerKeys, transmitterAccounts, f, _, offchainConfigVersion, offchainConfig, err := confighelper.ContractSetConfigArgsForTests(
-		o2.DeltaProgress,
-		o2.DeltaResend,
-		o2.DeltaRound,
-		o2.DeltaGrace,
-		o2.DeltaStage,
-		o2.RMax,
-		s,
-		ids,
-		median.OffchainConfig{
-			AlphaAcceptInfinite: o.OCR2MedianOffchainConfig.AlphaAcceptInfinite,
-			AlphaReportInfinite: o.OCR2MedianOffchainConfig.AlphaReportInfinite,
-			AlphaReportPPB:      o.OCR2MedianOffchainConfig.AlphaReportPPB,
-			AlphaAcceptPPB:      o.OCR2MedianOffchainConfig.AlphaAcceptPPB,
-			DeltaC:              time.Duration(o.OCR2MedianOffchainConfig.DeltaCSec) * time.Second,
-		}.Encode(),
-		nil,
-		o2.MaxDurationQuery,
-		o2.MaxDurationObservation,
-		o2.MaxDurationReport,
-		o2.MaxDurationShouldAcceptFinalizedReport,
-		o2.MaxDurationShouldTransmitAcceptedReport,
-		1,
-		nil, // The median reporting plugin has an empty onchain config
-	)
+	infos, err := CollectNodeInfo(ctx, cl, bc.Out.ChainID, o.KeyBundles)
+	if err != nil {
+		return types.ConfigDigest{}, fmt.Errorf("could not get oracle identities: %w", err)
+	}
+	s, ids := oracleIdentities(infos)
+	codec, err := NewPluginConfigCodec(o)
+	if err != nil {
+		return types.ConfigDigest{}, err
+	}
+	reportingPluginConfig, err := codec.OffchainConfig()
+	if err != nil {
+		return types.ConfigDigest{}, fmt.Errorf("could not encode reporting plugin config: %w", err)
+	}
+	onChainConfig, err := codec.OnchainConfig(ctx)
 	if err != nil {
-		return fmt.Errorf("could not set config: %w", err)
+		return types.ConfigDigest{}, fmt.Errorf("could not encode onchain config: %w", err)
+	}
+	requestHash, err := setConfigRequestHash(o.ConfigSeed, o2, ids, reportingPluginConfig, onChainConfig)
+	if err != nil {
+		return types.ConfigDigest{}, err
+	}
+	current, err := LatestConfigDigest(ctx, ocr2i)
+	if err != nil {
+		return types.ConfigDigest{}, err
+	}
+	if out := o.OCR2SetConfigOut; out != nil && out.RequestHash == requestHash && out.ConfigDigest == current.Hex() {
+		ctxLogger(ctx).Info().Str("ConfigDigest", current.Hex()).Msg("OCR2 config is already applied, skipping")
+		return current, nil
+	}
+	signerKeys, transmitterAccounts, f, offchainConfigVersion, offchainConfig, err := contractSetConfigArgs(o.ConfigSeed, o2, s, ids, reportingPluginConfig)
+	if err != nil {
+		return types.ConfigDigest{}, fmt.Errorf("could not set config: %w", err)
 	}
 	signerAddresses := make([]common.Address, 0)
 	for _, signer := range signerKeys {
@@ -373,89 +863,262 @@ func UpdateOCR2ConfigOffChainValues(ctx context.Context, bc *blockchain.Input, o
 	}
 	transmitterAddresses := make([]common.Address, 0)
 	for _, account := range transmitterAccounts {
-		transmitterAddresses = append(transmitterAddresses, common.HexToAddress(string(account)))
-	}
-	onChainConfig, err := median.StandardOnchainConfigCodec{}.Encode(context.Background(), median.OnchainConfig{Min: o.OCR2.MinimumAnswer, Max: o.OCR2.MaximumAnswer})
-	if err != nil {
-		return fmt.Errorf("could not encode onchain config: %w", err)
+		transmitterAddresses = append(transmitterAddresses, o.DeployedContracts.onchainTransmitter(common.HexToAddress(string(account))))
 	}
 	tx, err := ocr2i.SetConfig(auth, signerAddresses, transmitterAddresses, f, onChainConfig, offchainConfigVersion, offchainConfig)
 	if err != nil {
-		return fmt.Errorf("could not set OCRv2 config: %w", err)
+		return types.ConfigDigest{}, fmt.Errorf("could not set OCRv2 config: %w", err)
 	}
-	_, err = bind.WaitMined(ctx, c, tx)
+	ev, err := waitConfigSet(ctx, c, ocr2i, ocr2i.Address(), tx, o.txWait(bc.Type, bc.Out.Nodes[0].ExternalHTTPUrl))
 	if err != nil {
-		return err
+		return types.ConfigDigest{}, err
 	}
-	return nil
+	if err := verifySigners(ctx, ev.Signers, keyReaders(cl), bundleIDs(infos)); err != nil {
+		return types.ConfigDigest{}, err
+	}
+	digest := types.ConfigDigest(ev.ConfigDigest)
+	o.OCR2SetConfigOut = &OCRv2Config{
+		F:                     f,
+		Signers:               signerAddresses,
+		Transmitters:          transmitterAddresses,
+		OnchainConfig:         onChainConfig,
+		OffchainConfigVersion: offchainConfigVersion,
+		OffchainConfig:        offchainConfig,
+		ConfigDigest:          digest.Hex(),
+		RequestHash:           requestHash,
+		ConfigSetTxHash:       ev.Raw.TxHash.Hex(),
+		ConfigSetBlock:        ev.Raw.BlockNumber,
+		ConfigCount:           ev.ConfigCount,
+		ConfigSet:             ev,
+	}
+	ctxLogger(ctx).Info().Str("ConfigDigest", digest.Hex()).Msg("OCR2 config is applied")
+	return digest, nil
 }
 
-func (m *Configurator) configureContracts(ctx context.Context, c *ethclient.Client, auth *bind.TransactOpts, cl []*clclient.ChainlinkClient, rootAddr string, transmitters []common.Address, linkFunding float64) (*OCRv2Config, string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Minute)
-	defer cancel()
-	L.Info().Msg("Deploying LINK token contract")
-	lt, err := deployLinkAndMint(ctx, c, auth, rootAddr, transmitters, linkFunding)
+// LatestConfigDigest returns digest of the config currently set on the aggregator
+func LatestConfigDigest(ctx context.Context, ocr2i *ocr2aggregator.OCR2Aggregator) (types.ConfigDigest, error) {
+	d, err := ocr2i.LatestConfigDetails(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		return nil, "", fmt.Errorf("could not create link token contract and mint: %w", err)
+		return types.ConfigDigest{}, fmt.Errorf("could not read latest config details: %w", err)
 	}
-	// OCRv2 Aggregator
-	L.Info().Msg("Deploying OCRv2 aggregator contract")
-	opts := m.OCR2.OCR2
-	ocr2addr, tx, ocr2i, err := ocr2aggregator.DeployOCR2Aggregator(auth, c, lt.Address(), opts.MinimumAnswer, opts.MaximumAnswer, common.HexToAddress(""), common.HexToAddress(""), 18, "")
+	return types.ConfigDigest(d.ConfigDigest), nil
+}
+
+// LatestConfigCount returns the number of configs set on the aggregator, it only grows when setConfig is sent
+func LatestConfigCount(ctx context.Context, ocr2i *ocr2aggregator.OCR2Aggregator) (uint32, error) {
+	d, err := ocr2i.LatestConfigDetails(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		return nil, "", fmt.Errorf("could not create ocr2 aggregator contract: %w", err)
+		return 0, fmt.Errorf("could not read latest config details: %w", err)
 	}
-	_, err = bind.WaitDeployed(ctx, c, tx)
+	return d.ConfigCount, nil
+}
+
+// RoundData is aggregator round as returned by LatestRoundData
+type RoundData = struct {
+	RoundId         *big.Int //nolint:revive // we can't change this field in generated binding
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}
+
+// RoundReader reads the latest aggregator round, ex.: *ocr2aggregator.OCR2Aggregator
+type RoundReader interface {
+	LatestRoundData(opts *bind.CallOpts) (RoundData, error)
+}
+
+// firstRoundWait is how WaitForFirstRound polls the latest round if [ocr2.wait] is not set
+var firstRoundWait = WaitConfig{PollIntervalMs: 2000}
+
+// WaitForFirstRound polls the aggregator until the first round is reported and returns it,
+// aggregator returns round 0 with a zero answer until then
+func WaitForFirstRound(ctx context.Context, r RoundReader, w WaitConfig) (RoundData, error) {
+	var rd RoundData
+	err := w.Poll(ctx, func(ctx context.Context) (bool, error) {
+		latest, err := r.LatestRoundData(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return false, err
+		}
+		rd = latest
+		return rd.RoundId != nil && rd.RoundId.Sign() > 0, nil
+	})
 	if err != nil {
-		return nil, "", err
+		return RoundData{}, fmt.Errorf("no OCR2 round reported, it's almost always a misconfiguration, check OCR2 jobs and CL node logs: %w", err)
 	}
-	L.Info().Str("Address", ocr2addr.String()).Msg("Deployed OCRv2 Aggregator contract")
-	tx, err = ocr2i.SetPayees(auth, transmitters, []common.Address{
-		common.HexToAddress(rootAddr),
-		common.HexToAddress(rootAddr),
-		common.HexToAddress(rootAddr),
-		common.HexToAddress(rootAddr),
+	return rd, nil
+}
+
+// WaitForRounds polls the aggregator until it reports at least n rounds and returns the latest one, round IDs of
+// a freshly deployed aggregator count its rounds, the error names how many rounds were seen if it falls short
+func WaitForRounds(ctx context.Context, r RoundReader, n int, w WaitConfig) (RoundData, error) {
+	var rd RoundData
+	want := big.NewInt(int64(n))
+	err := w.Poll(ctx, func(ctx context.Context) (bool, error) {
+		latest, err := r.LatestRoundData(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return false, err
+		}
+		rd = latest
+		return rd.RoundId != nil && rd.RoundId.Cmp(want) >= 0, nil
 	})
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to set payees: %w", err)
+		seen := "0"
+		if rd.RoundId != nil {
+			seen = rd.RoundId.String()
+		}
+		return RoundData{}, fmt.Errorf("only %s of %d required OCR2 rounds reported: %w", seen, n, err)
 	}
-	_, err = bind.WaitMined(ctx, c, tx)
+	return rd, nil
+}
+
+// LatestEpoch returns epoch of the latest transmission, it's updated on every report including heartbeat reports
+// with an unchanged answer, so it shows protocol progress even when the answer is stable
+func LatestEpoch(ctx context.Context, ocr2i *ocr2aggregator.OCR2Aggregator) (uint32, error) {
+	d, err := ocr2i.LatestConfigDigestAndEpoch(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		return nil, "", err
+		return 0, fmt.Errorf("could not read latest config digest and epoch: %w", err)
 	}
-	// generating oracle identities and setting up OCRv2
-	s, ids, err := getOracleIdentities(cl)
-	if err != nil {
-		return nil, "", fmt.Errorf("could not get oracle identities: %w", err)
-	}
-	ocrSetConfig := m.OCR2.OCR2SetConfig
-	signerKeys, transmitterAccounts, f, _, offchainConfigVersion, offchainConfig, err := confighelper.ContractSetConfigArgsForTests(
-		ocrSetConfig.DeltaProgress*time.Second,
-		ocrSetConfig.DeltaResend*time.Second,
-		ocrSetConfig.DeltaRound*time.Second,
-		ocrSetConfig.DeltaGrace*time.Second,
-		ocrSetConfig.DeltaStage*time.Second,
-		ocrSetConfig.RMax,
-		s,
-		ids,
-		median.OffchainConfig{
-			AlphaAcceptInfinite: m.OCR2.OCR2MedianOffchainConfig.AlphaAcceptInfinite,
-			AlphaReportInfinite: m.OCR2.OCR2MedianOffchainConfig.AlphaReportInfinite,
-			AlphaReportPPB:      m.OCR2.OCR2MedianOffchainConfig.AlphaReportPPB,
-			AlphaAcceptPPB:      m.OCR2.OCR2MedianOffchainConfig.AlphaAcceptPPB,
-			DeltaC:              time.Duration(m.OCR2.OCR2MedianOffchainConfig.DeltaCSec) * time.Second,
-		}.Encode(),
-		nil,
-		ocrSetConfig.MaxDurationQuery*time.Second,
-		ocrSetConfig.MaxDurationObservation*time.Second,
-		ocrSetConfig.MaxDurationReport*time.Second,
-		ocrSetConfig.MaxDurationShouldAcceptFinalizedReport*time.Second,
-		ocrSetConfig.MaxDurationShouldTransmitAcceptedReport*time.Second,
-		1,
-		nil, // The median reporting plugin has an empty onchain config
+	return d.Epoch, nil
+}
+
+// contractSetConfigArgs generates setConfig arguments, shared secret and encryption key are derived from seed
+// so the same inputs always produce the same offchain config, random ones are used if seed is empty
+func contractSetConfigArgs(seed string, o2 *OCRv2SetConfigOptions, s []int, ids []confighelper.OracleIdentityExtra, reportingPluginConfig []byte) ([]types.OnchainPublicKey, []types.Account, uint8, uint64, []byte, error) {
+	var (
+		signerKeys            []types.OnchainPublicKey
+		transmitterAccounts   []types.Account
+		f                     uint8
+		offchainConfigVersion uint64
+		offchainConfig        []byte
+		err                   error
 	)
+	if seed == "" {
+		signerKeys, transmitterAccounts, f, _, offchainConfigVersion, offchainConfig, err = confighelper.ContractSetConfigArgsForTests(
+			o2.DeltaProgress,
+			o2.DeltaResend,
+			o2.DeltaRound,
+			o2.DeltaGrace,
+			o2.DeltaStage,
+			o2.RMax,
+			s,
+			ids,
+			reportingPluginConfig,
+			nil,
+			o2.MaxDurationQuery,
+			o2.MaxDurationObservation,
+			o2.MaxDurationReport,
+			o2.MaxDurationShouldAcceptFinalizedReport,
+			o2.MaxDurationShouldTransmitAcceptedReport,
+			FaultyOracles,
+			nil, // The median reporting plugin has an empty onchain config
+		)
+	} else {
+		var sharedSecret [16]byte
+		ss := sha256.Sum256([]byte("shared-secret:" + seed))
+		copy(sharedSecret[:], ss[:])
+		signerKeys, transmitterAccounts, f, _, offchainConfigVersion, offchainConfig, err = confighelper.ContractSetConfigArgsDeterministic(
+			sha256.Sum256([]byte("ephemeral-key:"+seed)),
+			sharedSecret,
+			o2.DeltaProgress,
+			o2.DeltaResend,
+			o2.DeltaRound,
+			o2.DeltaGrace,
+			o2.DeltaStage,
+			o2.RMax,
+			s,
+			ids,
+			reportingPluginConfig,
+			nil,
+			o2.MaxDurationQuery,
+			o2.MaxDurationObservation,
+			o2.MaxDurationReport,
+			o2.MaxDurationShouldAcceptFinalizedReport,
+			o2.MaxDurationShouldTransmitAcceptedReport,
+			FaultyOracles,
+			nil, // The median reporting plugin has an empty onchain config
+		)
+	}
 	if err != nil {
-		return nil, "", fmt.Errorf("could not set config: %w", err)
+		return nil, nil, 0, 0, nil, fmt.Errorf("could not generate set config args: %w", err)
+	}
+	return signerKeys, transmitterAccounts, f, offchainConfigVersion, offchainConfig, nil
+}
+
+// setConfigRequestHash hashes everything that defines a config except the random shared secret,
+// config_seed is included because secrets are derived from it, so changing it applies the config again.
+// On-chain digest can't be compared directly because it includes config count and encrypted secrets
+func setConfigRequestHash(seed string, o2 *OCRv2SetConfigOptions, ids []confighelper.OracleIdentityExtra, reportingPluginConfig, onchainConfig []byte) (string, error) {
+	d, err := json.Marshal(struct {
+		ConfigSeed            string
+		Options               *OCRv2SetConfigOptions
+		Oracles               []confighelper.OracleIdentityExtra
+		ReportingPluginConfig []byte
+		OnchainConfig         []byte
+		F                     int
+	}{seed, o2, ids, reportingPluginConfig, onchainConfig, FaultyOracles})
+	if err != nil {
+		return "", fmt.Errorf("could not hash set config request: %w", err)
+	}
+	h := sha256.Sum256(d)
+	return hex.EncodeToString(h[:]), nil
+}
+
+func (m *Configurator) configureContracts(ctx context.Context, c *ethclient.Client, auth *bind.TransactOpts, cl []*clclient.ChainlinkClient, infos []NodeInfo, rootAddr string, transmitters []common.Address, linkFunding []float64, w WaitConfig) (*OCRv2Config, *DeployedContracts, error) {
+	// a median config that never or always reports is caught before any contract is deployed
+	if m.OCR2.pluginType() == PluginTypeMedian {
+		if err := m.OCR2.OCR2MedianOffchainConfig.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	// every transaction is bounded by w, the whole deployment by the bring-up deadline of ctx
+	deployAuth, err := m.OCR2.GasSettings.transactOpts(ctx, c, auth, GasOpDeploy)
+	if err != nil {
+		return nil, nil, err
+	}
+	setConfigAuth, err := m.OCR2.GasSettings.transactOpts(ctx, c, auth, GasOpSetConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	fundAuth, err := m.OCR2.GasSettings.transactOpts(ctx, c, auth, GasOpFund)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctxLogger(ctx).Info().Msg("Deploying LINK token contract")
+	lt, err := deployLinkAndMint(ctx, c, deployAuth, fundAuth, rootAddr, transmitters, linkFunding, m.OCR2.FeeToken, w)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create link token contract and mint: %w", err)
+	}
+	deployed := &DeployedContracts{}
+	if m.OCR2.ForwardingAllowed {
+		ctxLogger(ctx).Info().Msg("Deploying authorized forwarders")
+		deployed.Forwarders, err = deployForwarders(ctx, c, deployAuth, lt.Address(), common.HexToAddress(rootAddr), transmitters, w)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	// aggregator sees forwarders as transmitters, so payees are set for them
+	onchainTransmitters := make([]common.Address, 0, len(transmitters))
+	for _, transmitter := range transmitters {
+		onchainTransmitters = append(onchainTransmitters, deployed.onchainTransmitter(transmitter))
+	}
+	specs, err := m.OCR2.FeedSpecs()
+	if err != nil {
+		return nil, nil, err
+	}
+	// generating oracle identities, all feeds are served by the same oracles with the same config
+	s, ids := oracleIdentities(infos)
+	codec, err := NewPluginConfigCodec(m.OCR2)
+	if err != nil {
+		return nil, nil, err
+	}
+	reportingPluginConfig, err := codec.OffchainConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not encode reporting plugin config: %w", err)
+	}
+	ocrSetConfig := m.OCR2.OCR2SetConfig.withSecondUnits()
+	signerKeys, transmitterAccounts, f, offchainConfigVersion, offchainConfig, err := contractSetConfigArgs(m.OCR2.ConfigSeed, ocrSetConfig, s, ids, reportingPluginConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not set config: %w", err)
 	}
 	signerAddresses := make([]common.Address, 0)
 	for _, signer := range signerKeys {
@@ -463,187 +1126,270 @@ func (m *Configurator) configureContracts(ctx context.Context, c *ethclient.Clie
 	}
 	transmitterAddresses := make([]common.Address, 0)
 	for _, account := range transmitterAccounts {
-		transmitterAddresses = append(transmitterAddresses, common.HexToAddress(string(account)))
+		transmitterAddresses = append(transmitterAddresses, deployed.onchainTransmitter(common.HexToAddress(string(account))))
 	}
-	onChainConfig, err := median.StandardOnchainConfigCodec{}.Encode(context.Background(), median.OnchainConfig{Min: m.OCR2.OCR2.MinimumAnswer, Max: m.OCR2.OCR2.MaximumAnswer})
+	onChainConfig, err := codec.OnchainConfig(ctx)
 	if err != nil {
-		return nil, "", fmt.Errorf("could not encode onchain config: %w", err)
+		return nil, nil, fmt.Errorf("could not encode onchain config: %w", err)
 	}
-	tx, err = ocr2i.SetConfig(auth, signerAddresses, transmitterAddresses, f, onChainConfig, offchainConfigVersion, offchainConfig)
+	requestHash, err := setConfigRequestHash(m.OCR2.ConfigSeed, ocrSetConfig, ids, reportingPluginConfig, onChainConfig)
 	if err != nil {
-		return nil, "", fmt.Errorf("could not set OCRv2 config: %w", err)
+		return nil, nil, err
+	}
+	var out *OCRv2Config
+	for i, spec := range specs {
+		ctxLogger(ctx).Info().Str("Feed", spec.Name).Msg("Deploying OCRv2 aggregator contract")
+		aggParams, err := NewAggregatorDeployParams(spec.Options, lt.Address())
+		if err != nil {
+			return nil, nil, fmt.Errorf("feed %s: %w", spec.Name, err)
+		}
+		ocr2addr, ocr2i, err := deployAggregator(ctx, c, deployAuth, aggParams, w)
+		if err != nil {
+			return nil, nil, fmt.Errorf("feed %s: %w", spec.Name, err)
+		}
+		ctxLogger(ctx).Info().Str("Feed", spec.Name).Str("Address", ocr2addr.String()).Uint8("Decimals", aggParams.Decimals).Msg("Deployed OCRv2 Aggregator contract")
+		if m.OCR2.SkipSetPayees {
+			ctxLogger(ctx).Warn().Msg("skip_set_payees is set, transmitters have no payees, payment-gated transmissions may not work")
+		} else if err := setPayees(ctx, c, setConfigAuth, ocr2i, onchainTransmitters, common.HexToAddress(rootAddr), w); err != nil {
+			return nil, nil, err
+		}
+		tx, err := ocr2i.SetConfig(setConfigAuth, signerAddresses, transmitterAddresses, f, onChainConfig, offchainConfigVersion, offchainConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not set OCRv2 config of feed %s: %w", spec.Name, err)
+		}
+		ev, err := waitConfigSet(ctx, c, ocr2i, ocr2addr, tx, w)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := verifySigners(ctx, ev.Signers, keyReaders(cl), bundleIDs(infos)); err != nil {
+			return nil, nil, fmt.Errorf("feed %s: %w", spec.Name, err)
+		}
+		digest := types.ConfigDigest(ev.ConfigDigest)
+		deployed.Feeds = append(deployed.Feeds, &DeployedFeed{
+			Name:         spec.Name,
+			Address:      ocr2addr.String(),
+			Decimals:     aggParams.Decimals,
+			ConfigDigest: digest.Hex(),
+		})
+		if i > 0 {
+			continue
+		}
+		// the first feed is the primary aggregator, config updates and tests apply to it
+		deployed.OCRv2AggregatorAddr = ocr2addr.String()
+		out = &OCRv2Config{
+			F:                     f,
+			Signers:               signerAddresses,
+			Transmitters:          transmitterAddresses,
+			OnchainConfig:         onChainConfig,
+			OffchainConfigVersion: offchainConfigVersion,
+			OffchainConfig:        offchainConfig,
+			ConfigDigest:          digest.Hex(),
+			RequestHash:           requestHash,
+			ConfigSetTxHash:       ev.Raw.TxHash.Hex(),
+			ConfigSetBlock:        ev.Raw.BlockNumber,
+			ConfigCount:           ev.ConfigCount,
+			ConfigSet:             ev,
+		}
 	}
-	_, err = bind.WaitMined(ctx, c, tx)
+	return out, deployed, nil
+}
+
+// setPayees sets payee of every transmitter and verifies payees are set
+func setPayees(ctx context.Context, c *ethclient.Client, auth *bind.TransactOpts, ocr2i *ocr2aggregator.OCR2Aggregator, transmitters []common.Address, payee common.Address, w WaitConfig) error {
+	payees := make([]common.Address, 0, len(transmitters))
+	for range transmitters {
+		payees = append(payees, payee)
+	}
+	tx, err := ocr2i.SetPayees(auth, transmitters, payees)
 	if err != nil {
-		return nil, "", err
+		return fmt.Errorf("failed to set payees: %w", err)
 	}
-	return &OCRv2Config{
-		F:                     f,
-		Signers:               signerAddresses,
-		Transmitters:          transmitterAddresses,
-		OnchainConfig:         onChainConfig,
-		OffchainConfigVersion: offchainConfigVersion,
-		OffchainConfig:        offchainConfig,
-	}, ocr2addr.String(), err
+	_, err = WaitMined(ctx, c, tx, w)
+	if err != nil {
+		return err
+	}
+	return verifyPayees(ctx, ocr2i, transmitters, payees)
 }
 
-func getOracleIdentities(clClients []*clclient.ChainlinkClient) ([]int, []confighelper.OracleIdentityExtra, error) {
-	s := make([]int, len(clClients))
-	oracleIdentities := make([]confighelper.OracleIdentityExtra, len(clClients))
-	sharedSecretEncryptionPublicKeys := make([]types.ConfigEncryptionPublicKey, len(clClients))
-	eg := &errgroup.Group{}
-	for i, cl := range clClients {
-		eg.Go(func() error {
-			addresses, err := cl.EthAddresses()
-			if err != nil {
-				return err
-			}
-			ocr2Keys, err := cl.MustReadOCR2Keys()
-			if err != nil {
-				return err
-			}
-			var ocr2Config clclient.OCR2KeyAttributes
-			for _, key := range ocr2Keys.Data {
-				if key.Attributes.ChainType == "evm" {
-					ocr2Config = key.Attributes
-					break
-				}
-			}
+// verifyPayees reads payees back from the aggregator and checks they match what was set.
+// Aggregator has no payee getter, so the mapping is restored from PayeeshipTransferred events.
+func verifyPayees(ctx context.Context, ocr2i *ocr2aggregator.OCR2Aggregator, transmitters, payees []common.Address) error {
+	it, err := ocr2i.FilterPayeeshipTransferred(&bind.FilterOpts{Context: ctx}, transmitters, nil, nil)
+	if err != nil {
+		return fmt.Errorf("could not filter PayeeshipTransferred events: %w", err)
+	}
+	defer it.Close()
+	onchainPayees := make(map[common.Address]common.Address)
+	for it.Next() {
+		onchainPayees[it.Event.Transmitter] = it.Event.Current
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("could not read PayeeshipTransferred events: %w", err)
+	}
+	mismatches := make([]string, 0)
+	for i, transmitter := range transmitters {
+		payee, ok := onchainPayees[transmitter]
+		if !ok || payee != payees[i] {
+			mismatches = append(mismatches, fmt.Sprintf("transmitter %s: expected payee %s, got %s", transmitter.Hex(), payees[i].Hex(), payee.Hex()))
+			continue
+		}
+		ctxLogger(ctx).Info().
+			Str("Transmitter", transmitter.Hex()).
+			Str("Payee", payee.Hex()).
+			Msg("Verified payee")
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("on-chain payees do not match: %s", strings.Join(mismatches, "; "))
+	}
+	return nil
+}
 
-			keys, err := cl.MustReadP2PKeys()
-			if err != nil {
-				return err
-			}
-			p2pKeyID := keys.Data[0].Attributes.PeerID
+// configureJobs creates bootstrap jobs on topology bootstrap nodes and OCR2 jobs on worker nodes,
+// clNodes and infos are nodes of the worker node set and their keys
+func (m *Configurator) configureJobs(ctx context.Context, create jobCreator, fake *fake.Input, bc *blockchain.Input, topo *jobTopology, clNodes []*clclient.ChainlinkClient, infos []NodeInfo, ocr2Addr string) error {
+	bootstrapNodes, err := clclient.New(topo.bootstrap)
+	if err != nil {
+		return fmt.Errorf("could not connect to bootstrap nodes: %w", err)
+	}
+	p2p, err := m.OCR2.p2pSettings()
+	if err != nil {
+		return err
+	}
+	bootstrapInfos, err := CollectNodeInfo(ctx, bootstrapNodes, bc.Out.ChainID, nil)
+	if err != nil {
+		return fmt.Errorf("reading keys of bootstrap nodes have failed: %w", err)
+	}
+	p2pV2Bootstrappers := make([]string, 0, len(bootstrapNodes))
+	for i, bootstrapNode := range bootstrapNodes {
+		p2pV2Bootstrappers = append(p2pV2Bootstrappers, fmt.Sprintf("%s@%s:%d", bootstrapInfos[i].P2PPeerID, topo.bootstrap[i].Node.ContainerName, p2p.AdvertisedPort))
+		bootstrapSpec, err := m.bootstrapJobSpec("ocr2_bootstrap-"+uuid.NewString(), bc.ChainID, ocr2Addr)
+		if err != nil {
+			return err
+		}
+		err = create(ctx, bootstrapNode, bootstrapSpec)
+		if err != nil {
+			return fmt.Errorf("creating bootstrap job have failed: %w", err)
+		}
+	}
 
-			offchainPkBytes, err := hex.DecodeString(strings.TrimPrefix(ocr2Config.OffChainPublicKey, "ocr2off_evm_"))
-			if err != nil {
-				return err
-			}
-			offchainPkBytesFixed := [ed25519.PublicKeySize]byte{}
-			n := copy(offchainPkBytesFixed[:], offchainPkBytes)
-			if n != ed25519.PublicKeySize {
-				return errors.New("wrong number of elements copied")
-			}
-			configPkBytes, err := hex.DecodeString(strings.TrimPrefix(ocr2Config.ConfigPublicKey, "ocr2cfg_evm_"))
-			if err != nil {
-				return err
-			}
-			configPkBytesFixed := [ed25519.PublicKeySize]byte{}
-			n = copy(configPkBytesFixed[:], configPkBytes)
-			if n != ed25519.PublicKeySize {
-				return errors.New("wrong number of elements copied")
-			}
-			onchainPkBytes, err := hex.DecodeString(strings.TrimPrefix(ocr2Config.OnChainPublicKey, "ocr2on_evm_"))
-			if err != nil {
-				return err
-			}
-			sharedSecretEncryptionPublicKeys[i] = configPkBytesFixed
-			oracleIdentities[i] = confighelper.OracleIdentityExtra{
-				OracleIdentity: confighelper.OracleIdentity{
-					OnchainPublicKey:  onchainPkBytes,
-					OffchainPublicKey: offchainPkBytesFixed,
-					PeerID:            p2pKeyID,
-					TransmitAccount:   types.Account(addresses[0]),
-				},
-				ConfigEncryptionPublicKey: configPkBytesFixed,
+	// worker jobs only reference bootstrap nodes, so they can be created concurrently
+	errs := make([]error, len(topo.workers))
+	eg := &errgroup.Group{}
+	for i, idx := range topo.workers {
+		eg.Go(func() error {
+			if err := m.configureWorkerJob(ctx, create, clNodes[idx], infos[idx], fake, bc, ocr2Addr, p2pV2Bootstrappers); err != nil {
+				errs[i] = fmt.Errorf("node %d: %w", idx, err)
 			}
-			s[i] = 1
-			L.Trace().
-				Interface("OnChainPK", onchainPkBytes).
-				Interface("OffChainPK", offchainPkBytesFixed).
-				Interface("ConfigPK", configPkBytesFixed).
-				Str("PeerID", p2pKeyID).
-				Str("Address", addresses[0]).
-				Msg("Oracle identity")
 			return nil
 		})
 	}
-	return s, oracleIdentities, eg.Wait()
+	_ = eg.Wait()
+	return errors.Join(errs...)
 }
 
-func (m *Configurator) configureJobs(ctx context.Context, fake *fake.Input, bc *blockchain.Input, ns *nodeset.Input, clNodes []*clclient.ChainlinkClient, ocr2Addr string) error {
-	bootstrapNode := clNodes[0]
-	workerNodes := clNodes[1:]
-	bootstrapP2PIds, err := bootstrapNode.MustReadP2PKeys()
+// bootstrapJobSpec returns bootstrap job spec for the aggregator
+func (m *Configurator) bootstrapJobSpec(name, chainID, ocr2Addr string) (*TaskJobSpec, error) {
+	maxTaskDuration, err := m.OCR2.Jobs.maxTaskDuration(JobTypeBootstrap)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	p2pV2Bootstrapper := fmt.Sprintf("%s@%s:%d", bootstrapP2PIds.Data[0].Attributes.PeerID, ns.Out.CLNodes[0].Node.ContainerName, 6690)
-	// Set the value for the jobs to report on
-	bootstrapSpec := &TaskJobSpec{
-		Name:    "ocr2_bootstrap-" + uuid.NewString(),
-		JobType: "bootstrap",
+	confirmations, err := m.OCR2.Jobs.contractConfigConfirmations()
+	if err != nil {
+		return nil, err
+	}
+	relay, relayConfig, err := m.OCR2.Jobs.relaySpec(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskJobSpec{
+		Name:            name,
+		JobType:         JobTypeBootstrap,
+		MaxTaskDuration: maxTaskDuration,
 		OCR2OracleSpec: OracleSpec{
-			ContractID: ocr2Addr,
-			Relay:      "evm",
-			RelayConfig: map[string]any{
-				"chainID": bc.ChainID,
-			},
+			ContractID:                        ocr2Addr,
+			Relay:                             relay,
+			RelayConfig:                       relayConfig,
 			ContractConfigTrackerPollInterval: *NewInterval(5 * time.Second),
+			ContractConfigConfirmations:       confirmations,
 		},
+	}, nil
+}
+
+// workerJobSpec returns OCR2 job spec of a worker node, ea and juels are bridges the pipeline and juels source call
+func (m *Configurator) workerJobSpec(name, chainID, ocr2Addr string, p2pV2Bootstrappers []string, ocrKeyID, transmitter string, ea, juels *clclient.BridgeTypeAttributes) (*TaskJobSpec, error) {
+	maxTaskDuration, err := m.OCR2.Jobs.maxTaskDuration(JobTypeOCR2)
+	if err != nil {
+		return nil, err
+	}
+	confirmations, err := m.OCR2.Jobs.contractConfigConfirmations()
+	if err != nil {
+		return nil, err
+	}
+	observationSource, err := observationSourceSpec(ea, m.OCR2.Jobs.observationSource(), m.OCR2.Jobs.bridgeHeaders())
+	if err != nil {
+		return nil, err
+	}
+	juelsSource, err := observationSourceSpec(juels, nil, m.OCR2.Jobs.bridgeHeaders())
+	if err != nil {
+		return nil, err
 	}
-	_, err = bootstrapNode.MustCreateJob(bootstrapSpec)
+	relay, relayConfig, err := m.OCR2.Jobs.relaySpec(chainID)
 	if err != nil {
-		return fmt.Errorf("creating bootstrap job have failed: %w", err)
+		return nil, err
 	}
+	return &TaskJobSpec{
+		Name:              name,
+		JobType:           JobTypeOCR2,
+		MaxTaskDuration:   maxTaskDuration,
+		ObservationSource: observationSource,
+		ForwardingAllowed: m.OCR2.ForwardingAllowed,
+		OCR2OracleSpec: OracleSpec{
+			PluginType:  m.OCR2.pluginType(),
+			Relay:       relay,
+			RelayConfig: relayConfig,
+			PluginConfig: map[string]any{
+				"juelsPerFeeCoinSource": fmt.Sprintf("\"\"\"%s\"\"\"", juelsSource),
+			},
+			ContractConfigTrackerPollInterval: *NewInterval(5 * time.Second),
+			ContractConfigConfirmations:       confirmations,
+			MonitoringEndpoint:                m.OCR2.Telemetry.monitoringEndpoint(),
+			CaptureEATelemetry:                m.OCR2.Telemetry.captureEATelemetry(),
+			ContractID:                        ocr2Addr,                     // registryAddr
+			OCRKeyBundleID:                    null.StringFrom(ocrKeyID),    // get node ocr2config.ID
+			TransmitterID:                     null.StringFrom(transmitter), // node addr
+			P2PV2Bootstrappers:                p2pV2Bootstrappers,           // bootstrap node keys and addresses <p2p-key>@bootstrap:<advertised_port>
+		},
+	}, nil
+}
 
-	for _, chainlinkNode := range workerNodes {
-		nodeTransmitterAddress, err := chainlinkNode.PrimaryEthAddress()
-		if err != nil {
-			return fmt.Errorf("getting primary ETH address from OCR node have failed: %w", err)
-		}
-		nodeOCRKeys, err := chainlinkNode.MustReadOCR2Keys()
-		if err != nil {
-			return fmt.Errorf("getting OCR keys from OCR node have failed: %w", err)
-		}
-		nodeOCRKeyID := nodeOCRKeys.Data[0].ID
+// configureWorkerJob creates EA bridges and OCR2 job on a worker node
+func (m *Configurator) configureWorkerJob(ctx context.Context, create jobCreator, chainlinkNode *clclient.ChainlinkClient, info NodeInfo, fake *fake.Input, bc *blockchain.Input, ocr2Addr string, p2pV2Bootstrappers []string) error {
+	fakeServerURL := fake.Out.BaseURLDocker
 
-		fakeServerURL := fake.Out.BaseURLDocker
+	ea := &clclient.BridgeTypeAttributes{
+		Name: "ea-" + uuid.NewString(),
+		URL:  fmt.Sprintf("%s/%s", fakeServerURL, "ea"),
+	}
+	juelsBridge := &clclient.BridgeTypeAttributes{
+		Name: "juels-" + uuid.NewString(),
+		URL:  fmt.Sprintf("%s/%s", fakeServerURL, "juelsPerFeeCoinSource"),
+	}
+	err := chainlinkNode.MustCreateBridge(ea)
+	if err != nil {
+		return fmt.Errorf("creating bridge to %s on CL node failed: %w", ea.URL, err)
+	}
+	err = chainlinkNode.MustCreateBridge(juelsBridge)
+	if err != nil {
+		return fmt.Errorf("creating bridge to %s on CL node failed: %w", juelsBridge.URL, err)
+	}
 
-		ea := &clclient.BridgeTypeAttributes{
-			Name: "ea-" + uuid.NewString(),
-			URL:  fmt.Sprintf("%s/%s", fakeServerURL, "ea"),
-		}
-		juelsBridge := &clclient.BridgeTypeAttributes{
-			Name: "juels-" + uuid.NewString(),
-			URL:  fmt.Sprintf("%s/%s", fakeServerURL, "juelsPerFeeCoinSource"),
-		}
-		err = chainlinkNode.MustCreateBridge(ea)
-		if err != nil {
-			return fmt.Errorf("creating bridge to %s on CL node failed: %w", ea.URL, err)
-		}
-		err = chainlinkNode.MustCreateBridge(juelsBridge)
-		if err != nil {
-			return fmt.Errorf("creating bridge to %s CL node failed: %w", juelsBridge.URL, err)
-		}
-
-		ocrSpec := &TaskJobSpec{
-			Name:              "ocr2-" + uuid.NewString(),
-			JobType:           "offchainreporting2",
-			MaxTaskDuration:   (time.Duration(m.OCR2.Jobs.MaxTaskDurationSec) * time.Second).String(),
-			ObservationSource: clclient.ObservationSourceSpecBridge(ea),
-			ForwardingAllowed: false,
-			OCR2OracleSpec: OracleSpec{
-				PluginType: "median",
-				Relay:      "evm",
-				RelayConfig: map[string]any{
-					"chainID": bc.ChainID,
-				},
-				PluginConfig: map[string]any{
-					"juelsPerFeeCoinSource": fmt.Sprintf("\"\"\"%s\"\"\"", clclient.ObservationSourceSpecBridge(juelsBridge)),
-				},
-				ContractConfigTrackerPollInterval: *NewInterval(5 * time.Second),
-				ContractID:                        ocr2Addr,                                // registryAddr
-				OCRKeyBundleID:                    null.StringFrom(nodeOCRKeyID),           // get node ocr2config.ID
-				TransmitterID:                     null.StringFrom(nodeTransmitterAddress), // node addr
-				P2PV2Bootstrappers:                pq.StringArray{p2pV2Bootstrapper},       // bootstrap node key and address <p2p-key>@bootstrap:6690
-			},
-		}
-		_, err = chainlinkNode.MustCreateJob(ocrSpec)
-		if err != nil {
-			return fmt.Errorf("creating OCR task job on OCR node have failed: %w", err)
-		}
+	ocrSpec, err := m.workerJobSpec("ocr2-"+uuid.NewString(), bc.ChainID, ocr2Addr, p2pV2Bootstrappers, info.OCR2BundleID, info.ETHAddress.Hex(), ea, juelsBridge)
+	if err != nil {
+		return err
+	}
+	err = create(ctx, chainlinkNode, ocrSpec)
+	if err != nil {
+		return fmt.Errorf("creating OCR task job on OCR node have failed: %w", err)
 	}
 	return nil
 }
@@ -0,0 +1,266 @@
+package ocr2
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/fake"
+	"github.com/smartcontractkit/libocr/offchainreporting2/confighelper"
+	"github.com/smartcontractkit/libocr/offchainreporting2/types"
+
+	"github.com/smartcontractkit/chainlink/devenv/products"
+)
+
+// testOracleIdentities returns fixed oracle identities, so only config generation can make outputs differ
+func testOracleIdentities(n int) ([]int, []confighelper.OracleIdentityExtra) {
+	s := make([]int, n)
+	ids := make([]confighelper.OracleIdentityExtra, n)
+	for i := range n {
+		s[i] = 1
+		onchain := sha256.Sum256([]byte(fmt.Sprintf("onchain-%d", i)))
+		ids[i] = confighelper.OracleIdentityExtra{
+			OracleIdentity: confighelper.OracleIdentity{
+				OnchainPublicKey:  onchain[:20],
+				OffchainPublicKey: sha256.Sum256([]byte(fmt.Sprintf("offchain-%d", i))),
+				PeerID:            fmt.Sprintf("12D3KooWTestPeer%d", i),
+				TransmitAccount:   types.Account(fmt.Sprintf("0x%040d", i+1)),
+			},
+			ConfigEncryptionPublicKey: sha256.Sum256([]byte(fmt.Sprintf("config-%d", i))),
+		}
+	}
+	return s, ids
+}
+
+func TestContractSetConfigArgsSeeded(t *testing.T) {
+	o2 := (&OCRv2SetConfigOptions{
+		RMax:                                    3,
+		DeltaProgress:                           20,
+		DeltaResend:                             20,
+		DeltaRound:                              5,
+		DeltaGrace:                              1,
+		DeltaStage:                              15,
+		MaxDurationQuery:                        1,
+		MaxDurationObservation:                  1,
+		MaxDurationReport:                       1,
+		MaxDurationShouldAcceptFinalizedReport:  1,
+		MaxDurationShouldTransmitAcceptedReport: 1,
+	}).withSecondUnits()
+	s, ids := testOracleIdentities(MinOracles())
+	pluginConfig := []byte("plugin")
+
+	_, _, _, _, first, err := contractSetConfigArgs("ci", o2, s, ids, pluginConfig)
+	require.NoError(t, err)
+	_, _, _, _, second, err := contractSetConfigArgs("ci", o2, s, ids, pluginConfig)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	_, _, _, _, other, err := contractSetConfigArgs("other", o2, s, ids, pluginConfig)
+	require.NoError(t, err)
+	require.NotEqual(t, first, other)
+
+	_, _, _, _, random, err := contractSetConfigArgs("", o2, s, ids, pluginConfig)
+	require.NoError(t, err)
+	require.NotEqual(t, first, random)
+}
+
+func TestSetConfigRequestHashSeed(t *testing.T) {
+	o2 := (&OCRv2SetConfigOptions{RMax: 3, DeltaProgress: 20}).withSecondUnits()
+	_, ids := testOracleIdentities(MinOracles())
+
+	first, err := setConfigRequestHash("ci", o2, ids, []byte("plugin"), nil)
+	require.NoError(t, err)
+	same, err := setConfigRequestHash("ci", o2, ids, []byte("plugin"), nil)
+	require.NoError(t, err)
+	require.Equal(t, first, same)
+
+	other, err := setConfigRequestHash("other", o2, ids, []byte("plugin"), nil)
+	require.NoError(t, err)
+	require.NotEqual(t, first, other, "changing config_seed must apply the config again")
+}
+
+// roundReader returns rounds in order, the last one is repeated
+type roundReader struct {
+	rounds []RoundData
+	err    error
+	reads  int
+}
+
+func (r *roundReader) LatestRoundData(_ *bind.CallOpts) (RoundData, error) {
+	if r.err != nil {
+		return RoundData{}, r.err
+	}
+	rd := r.rounds[min(r.reads, len(r.rounds)-1)]
+	r.reads++
+	return rd, nil
+}
+
+func TestWaitForFirstRound(t *testing.T) {
+	w := WaitConfig{PollIntervalMs: 1, TimeoutSec: 1}
+	initial := RoundData{RoundId: big.NewInt(0), Answer: big.NewInt(0)}
+	first := RoundData{RoundId: big.NewInt(1), Answer: big.NewInt(200)}
+
+	t.Run("returns first reported round", func(t *testing.T) {
+		r := &roundReader{rounds: []RoundData{initial, initial, first}}
+		rd, err := WaitForFirstRound(context.Background(), r, w)
+		require.NoError(t, err)
+		require.Equal(t, first, rd)
+		require.Equal(t, 3, r.reads)
+	})
+	t.Run("gives up if no round is reported", func(t *testing.T) {
+		_, err := WaitForFirstRound(context.Background(), &roundReader{rounds: []RoundData{initial}}, WaitConfig{PollIntervalMs: 1, MaxAttempts: 3})
+		require.ErrorIs(t, err, ErrWaitExhausted)
+		require.ErrorContains(t, err, "no OCR2 round reported")
+	})
+	t.Run("times out with the last read error", func(t *testing.T) {
+		readErr := errors.New("execution reverted")
+		_, err := WaitForFirstRound(context.Background(), &roundReader{err: readErr}, WaitConfig{PollIntervalMs: 1, MaxAttempts: 3})
+		require.ErrorIs(t, err, readErr)
+	})
+}
+
+func TestWaitForRounds(t *testing.T) {
+	round := func(id int64) RoundData { return RoundData{RoundId: big.NewInt(id), Answer: big.NewInt(200)} }
+
+	t.Run("returns once enough rounds are reported", func(t *testing.T) {
+		r := &roundReader{rounds: []RoundData{round(0), round(1), round(3)}}
+		rd, err := WaitForRounds(context.Background(), r, 3, WaitConfig{PollIntervalMs: 1, TimeoutSec: 1})
+		require.NoError(t, err)
+		require.Equal(t, round(3), rd)
+		require.Equal(t, 3, r.reads)
+	})
+	t.Run("names rounds seen if it falls short", func(t *testing.T) {
+		r := &roundReader{rounds: []RoundData{round(1), round(2)}}
+		_, err := WaitForRounds(context.Background(), r, 5, WaitConfig{PollIntervalMs: 1, MaxAttempts: 3})
+		require.ErrorIs(t, err, ErrWaitExhausted)
+		require.ErrorContains(t, err, "only 2 of 5 required OCR2 rounds reported")
+	})
+	t.Run("no rounds read", func(t *testing.T) {
+		_, err := WaitForRounds(context.Background(), &roundReader{err: errors.New("execution reverted")}, 2, WaitConfig{PollIntervalMs: 1, MaxAttempts: 2})
+		require.ErrorContains(t, err, "only 0 of 2 required OCR2 rounds reported")
+	})
+}
+
+func TestValidateRounds(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		rounds  int
+		deltaC  int64
+		timeout int64
+		err     string
+	}{
+		{name: "first round only", rounds: 1, deltaC: 0, timeout: 10},
+		{name: "heartbeats fit into timeout", rounds: 5, deltaC: 30, timeout: 400},
+		{name: "no timeout", rounds: 5, deltaC: 1800},
+		{name: "no heartbeat", rounds: 2, deltaC: 0, timeout: 400, err: "median delta_sec is 0"},
+		{name: "heartbeats exceed timeout", rounds: 2, deltaC: 1800, timeout: 400, err: "require_rounds 2 takes at least 30m0s with median delta_sec 1800, but verification times out after 6m40s"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Configurator{OCR2: &OCR2{
+				VerificationTimeoutSec:   tc.timeout,
+				OCR2MedianOffchainConfig: &MedianOffchainConfig{DeltaCSec: tc.deltaC},
+			}}
+			err := m.ValidateRounds(tc.rounds)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestGasSettingsMultipliers(t *testing.T) {
+	g := &GasSettings{
+		FeeCapMultiplier: 2,
+		TipCapMultiplier: 3,
+		Deploy:           &GasMultipliers{FeeCapMultiplier: 10, TipCapMultiplier: 20},
+		SetConfig:        &GasMultipliers{FeeCapMultiplier: 5},
+	}
+	tests := []struct {
+		op      GasOperation
+		feeCap  int64
+		tipCap  int64
+		comment string
+	}{
+		{op: GasOpDeploy, feeCap: 10, tipCap: 20, comment: "full override"},
+		{op: GasOpSetConfig, feeCap: 5, tipCap: 3, comment: "unset tip cap falls back to default"},
+		{op: GasOpFund, feeCap: 2, tipCap: 3, comment: "funding falls back to defaults, so it's not stuck during fee spikes"},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.op), func(t *testing.T) {
+			feeCap, tipCap := g.Multipliers(tc.op)
+			require.Equal(t, tc.feeCap, feeCap, tc.comment)
+			require.Equal(t, tc.tipCap, tipCap, tc.comment)
+		})
+	}
+	g.Fund = &GasMultipliers{TipCapMultiplier: 4}
+	feeCap, tipCap := g.Multipliers(GasOpFund)
+	require.Equal(t, int64(2), feeCap, "unset fund fee cap falls back to default")
+	require.Equal(t, int64(4), tipCap)
+}
+
+func TestJobsSelectFake(t *testing.T) {
+	fakes := []*fake.Input{{Port: 9111}, {Port: 9112}}
+	f, err := (*Jobs)(nil).SelectFake(fakes)
+	require.NoError(t, err)
+	require.Same(t, fakes[0], f)
+	f, err = (&Jobs{FakeServer: 1}).SelectFake(fakes)
+	require.NoError(t, err)
+	require.Same(t, fakes[1], f)
+	_, err = (&Jobs{FakeServer: 2}).SelectFake(fakes)
+	require.ErrorContains(t, err, "jobs fake_server index 2 is out of range, there are 2 fake servers")
+	_, err = (&Jobs{FakeServer: -1}).SelectFake(fakes)
+	require.ErrorContains(t, err, "index -1 is out of range")
+}
+
+func TestAnswerBoundsTOML(t *testing.T) {
+	huge, ok := new(big.Int).SetString("100000000000000000000000000000", 10)
+	require.True(t, ok)
+	tests := []struct {
+		name    string
+		min     string
+		max     string
+		wantMin *big.Int
+		wantMax *big.Int
+		wantErr string
+	}{
+		{name: "integers", min: "1", max: "50000000000000000", wantMin: big.NewInt(1), wantMax: big.NewInt(5e16)},
+		{name: "beyond int64", min: "-100000000000000000000000000000", max: "100000000000000000000000000000", wantMin: new(big.Int).Neg(huge), wantMax: huge},
+		{name: "decimal strings", min: `"-1"`, max: `"100000000000000000000000000000"`, wantMin: big.NewInt(-1), wantMax: huge},
+		{name: "hex", min: `"-0x10"`, max: "0xffff", wantMin: big.NewInt(-16), wantMax: big.NewInt(0xffff)},
+		{name: "exponent is not an integer", min: "1", max: "5e16", wantErr: `cannot unmarshal "5e16" into a *big.Int`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(products.EnvVarTestConfigs, "env.toml")
+			s := products.NewMemoryStore()
+			cfg := fmt.Sprintf("[ocr2.ocr2]\nminimum_answer = %s\nmaximum_answer = %s\n\n[[ocr2.feeds]]\nname = \"ETH/USD\"\nminimum_answer = %s\nmaximum_answer = %s\n", tt.min, tt.max, tt.min, tt.max)
+			require.NoError(t, s.Write("env.toml", []byte(cfg)))
+			in, err := products.Load[Configurator](s)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 0, tt.wantMin.Cmp(in.OCR2.OCR2.MinimumAnswer), "minimum_answer %s", in.OCR2.OCR2.MinimumAnswer)
+			require.Equal(t, 0, tt.wantMax.Cmp(in.OCR2.OCR2.MaximumAnswer), "maximum_answer %s", in.OCR2.OCR2.MaximumAnswer)
+			require.Equal(t, 0, tt.wantMax.Cmp(in.OCR2.Feeds[0].MaximumAnswer))
+
+			// bounds are written as decimal strings and read back unchanged
+			require.NoError(t, products.Store(".", in, s))
+			out, err := products.LoadOutput[Configurator]("env-out.toml", s)
+			require.NoError(t, err)
+			require.Equal(t, 0, tt.wantMin.Cmp(out.OCR2.OCR2.MinimumAnswer))
+			require.Equal(t, 0, tt.wantMax.Cmp(out.OCR2.OCR2.MaximumAnswer))
+			require.Equal(t, 0, tt.wantMin.Cmp(out.OCR2.Feeds[0].MinimumAnswer))
+		})
+	}
+}
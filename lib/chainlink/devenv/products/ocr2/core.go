@@ -107,6 +107,7 @@ func (o *TaskJobSpec) String() (string, error) {
 		TrackerPollInterval      time.Duration
 		ContractConfirmations    uint16
 		ForwardingAllowed        bool
+		CaptureEATelemetry       bool
 	}{
 		Name:                  o.Name,
 		JobType:               o.JobType,
@@ -126,51 +127,63 @@ func (o *TaskJobSpec) String() (string, error) {
 		ContractConfirmations: o.OCR2OracleSpec.ContractConfigConfirmations,
 		TrackerPollInterval:   o.OCR2OracleSpec.ContractConfigTrackerPollInterval.Duration(),
 		ObservationSource:     o.ObservationSource,
+		CaptureEATelemetry:    o.OCR2OracleSpec.CaptureEATelemetry,
 	}
-	ocr2TemplateString := `
-type                                   = "{{ .JobType }}"
+	// every optional line trims the preceding newline, so specs without optional fields, ex.: bootstrap, have no blank lines
+	ocr2TemplateString := `type                                   = "{{ .JobType }}"
 name                                   = "{{.Name}}"
 forwardingAllowed                      = {{.ForwardingAllowed}}
 {{- if .MaxTaskDuration}}
-maxTaskDuration                        = "{{ .MaxTaskDuration }}" {{end}}
+maxTaskDuration                        = "{{ .MaxTaskDuration }}"
+{{- end}}
 {{- if .PluginType}}
-pluginType                             = "{{ .PluginType }}" {{end}}
+pluginType                             = "{{ .PluginType }}"
+{{- end}}
 relay                                  = "{{.Relay}}"
 schemaVersion                          = 1
 contractID                             = "{{.ContractID}}"
 {{- if .FeedID}}
 feedID                                 = "{{.FeedID}}"
-{{end}}
-{{- if eq .JobType "offchainreporting2" }}
-ocrKeyBundleID                         = "{{.OCRKeyBundleID}}" {{end}}
-{{- if eq .JobType "offchainreporting2" }}
-transmitterID                          = "{{.TransmitterID}}" {{end}}
+{{- end}}
+{{- if eq .JobType "offchainreporting2"}}
+ocrKeyBundleID                         = "{{.OCRKeyBundleID}}"
+transmitterID                          = "{{.TransmitterID}}"
+{{- end}}
 {{- if .BlockchainTimeout}}
 blockchainTimeout                      = "{{.BlockchainTimeout}}"
-{{end}}
+{{- end}}
 {{- if .ContractConfirmations}}
 contractConfigConfirmations            = {{.ContractConfirmations}}
-{{end}}
+{{- end}}
 {{- if .TrackerPollInterval}}
 contractConfigTrackerPollInterval      = "{{.TrackerPollInterval}}"
-{{end}}
+{{- end}}
 {{- if .TrackerSubscribeInterval}}
 contractConfigTrackerSubscribeInterval = "{{.TrackerSubscribeInterval}}"
-{{end}}
+{{- end}}
 {{- if .P2PV2Bootstrappers}}
-p2pv2Bootstrappers                     = [{{range .P2PV2Bootstrappers}}"{{.}}",{{end}}]{{end}}
+p2pv2Bootstrappers                     = [{{range .P2PV2Bootstrappers}}"{{.}}",{{end}}]
+{{- end}}
 {{- if .MonitoringEndpoint}}
-monitoringEndpoint                     = "{{.MonitoringEndpoint}}" {{end}}
+monitoringEndpoint                     = "{{.MonitoringEndpoint}}"
+{{- end}}
+{{- if .CaptureEATelemetry}}
+captureEATelemetry                     = true
+{{- end}}
 {{- if .ObservationSource}}
 observationSource                      = """
 {{.ObservationSource}}
-"""{{end}}
-{{if eq .JobType "offchainreporting2" }}
-[pluginConfig]{{range $key, $value := .PluginConfig}}
-{{$key}} = {{$value}}{{end}}
-{{end}}
-{{.RelayConfig}}
-`
+"""
+{{- end}}
+{{- if eq .JobType "offchainreporting2"}}
+
+[pluginConfig]
+{{- range $key, $value := .PluginConfig}}
+{{$key}} = {{$value}}
+{{- end}}
+{{- end}}
+
+{{.RelayConfig}}`
 	return MarshallTemplate(specWrap, "OCR2 Job", ocr2TemplateString)
 }
 
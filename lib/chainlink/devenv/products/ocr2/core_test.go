@@ -0,0 +1,69 @@
+package ocr2
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// requireGolden compares got with testdata/name, run with -update to rewrite the golden file
+func requireGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o600))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(want), got)
+}
+
+func TestBootstrapJobSpec(t *testing.T) {
+	spec := &TaskJobSpec{
+		Name:    "ocr2_bootstrap",
+		JobType: JobTypeBootstrap,
+		OCR2OracleSpec: OracleSpec{
+			ContractID: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+			Relay:      "evm",
+			RelayConfig: map[string]any{
+				"chainID": "1337",
+			},
+			ContractConfigTrackerPollInterval: *NewInterval(5 * time.Second),
+		},
+	}
+	got, err := spec.String()
+	require.NoError(t, err)
+	requireGolden(t, "bootstrap_job.toml", got)
+}
+
+func TestGenerateJobSpecs(t *testing.T) {
+	cfg := &Configurator{OCR2: &OCR2{
+		Jobs: &Jobs{
+			MaxTaskDurationSec: 60,
+			BridgeHeaders:      map[string]string{"Authorization": "Bearer token"},
+			ObservationSource:  &ObservationSource{Sources: 2, AllowedFaults: 1},
+		},
+	}}
+	specs, err := GenerateJobSpecs(cfg, "0x5FbDB2315678afecb367f032d93F642f64180aa3", []string{
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+	}, "1337")
+	require.NoError(t, err)
+	require.Len(t, specs, 3)
+	requireGolden(t, "render_bootstrap.toml", specs["bootstrap.toml"])
+	requireGolden(t, "render_worker.toml", specs["worker-1.toml"])
+	require.Contains(t, specs["worker-2.toml"], `"ocr2-node2"`)
+	require.Contains(t, specs["worker-2.toml"], `"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"`)
+
+	specs, err = GenerateJobSpecs(cfg, "", nil, "1337")
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	require.Contains(t, specs["bootstrap.toml"], PlaceholderAggregatorAddress)
+	require.Contains(t, specs["worker-1.toml"], PlaceholderTransmitter)
+}
@@ -5,29 +5,307 @@ import (
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
-	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
 )
 
 const (
 	AnvilKey0                     = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
 	DefaultNativeTransferGasPrice = 21000
+	// DefaultAnvilBlockTime is used when block time is not set in Anvil docker_cmd_params
+	DefaultAnvilBlockTime = 1 * time.Second
 )
 
+// anvilKeys are well-known private keys of Anvil and Hardhat default accounts 0-9, all are funded on a fresh local chain
+var anvilKeys = []string{
+	AnvilKey0,
+	"59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+	"5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a",
+	"7c852118294e51e653712a81e05800f419141751be58f605c371e15141b007a6",
+	"47e179ec197488593b187f80a00eb0da91f1b9d0b13f8733639f19c30a34926a",
+	"8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba",
+	"92db14e403b83dfe3df233f83dfa3a0d7096f21ca9b0d6d6b8d88b2b4ec1564e",
+	"4bbbf85ce3377467afe5d46f804f221813b2bb87f24d81f60f1fcdbf7cbf4356",
+	"dbda1821b80551c9d65939329250298aa3472ba22feea921c0cf5d620ea67b97",
+	"2a871d0798f97d79848a013d4936a73bf4cc922c825d33c1cf7073dff6d409c6",
+}
+
+// AnvilKey returns private key of well-known Anvil account i, ex.: a separate deployer, payee or transmitter owner in tests
+func AnvilKey(i int) (string, error) {
+	if i < 0 || i >= len(anvilKeys) {
+		return "", fmt.Errorf("anvil key index %d is out of range, there are %d well-known keys", i, len(anvilKeys))
+	}
+	return anvilKeys[i], nil
+}
+
+// AnvilBlockTime returns block time from Anvil "-b" or "--block-time" params, ex.: ["-b", "1"], block time must be
+// whole seconds, Anvil accepts sub-second block times, but evm_setIntervalMining, which tests use to re-apply it, takes seconds
+func AnvilBlockTime(params []string) (time.Duration, error) {
+	for i, p := range params {
+		var v string
+		switch {
+		case p == "-b" || p == "--block-time":
+			if i+1 >= len(params) {
+				return 0, fmt.Errorf("no value for Anvil block time param %s", p)
+			}
+			v = params[i+1]
+		case strings.HasPrefix(p, "--block-time="):
+			v = strings.TrimPrefix(p, "--block-time=")
+		default:
+			continue
+		}
+		sec, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid Anvil block time %q: %w", v, err)
+		}
+		if sec <= 0 {
+			return 0, fmt.Errorf("anvil block time must be positive, got %s", v)
+		}
+		if sec < 1 || sec != math.Trunc(sec) {
+			return 0, fmt.Errorf("anvil block time must be whole seconds, got %s, evm_setIntervalMining can't set sub-second intervals", v)
+		}
+		return time.Duration(sec) * time.Second, nil
+	}
+	return DefaultAnvilBlockTime, nil
+}
+
+// AnvilMining is how Anvil mines blocks according to its docker_cmd_params, ex.: ["-b", "1", "--mixed-mining"]
+type AnvilMining struct {
+	// BlockTime is the interval mining block time, DefaultAnvilBlockTime if it's not set
+	BlockTime time.Duration
+	// Mixed mines blocks on the interval and on every transaction
+	Mixed bool
+}
+
+// AnvilMiningMode returns block time and mixed mining from Anvil params
+func AnvilMiningMode(params []string) (AnvilMining, error) {
+	blockTime, err := AnvilBlockTime(params)
+	if err != nil {
+		return AnvilMining{}, err
+	}
+	return AnvilMining{BlockTime: blockTime, Mixed: slices.Contains(params, "--mixed-mining")}, nil
+}
+
+// SetAnvilIntervalMining sets Anvil block time with evm_setIntervalMining, it takes whole seconds,
+// so sub-second and fractional intervals are rejected instead of being rounded
+func SetAnvilIntervalMining(ctx context.Context, c *ethclient.Client, interval time.Duration) error {
+	if interval < time.Second || interval%time.Second != 0 {
+		return fmt.Errorf("anvil mining interval must be whole seconds, got %s", interval)
+	}
+	sec := int64(interval / time.Second)
+	if err := c.Client().CallContext(ctx, nil, "evm_setIntervalMining", sec); err != nil {
+		return fmt.Errorf("could not set Anvil mining interval: %w", err)
+	}
+	zerolog.Ctx(ctx).Info().Int64("Seconds", sec).Msg("Anvil mining interval set")
+	return nil
+}
+
+// RPCCaller makes raw JSON-RPC calls, ex.: (*ethclient.Client).Client()
+type RPCCaller interface {
+	CallContext(ctx context.Context, result any, method string, args ...any) error
+}
+
+// AnvilMiner switches Anvil between interval and manual mining and mines blocks on demand,
+// so gas scenarios advance one block per step instead of racing the block timer
+type AnvilMiner struct {
+	rpc RPCCaller
+}
+
+// NewAnvilMiner creates a miner for the Anvil node c is connected to
+func NewAnvilMiner(c RPCCaller) *AnvilMiner {
+	return &AnvilMiner{rpc: c}
+}
+
+// Mine mines exactly one block
+func (m *AnvilMiner) Mine(ctx context.Context) error {
+	if err := m.rpc.CallContext(ctx, nil, "evm_mine"); err != nil {
+		return fmt.Errorf("could not mine Anvil block: %w", err)
+	}
+	return nil
+}
+
+// Automine reports whether Anvil mines a block on every transaction
+func (m *AnvilMiner) Automine(ctx context.Context) (bool, error) {
+	var enabled bool
+	if err := m.rpc.CallContext(ctx, &enabled, "anvil_getAutomine"); err != nil {
+		return false, fmt.Errorf("could not read Anvil automine: %w", err)
+	}
+	return enabled, nil
+}
+
+// ManualMining disables automine and interval mining for the duration of fn, blocks are only mined by Mine.
+// The mining mode Anvil was in is restored afterward, even if fn fails: automine if it was on, otherwise interval mining
+// with mode block time. Mixed mining can't be set over RPC, it's restored as interval mining
+func (m *AnvilMiner) ManualMining(ctx context.Context, mode AnvilMining, fn func() error) (err error) {
+	if mode.BlockTime < time.Second || mode.BlockTime%time.Second != 0 {
+		return fmt.Errorf("anvil block time %s can't be restored, evm_setIntervalMining takes whole seconds", mode.BlockTime)
+	}
+	sec := int64(mode.BlockTime / time.Second)
+	automine, err := m.Automine(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.rpc.CallContext(ctx, nil, "evm_setAutomine", false); err != nil {
+		return fmt.Errorf("could not disable Anvil automine: %w", err)
+	}
+	defer func() {
+		// restore even if ctx is cancelled, otherwise the chain stops producing blocks
+		rctx := context.WithoutCancel(ctx)
+		var rErr error
+		if automine {
+			rErr = m.rpc.CallContext(rctx, nil, "evm_setAutomine", true)
+		} else {
+			if mode.Mixed {
+				zerolog.Ctx(ctx).Warn().Msg("Anvil mixed mining can't be set over RPC, restoring interval mining")
+			}
+			rErr = m.rpc.CallContext(rctx, nil, "evm_setIntervalMining", sec)
+		}
+		if rErr != nil {
+			err = errors.Join(err, fmt.Errorf("could not restore Anvil mining mode: %w", rErr))
+		}
+	}()
+	if err := m.rpc.CallContext(ctx, nil, "evm_setIntervalMining", 0); err != nil {
+		return fmt.Errorf("could not disable Anvil interval mining: %w", err)
+	}
+	zerolog.Ctx(ctx).Info().Msg("Anvil manual mining enabled")
+	return fn()
+}
+
+// BlockNumberReader reads the latest block number, ex.: *ethclient.Client
+type BlockNumberReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// HeaderReader reads block headers, ex.: *ethclient.Client
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// LatestBaseFee returns base fee of the latest block, chains without EIP-1559 are rejected
+func LatestBaseFee(ctx context.Context, c HeaderReader) (*big.Int, error) {
+	h, err := c.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not read latest block header: %w", err)
+	}
+	if h.BaseFee == nil {
+		return nil, fmt.Errorf("block %s has no base fee, chain doesn't support EIP-1559", h.Number)
+	}
+	return h.BaseFee, nil
+}
+
+// ConfirmedCallOpts returns call options reading contract state confirmations blocks behind the head,
+// so values that can be reorged out are not read, 0 confirmations reads the latest block
+func ConfirmedCallOpts(ctx context.Context, c BlockNumberReader, confirmations uint64) (*bind.CallOpts, error) {
+	if confirmations == 0 {
+		return &bind.CallOpts{Context: ctx}, nil
+	}
+	head, err := c.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not read latest block number: %w", err)
+	}
+	if head < confirmations {
+		return nil, fmt.Errorf("chain has only %d blocks, can't read state %d blocks behind the head", head, confirmations)
+	}
+	return &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(head - confirmations)}, nil
+}
+
+// ToWei converts an amount of ETH or LINK to wei using its decimal representation,
+// amounts that can't be represented in wei exactly, ex.: more than 18 decimals, are rejected instead of being truncated
+func ToWei(amount float64) (*big.Int, error) {
+	return ToTokenUnits(amount, 18)
+}
+
+// ToTokenUnits converts an amount of a token with decimals to its smallest units, ex.: 1.5 of a 6 decimals token is 1500000,
+// amounts with more decimals than the token has are rejected instead of being truncated
+func ToTokenUnits(amount float64, decimals uint8) (*big.Int, error) {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) || amount < 0 {
+		return nil, fmt.Errorf("amount must be a non-negative number, got %v", amount)
+	}
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	if !ok {
+		return nil, fmt.Errorf("could not parse amount %v", amount)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("amount %v has more than %d decimals and can't be converted to token units exactly", amount, decimals)
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// BalanceFunc reads native or token balance of an address
+type BalanceFunc func(ctx context.Context, addr common.Address) (*big.Int, error)
+
+// VerifyBalances checks that every address has at least minWei of asset, all underfunded addresses are reported at once
+func VerifyBalances(ctx context.Context, asset string, addrs []common.Address, minWei *big.Int, balance BalanceFunc) error {
+	mins := make([]*big.Int, len(addrs))
+	for i := range addrs {
+		mins[i] = minWei
+	}
+	return VerifyBalancesEach(ctx, asset, addrs, mins, balance)
+}
+
+// VerifyBalancesEach checks that every address has at least its own minimum of asset, ex.: nodes funded with different amounts,
+// all underfunded addresses are reported at once
+func VerifyBalancesEach(ctx context.Context, asset string, addrs []common.Address, minWei []*big.Int, balance BalanceFunc) error {
+	if len(addrs) != len(minWei) {
+		return fmt.Errorf("got %d addresses but %d minimum balances", len(addrs), len(minWei))
+	}
+	underfunded := make([]string, 0)
+	for i, addr := range addrs {
+		b, err := balance(ctx, addr)
+		if err != nil {
+			return fmt.Errorf("could not read %s balance of %s: %w", asset, addr.Hex(), err)
+		}
+		if b.Cmp(minWei[i]) < 0 {
+			underfunded = append(underfunded, fmt.Sprintf("%s has %s wei, expected at least %s", addr.Hex(), b, minWei[i]))
+			continue
+		}
+		zerolog.Ctx(ctx).Info().Str("Addr", addr.Hex()).Str("Asset", asset).Str("Wei", b.String()).Msg("Verified node balance")
+	}
+	if len(underfunded) > 0 {
+		return fmt.Errorf("nodes are underfunded with %s wei: %s", asset, strings.Join(underfunded, "; "))
+	}
+	return nil
+}
+
+// checkBlockchainOut returns an error if blockchain output is not populated, ex.: the blockchain failed to come up
+func checkBlockchainOut(bc *blockchain.Input) error {
+	if bc == nil {
+		return errors.New("blockchain configuration is not set")
+	}
+	if bc.Out == nil {
+		return fmt.Errorf("output configuration for %s blockchain %s is not set", bc.Type, bc.ChainID)
+	}
+	if len(bc.Out.Nodes) == 0 {
+		return fmt.Errorf("output configuration for %s blockchain %s has no nodes", bc.Type, bc.ChainID)
+	}
+	return nil
+}
+
 // FundNodeEIP1559 funds CL node using RPC URL, recipient address and amount of funds to send (ETH).
 // Uses EIP-1559 transaction type.
-func FundNodeEIP1559(ctx context.Context, c *ethclient.Client, pkey, recipientAddress string, amountOfFundsInETH float64) error {
+func FundNodeEIP1559(ctx context.Context, c *ethclient.Client, pkey, recipientAddress string, amountOfFundsInETH float64, feeCapMult, tipCapMult int64, w WaitConfig) error {
 	l := zerolog.Ctx(ctx)
-	amount := new(big.Float).Mul(big.NewFloat(amountOfFundsInETH), big.NewFloat(1e18))
-	amountWei, _ := amount.Int(nil)
+	amountWei, err := ToWei(amountOfFundsInETH)
+	if err != nil {
+		return err
+	}
 	l.Info().Str("Addr", recipientAddress).Str("Wei", amountWei.String()).Msg("Funding Node")
 
 	chainID, err := c.NetworkID(context.Background())
@@ -50,11 +328,7 @@ func FundNodeEIP1559(ctx context.Context, c *ethclient.Client, pkey, recipientAd
 	if err != nil {
 		return err
 	}
-	feeCap, err := c.SuggestGasPrice(context.Background())
-	if err != nil {
-		return err
-	}
-	tipCap, err := c.SuggestGasTipCap(context.Background())
+	feeCap, tipCap, err := multiplyEIP1559GasPrices(c, feeCapMult, tipCapMult)
 	if err != nil {
 		return err
 	}
@@ -76,7 +350,7 @@ func FundNodeEIP1559(ctx context.Context, c *ethclient.Client, pkey, recipientAd
 	if err != nil {
 		return err
 	}
-	if _, err := bind.WaitMined(context.Background(), c, signedTx); err != nil {
+	if _, err := WaitMined(context.Background(), c, signedTx, w); err != nil {
 		return err
 	}
 	l.Info().Str("Wei", amountWei.String()).Msg("Funded with ETH")
@@ -84,8 +358,13 @@ func FundNodeEIP1559(ctx context.Context, c *ethclient.Client, pkey, recipientAd
 }
 
 // ETHClient creates a basic Ethereum client using PRIVATE_KEY env var and tip/cap gas settings
+// RPC traffic is recorded if RECORD_RPC is set, see RecordRPCURL
 func ETHClient(ctx context.Context, rpcURL string, feeCapMult int64, tipCapMult int64) (*ethclient.Client, *bind.TransactOpts, string, error) {
 	l := zerolog.Ctx(ctx)
+	rpcURL, err := RecordRPCURL(rpcURL)
+	if err != nil {
+		return nil, nil, "", err
+	}
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("could not connect to eth client: %w", err)
@@ -131,11 +410,144 @@ func multiplyEIP1559GasPrices(client *ethclient.Client, fcMult, tcMult int64) (*
 	return new(big.Int).Mul(feeCap, big.NewInt(fcMult)), new(big.Int).Mul(tipCap, big.NewInt(tcMult)), nil
 }
 
+// transactOpts returns a copy of auth with current gas prices bumped by operation multipliers,
+// prices are read once base fee is within gas guard limits
+func (g *GasSettings) transactOpts(ctx context.Context, c *ethclient.Client, auth *bind.TransactOpts, op GasOperation) (*bind.TransactOpts, error) {
+	if err := g.guard().Await(ctx, c); err != nil {
+		return nil, fmt.Errorf("could not send %s transactions: %w", op, err)
+	}
+	feeCapMult, tipCapMult := g.Multipliers(op)
+	fc, tc, err := multiplyEIP1559GasPrices(c, feeCapMult, tipCapMult)
+	if err != nil {
+		return nil, fmt.Errorf("could not get bumped gas price for %s transactions: %w", op, err)
+	}
+	opts := *auth
+	opts.GasFeeCap = fc
+	opts.GasTipCap = tc
+	return &opts, nil
+}
+
 func getNetworkPrivateKey() string {
-	pk := os.Getenv("PRIVATE_KEY")
-	if pk == "" {
-		// that's the first Anvil and Geth private key, serves as a fallback for local testing if not overridden
-		return AnvilKey0
+	// that's the first Anvil and Geth private key, serves as a fallback for local testing if not overridden
+	return defaults.EnvOr("PRIVATE_KEY", AnvilKey0)
+}
+
+// errNoSubscriptions is returned by waitMinedSubscribe if a backend can't subscribe to new heads, ex.: HTTP RPC
+var errNoSubscriptions = errors.New("backend can't subscribe to new heads")
+
+// WaitMined waits for transaction receipt confirming it as configured by w, by polling or on new heads, see WaitConfig.Confirm,
+// unlike bind.WaitMined it gives up after w timeout or attempts. Backends that can't subscribe, ex.: HTTP clients, are polled
+func WaitMined(ctx context.Context, c bind.DeployBackend, tx *types.Transaction, w WaitConfig) (*types.Receipt, error) {
+	var (
+		receipt *types.Receipt
+		err     error
+	)
+	if w.Confirm == ConfirmSubscribe {
+		receipt, err = waitMinedSubscribe(ctx, c, tx, w)
+		if errors.Is(err, errNoSubscriptions) {
+			ctxLogger(ctx).Warn().Err(err).Str("TxHash", tx.Hash().Hex()).Msg("Polling transaction receipt instead")
+			receipt, err = waitMinedPoll(ctx, c, tx, w)
+		}
+	} else {
+		receipt, err = waitMinedPoll(ctx, c, tx, w)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s is not mined: %w", tx.Hash().Hex(), err)
+	}
+	return receipt, nil
+}
+
+// fetchReceipt returns transaction receipt, nil if the transaction is not mined yet
+func fetchReceipt(ctx context.Context, c bind.DeployBackend, tx *types.Transaction) (*types.Receipt, error) {
+	r, err := c.TransactionReceipt(ctx, tx.Hash())
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	return r, err
+}
+
+// waitMinedPoll fetches transaction receipt every w poll interval
+func waitMinedPoll(ctx context.Context, c bind.DeployBackend, tx *types.Transaction, w WaitConfig) (*types.Receipt, error) {
+	var receipt *types.Receipt
+	err := w.Poll(ctx, func(ctx context.Context) (bool, error) {
+		r, err := fetchReceipt(ctx, c, tx)
+		if err != nil || r == nil {
+			return false, err
+		}
+		receipt = r
+		return true, nil
+	})
+	return receipt, err
+}
+
+// HeadSubscriber subscribes to new chain heads, ethclient.Client connected over websocket implements it
+type HeadSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// waitMinedSubscribe fetches transaction receipt once subscribed and then on every new head, w timeout and attempts bound it
+// the same way they bound polling, failed fetches are retried on the next head
+func waitMinedSubscribe(ctx context.Context, c bind.DeployBackend, tx *types.Transaction, w WaitConfig) (*types.Receipt, error) {
+	hs, ok := c.(HeadSubscriber)
+	if !ok {
+		return nil, errNoSubscriptions
+	}
+	if w.TimeoutSec > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout())
+		defer cancel()
+	}
+	heads := make(chan *types.Header, 1)
+	sub, err := hs.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errNoSubscriptions, err)
+	}
+	defer sub.Unsubscribe()
+	var lastErr error
+	// the transaction can be mined before the subscription is live, so the receipt is fetched right away
+	for attempt := 1; ; attempt++ {
+		r, err := fetchReceipt(ctx, c, tx)
+		switch {
+		case err != nil:
+			lastErr = err
+		case r != nil:
+			return r, nil
+		}
+		if w.MaxAttempts > 0 && attempt >= w.MaxAttempts {
+			return nil, exhausted(fmt.Sprintf("%d attempts", attempt), lastErr)
+		}
+		select {
+		case <-ctx.Done():
+			if w.TimeoutSec == 0 || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ctx.Err()
+			}
+			return nil, exhausted(w.Timeout().String(), lastErr)
+		case err := <-sub.Err():
+			return nil, fmt.Errorf("new heads subscription failed: %w", err)
+		case <-heads:
+		}
+	}
+}
+
+// WaitDeployed waits for contract deployment transaction and returns the address of deployed contract,
+// see bind.WaitDeployed
+func WaitDeployed(ctx context.Context, c bind.DeployBackend, tx *types.Transaction, w WaitConfig) (common.Address, error) {
+	if tx.To() != nil {
+		return common.Address{}, errors.New("transaction is not a contract creation")
+	}
+	receipt, err := WaitMined(ctx, c, tx, w)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if receipt.ContractAddress == (common.Address{}) {
+		return common.Address{}, errors.New("zero address")
+	}
+	code, err := c.CodeAt(ctx, receipt.ContractAddress, nil)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("could not read deployed contract code: %w", err)
+	}
+	if len(code) == 0 {
+		return common.Address{}, bind.ErrNoCodeAfterDeploy
 	}
-	return pk
+	return receipt.ContractAddress, nil
 }
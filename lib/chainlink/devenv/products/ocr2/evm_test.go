@@ -0,0 +1,402 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
+)
+
+func TestAnvilBlockTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", params: []string{"--mixed-mining"}, want: DefaultAnvilBlockTime},
+		{name: "short flag", params: []string{"-b", "2", "--mixed-mining"}, want: 2 * time.Second},
+		{name: "long flag", params: []string{"--block-time", "4"}, want: 4 * time.Second},
+		{name: "sub-second", params: []string{"--block-time", "0.5"}, wantErr: true},
+		{name: "fractional", params: []string{"-b", "1.5"}, wantErr: true},
+		{name: "long flag with value", params: []string{"--block-time=3"}, want: 3 * time.Second},
+		{name: "no value", params: []string{"-b"}, wantErr: true},
+		{name: "not a number", params: []string{"-b", "fast"}, wantErr: true},
+		{name: "zero", params: []string{"-b", "0"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := AnvilBlockTime(tc.params)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestToWei(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  float64
+		want    string
+		wantErr bool
+	}{
+		{name: "whole", amount: 50, want: "50000000000000000000"},
+		{name: "fraction is exact", amount: 1.1, want: "1100000000000000000"},
+		{name: "smallest unit", amount: 1e-18, want: "1"},
+		{name: "zero", amount: 0, want: "0"},
+		{name: "too many decimals", amount: 1e-19, wantErr: true},
+		{name: "negative", amount: -1, wantErr: true},
+		{name: "not a number", amount: math.NaN(), wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ToWei(tc.amount)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.String())
+		})
+	}
+}
+
+func TestToTokenUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		decimals uint8
+		want     string
+		wantErr  string
+	}{
+		{name: "6 decimals", amount: 50, decimals: 6, want: "50000000"},
+		{name: "6 decimals fraction", amount: 1.5, decimals: 6, want: "1500000"},
+		{name: "no decimals", amount: 7, decimals: 0, want: "7"},
+		{name: "18 decimals is wei", amount: 1e-18, decimals: 18, want: "1"},
+		{name: "more decimals than the token has", amount: 1e-7, decimals: 6, wantErr: "more than 6 decimals"},
+		{name: "fraction of a token without decimals", amount: 0.5, decimals: 0, wantErr: "more than 0 decimals"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ToTokenUnits(tc.amount, tc.decimals)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.String())
+		})
+	}
+}
+
+func TestVerifyBalances(t *testing.T) {
+	funded := common.HexToAddress("0x01")
+	underfunded := common.HexToAddress("0x02")
+	balances := map[common.Address]*big.Int{
+		funded:      big.NewInt(100),
+		underfunded: big.NewInt(99),
+	}
+	balance := func(_ context.Context, addr common.Address) (*big.Int, error) {
+		return balances[addr], nil
+	}
+
+	require.NoError(t, VerifyBalances(context.Background(), "LINK", []common.Address{funded}, big.NewInt(100), balance))
+	err := VerifyBalances(context.Background(), "LINK", []common.Address{funded, underfunded}, big.NewInt(100), balance)
+	require.ErrorContains(t, err, underfunded.Hex())
+	require.NotContains(t, err.Error(), funded.Hex())
+
+	// a node funded with less than others is checked against its own amount
+	require.NoError(t, VerifyBalancesEach(context.Background(), "ETH", []common.Address{funded, underfunded}, []*big.Int{big.NewInt(100), big.NewInt(0)}, balance))
+	err = VerifyBalancesEach(context.Background(), "ETH", []common.Address{funded, underfunded}, []*big.Int{big.NewInt(101), big.NewInt(99)}, balance)
+	require.ErrorContains(t, err, funded.Hex()+" has 100 wei, expected at least 101")
+	require.NotContains(t, err.Error(), underfunded.Hex())
+	require.Error(t, VerifyBalancesEach(context.Background(), "ETH", []common.Address{funded}, nil, balance))
+}
+
+type headReader uint64
+
+func (h headReader) BlockNumber(context.Context) (uint64, error) { return uint64(h), nil }
+
+func TestConfirmedCallOpts(t *testing.T) {
+	ctx := context.Background()
+	opts, err := ConfirmedCallOpts(ctx, headReader(100), 0)
+	require.NoError(t, err)
+	require.Nil(t, opts.BlockNumber, "latest block must be read without confirmations")
+
+	opts, err = ConfirmedCallOpts(ctx, headReader(100), 10)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(90), opts.BlockNumber)
+
+	_, err = ConfirmedCallOpts(ctx, headReader(5), 10)
+	require.Error(t, err)
+}
+
+type headerReader types.Header
+
+func (h *headerReader) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return (*types.Header)(h), nil
+}
+
+func TestLatestBaseFee(t *testing.T) {
+	ctx := context.Background()
+	fee, err := LatestBaseFee(ctx, &headerReader{Number: big.NewInt(7), BaseFee: big.NewInt(25e9)})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(25e9), fee)
+
+	_, err = LatestBaseFee(ctx, &headerReader{Number: big.NewInt(7)})
+	require.ErrorContains(t, err, "block 7 has no base fee")
+}
+
+// recordingRPC records JSON-RPC calls, anvil_getAutomine returns automine
+type recordingRPC struct {
+	calls    []string
+	automine bool
+	failOn   string
+}
+
+func (r *recordingRPC) CallContext(_ context.Context, result any, method string, args ...any) error {
+	r.calls = append(r.calls, strings.TrimSpace(fmt.Sprintln(append([]any{method}, args...)...)))
+	if method == r.failOn {
+		return errors.New("rpc failed")
+	}
+	if method == "anvil_getAutomine" {
+		*result.(*bool) = r.automine
+	}
+	return nil
+}
+
+func TestAnvilMiningMode(t *testing.T) {
+	mode, err := AnvilMiningMode([]string{"-b", "2", "--mixed-mining", "--slots-in-an-epoch", "1"})
+	require.NoError(t, err)
+	require.Equal(t, AnvilMining{BlockTime: 2 * time.Second, Mixed: true}, mode)
+
+	mode, err = AnvilMiningMode(nil)
+	require.NoError(t, err)
+	require.Equal(t, AnvilMining{BlockTime: DefaultAnvilBlockTime}, mode)
+
+	_, err = AnvilMiningMode([]string{"-b"})
+	require.Error(t, err)
+}
+
+func TestAnvilMinerManualMining(t *testing.T) {
+	ctx := context.Background()
+	interval := AnvilMining{BlockTime: 2 * time.Second, Mixed: true}
+
+	// interval mining is restored with the block time Anvil was started with
+	r := &recordingRPC{}
+	m := NewAnvilMiner(r)
+	err := m.ManualMining(ctx, interval, func() error { return m.Mine(ctx) })
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"anvil_getAutomine",
+		"evm_setAutomine false",
+		"evm_setIntervalMining 0",
+		"evm_mine",
+		"evm_setIntervalMining 2",
+	}, r.calls)
+
+	// automine is restored without switching to interval mining
+	r = &recordingRPC{automine: true}
+	err = NewAnvilMiner(r).ManualMining(ctx, interval, func() error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, "evm_setAutomine true", r.calls[len(r.calls)-1])
+	require.NotContains(t, r.calls, "evm_setIntervalMining 2")
+
+	// mining mode is restored when the scenario fails
+	r = &recordingRPC{}
+	err = NewAnvilMiner(r).ManualMining(ctx, interval, func() error { return errors.New("scenario failed") })
+	require.ErrorContains(t, err, "scenario failed")
+	require.Equal(t, "evm_setIntervalMining 2", r.calls[len(r.calls)-1])
+
+	r = &recordingRPC{failOn: "evm_setIntervalMining"}
+	err = NewAnvilMiner(r).ManualMining(ctx, interval, func() error { return nil })
+	require.ErrorContains(t, err, "could not disable Anvil interval mining")
+	require.ErrorContains(t, err, "could not restore Anvil mining mode")
+
+	for _, bt := range []time.Duration{0, 500 * time.Millisecond, 1500 * time.Millisecond} {
+		r = &recordingRPC{}
+		err = NewAnvilMiner(r).ManualMining(ctx, AnvilMining{BlockTime: bt}, func() error { return nil })
+		require.ErrorContains(t, err, "can't be restored")
+		require.Empty(t, r.calls, "mining mode must not change if it can't be restored")
+	}
+}
+
+func TestCheckBlockchainOut(t *testing.T) {
+	tests := []struct {
+		name    string
+		bc      *blockchain.Input
+		wantErr string
+	}{
+		{name: "not set", wantErr: "blockchain configuration is not set"},
+		{name: "no output", bc: &blockchain.Input{Type: "anvil", ChainID: "1337"}, wantErr: "output configuration for anvil blockchain 1337 is not set"},
+		{name: "no nodes", bc: &blockchain.Input{Type: "anvil", ChainID: "1337", Out: &blockchain.Output{}}, wantErr: "output configuration for anvil blockchain 1337 has no nodes"},
+		{name: "populated", bc: &blockchain.Input{Type: "anvil", ChainID: "1337", Out: &blockchain.Output{Nodes: []*blockchain.Node{{}}}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkBlockchainOut(tc.bc)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+	// partial bring-up returns an error instead of panicking
+	_, err := (&Configurator{OCR2: &OCR2{}}).GenerateCLNodesBlockchainConfig(context.Background(), &blockchain.Input{Type: "anvil", ChainID: "1337"})
+	require.ErrorContains(t, err, "is not set")
+}
+
+func TestGenerateCLNodesBlockchainConfigChainID(t *testing.T) {
+	bc := &blockchain.Input{Type: "anvil", ChainID: "2337", Out: &blockchain.Output{
+		ChainID: "2337",
+		Nodes:   []*blockchain.Node{{InternalWSUrl: "ws://anvil:8545", InternalHTTPUrl: "http://anvil:8545"}},
+	}}
+	cfg, err := (&Configurator{OCR2: &OCR2{ChainFinalityDepth: 5, Telemetry: &Telemetry{Enabled: true, URL: "ingress:5000", ServerPubKey: "key"}}}).GenerateCLNodesBlockchainConfig(context.Background(), bc)
+	require.NoError(t, err)
+	require.Contains(t, cfg, "ChainID = '2337'")
+	require.NotContains(t, cfg, "1337")
+
+	err = checkBlockchainOut(&blockchain.Input{Type: "anvil", ChainID: "2337"})
+	require.EqualError(t, err, "output configuration for anvil blockchain 2337 is not set")
+}
+
+func TestAnvilKey(t *testing.T) {
+	// addresses of Anvil default accounts
+	want := []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+		"0x90F79bf6EB2c4f870365E785982E1f101E93b906",
+		"0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65",
+		"0x9965507D1a55bcC2695C58ba16FB37d819B0A4dc",
+		"0x976EA74026E726554dB657fA54763abd0C3a0aa9",
+		"0x14dC79964da2C08b23698B3D3cc7Ca32193d9955",
+		"0x23618e81E3f5cdF7f54C3d65f7FBc0aBf5B21E8f",
+		"0xa0Ee7A142d267C1f36714E4a8F75612F20a79720",
+	}
+	for i, addr := range want {
+		k, err := AnvilKey(i)
+		require.NoError(t, err)
+		pk, err := crypto.HexToECDSA(k)
+		require.NoError(t, err)
+		require.Equal(t, addr, crypto.PubkeyToAddress(pk.PublicKey).Hex())
+	}
+	for _, i := range []int{-1, len(want)} {
+		_, err := AnvilKey(i)
+		require.ErrorContains(t, err, "out of range")
+	}
+}
+
+// minedAfter is a backend that mines a transaction on the mineAt receipt fetch
+type minedAfter struct {
+	mu      sync.Mutex
+	fetches int
+	mineAt  int
+}
+
+func (b *minedAfter) TransactionReceipt(_ context.Context, h common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fetches++
+	if b.fetches < b.mineAt {
+		return nil, ethereum.NotFound
+	}
+	return &types.Receipt{TxHash: h}, nil
+}
+
+func (b *minedAfter) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+// minedAfterHeads also produces a new head every millisecond, like a websocket client subscribed to a fast chain
+type minedAfterHeads struct {
+	minedAfter
+}
+
+func (b *minedAfterHeads) SubscribeNewHead(_ context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for n := int64(1); ; n++ {
+			select {
+			case <-quit:
+				return nil
+			case <-ticker.C:
+				select {
+				case ch <- &types.Header{Number: big.NewInt(n)}:
+				case <-quit:
+					return nil
+				}
+			}
+		}
+	}), nil
+}
+
+// brokenHeads subscription fails right away, like a websocket connection dropped by RPC
+type brokenHeads struct {
+	minedAfterHeads
+}
+
+func (b *brokenHeads) SubscribeNewHead(context.Context, chan<- *types.Header) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(<-chan struct{}) error {
+		return errors.New("connection lost")
+	}), nil
+}
+
+func TestWaitMined(t *testing.T) {
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: 1})
+	tests := []struct {
+		name    string
+		backend bind.DeployBackend
+		w       WaitConfig
+		wantErr string
+	}{
+		{name: "poll", backend: &minedAfter{mineAt: 3}, w: WaitConfig{PollIntervalMs: 1, TimeoutSec: 5}},
+		{name: "poll by default", backend: &minedAfterHeads{minedAfter{mineAt: 3}}, w: WaitConfig{PollIntervalMs: 1, TimeoutSec: 5}},
+		{name: "subscribe", backend: &minedAfterHeads{minedAfter{mineAt: 3}}, w: WaitConfig{TimeoutSec: 5, Confirm: ConfirmSubscribe}},
+		{
+			name:    "subscribe attempts exhausted",
+			backend: &minedAfterHeads{minedAfter{mineAt: 100}},
+			w:       WaitConfig{MaxAttempts: 2, Confirm: ConfirmSubscribe},
+			wantErr: "wait exhausted after 2 attempts",
+		},
+		{name: "subscribe falls back to poll", backend: &minedAfter{mineAt: 3}, w: WaitConfig{PollIntervalMs: 1, TimeoutSec: 5, Confirm: ConfirmSubscribe}},
+		{
+			name:    "subscribe fails on a broken subscription",
+			backend: &brokenHeads{minedAfterHeads{minedAfter{mineAt: 100}}},
+			w:       WaitConfig{TimeoutSec: 5, Confirm: ConfirmSubscribe},
+			wantErr: "new heads subscription failed: connection lost",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := WaitMined(context.Background(), tt.backend, tx, tt.w)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tx.Hash(), r.TxHash)
+		})
+	}
+}
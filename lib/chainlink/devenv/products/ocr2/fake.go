@@ -0,0 +1,234 @@
+package ocr2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+)
+
+const (
+	// DefaultFakeServerRequestTimeout is used for fake server requests if ea_fake.request_timeout_sec is not set
+	DefaultFakeServerRequestTimeout = DefaultHTTPRequestTimeout
+	// DefaultEAValue is the value fake EA is seeded with before jobs are created if initial_value is not set
+	DefaultEAValue = 200
+	// JuelsModeConstant makes fake juels source return a constant ratio, it's the default
+	JuelsModeConstant = "constant"
+	// JuelsModeGasLinked makes fake juels source scale with the base fee pushed by SetBaseFee
+	JuelsModeGasLinked = "gas_linked"
+)
+
+// DefaultInitialValueWait bounds seeding of the initial value, the seed is retried until fake reports it
+var DefaultInitialValueWait = WaitConfig{PollIntervalMs: 500, TimeoutSec: 30}
+
+// RequestTimeout returns fake server request timeout, falls back to DefaultFakeServerRequestTimeout
+func (e *EAFake) RequestTimeout() time.Duration {
+	if e == nil || e.RequestTimeoutSec <= 0 {
+		return DefaultFakeServerRequestTimeout
+	}
+	return time.Duration(e.RequestTimeoutSec) * time.Second
+}
+
+// RetryCount returns how many times failed fake server requests are retried, falls back to DefaultHTTPRetryCount,
+// negative ea_fake.retry_count disables retries
+func (e *EAFake) RetryCount() int {
+	switch {
+	case e == nil || e.Retries == 0:
+		return DefaultHTTPRetryCount
+	case e.Retries < 0:
+		return 0
+	}
+	return e.Retries
+}
+
+func (e *EAFake) skipProbe() bool {
+	return e != nil && e.SkipProbe
+}
+
+// NewFakeServerClient creates a resty client for fake server with an explicit timeout and retries
+// so a hung or restarting fake fails fast instead of blocking the setup or test loop
+func NewFakeServerClient(baseURL string, e *EAFake) *resty.Client {
+	return newRestyClient(baseURL).
+		SetTimeout(e.RequestTimeout()).
+		SetRetryCount(e.RetryCount())
+}
+
+// WaitFakeServerReady polls fake server /health until it returns 200 or wait is exhausted
+func WaitFakeServerReady(ctx context.Context, r *resty.Client, w WaitConfig) error {
+	err := w.Poll(ctx, func(ctx context.Context) (bool, error) {
+		resp, err := r.R().SetContext(ctx).Get("/health")
+		if err != nil {
+			return false, err
+		}
+		return resp.IsSuccess(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("fake server is not ready: %w", err)
+	}
+	return nil
+}
+
+// TriggerDeviation sets the value fake EA returns, both transport errors and non-2xx statuses are returned as errors
+func TriggerDeviation(r *resty.Client, value int) error {
+	_, err := r.R().Post(fmt.Sprintf(`/trigger_deviation?result=%d`, value))
+	if err != nil {
+		return fmt.Errorf("fake server request failed: %w", err)
+	}
+	return nil
+}
+
+// SeedInitialValue sets the value fake EA returns and polls until fake reports it, a failed or lost seed request is retried
+func SeedInitialValue(ctx context.Context, r *resty.Client, value int, w WaitConfig) error {
+	err := w.Poll(ctx, func(ctx context.Context) (bool, error) {
+		if err := TriggerDeviation(r, value); err != nil {
+			return false, err
+		}
+		v, err := FakeValue(r)
+		if err != nil {
+			return false, err
+		}
+		if v != int64(value) {
+			return false, fmt.Errorf("fake returns %d, expected %d", v, value)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not seed fake initial value %d: %w", value, err)
+	}
+	ctxLogger(ctx).Info().Int("Value", value).Str("URL", r.BaseURL).Msg("Fake EA is seeded with the initial value")
+	return nil
+}
+
+// initialValue returns the value fake EAs are seeded with and whether seeding is required, it's required by default
+func (o *OCR2) initialValue() (int, bool) {
+	if o == nil {
+		return DefaultEAValue, true
+	}
+	return defaults.Coalesce(o.InitialValue, DefaultEAValue), o.RequireInitialValue == nil || *o.RequireInitialValue
+}
+
+// SeedFake seeds fake EA with the initial value and waits until fake confirms it, it's a no-op if require_initial_value = false
+func (o *OCR2) SeedFake(ctx context.Context, r *resty.Client) error {
+	value, required := o.initialValue()
+	if !required {
+		ctxLogger(ctx).Info().Str("URL", r.BaseURL).Msg("Initial value is not required, fake EA is not seeded")
+		return nil
+	}
+	return SeedInitialValue(ctx, r, value, DefaultInitialValueWait)
+}
+
+// EAProfile changes fake EA value over time, ex.: random walk for 2 minutes
+type EAProfile struct {
+	Kind     string
+	Start    int64
+	Step     int64
+	Min      int64
+	Max      int64
+	Interval time.Duration
+	Duration time.Duration
+}
+
+// SetProfile starts an EA profile on fake server, TriggerDeviation stops it
+func SetProfile(r *resty.Client, p *EAProfile) error {
+	_, err := r.R().
+		SetQueryParams(map[string]string{
+			"kind":         p.Kind,
+			"start":        strconv.FormatInt(p.Start, 10),
+			"step":         strconv.FormatInt(p.Step, 10),
+			"min":          strconv.FormatInt(p.Min, 10),
+			"max":          strconv.FormatInt(p.Max, 10),
+			"interval_sec": strconv.FormatInt(int64(p.Interval.Seconds()), 10),
+			"duration_sec": strconv.FormatInt(int64(p.Duration.Seconds()), 10),
+		}).
+		Post("/set_profile")
+	if err != nil {
+		return fmt.Errorf("fake server request failed: %w", err)
+	}
+	return nil
+}
+
+// FakeValue returns the value fake EA currently returns
+func FakeValue(r *resty.Client) (int64, error) {
+	var res struct {
+		Result string `json:"result"`
+	}
+	_, err := r.R().SetResult(&res).Get("/value")
+	if err != nil {
+		return 0, fmt.Errorf("fake server request failed: %w", err)
+	}
+	v, err := strconv.ParseInt(res.Result, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse fake value %q: %w", res.Result, err)
+	}
+	return v, nil
+}
+
+// SetJuelsMode changes how fake derives juels per fee coin ratio, ex.: JuelsModeGasLinked
+func SetJuelsMode(r *resty.Client, mode string) error {
+	_, err := r.R().SetQueryParam("mode", mode).Post("/set_juels_mode")
+	if err != nil {
+		return fmt.Errorf("fake server request failed: %w", err)
+	}
+	return nil
+}
+
+// SetBaseFee pushes the current block base fee in wei to fake, gas_linked juels mode derives the ratio from it
+func SetBaseFee(r *resty.Client, fee *big.Int) error {
+	_, err := r.R().SetQueryParam("wei", fee.String()).Post("/set_base_fee")
+	if err != nil {
+		return fmt.Errorf("fake server request failed: %w", err)
+	}
+	return nil
+}
+
+// SetLatency delays fake EA responses, sub-second precision is kept, 0 disables it
+func SetLatency(r *resty.Client, d time.Duration) error {
+	resp, err := r.R().SetQueryParam("duration", d.String()).Post("/set_latency")
+	if err != nil {
+		return fmt.Errorf("fake server request failed: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("could not set fake latency %s: %s", d, resp.String())
+	}
+	return nil
+}
+
+// FakeRequestsMetric counts requests fake served by route, method and status
+const FakeRequestsMetric = "fake_requests_total"
+
+// FakeRequests returns how many requests fake served on a route with a status, ex.: /ea and 200, summed over methods,
+// empty status sums all statuses, ex.: 499 of requests nodes gave up on
+func FakeRequests(r *resty.Client, path, status string) (float64, error) {
+	resp, err := r.R().SetHeader("Accept", "text/plain").Get("/metrics")
+	if err != nil {
+		return 0, fmt.Errorf("fake server request failed: %w", err)
+	}
+	if resp.IsError() {
+		return 0, fmt.Errorf("could not read fake metrics: %s", resp.Status())
+	}
+	var p expfmt.TextParser
+	families, err := p.TextToMetricFamilies(strings.NewReader(resp.String()))
+	if err != nil {
+		return 0, fmt.Errorf("could not parse fake metrics: %w", err)
+	}
+	var total float64
+	if mf, ok := families[FakeRequestsMetric]; ok {
+		for _, m := range mf.GetMetric() {
+			labels := make(map[string]string, len(m.GetLabel()))
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["path"] == path && (status == "" || labels["status"] == status) {
+				total += m.GetCounter().GetValue()
+			}
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,132 @@
+package ocr2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lossyFake drops the first seed requests, so the value it returns stays stale until a seed gets through
+type lossyFake struct {
+	mu      sync.Mutex
+	drops   int
+	seeds   int
+	value   string
+	handler http.Handler
+}
+
+func newLossyFake(drops int) *lossyFake {
+	f := &lossyFake{drops: drops, value: "0"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /trigger_deviation", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.seeds++
+		if f.seeds <= f.drops {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.value = r.URL.Query().Get("result")
+	})
+	mux.HandleFunc("GET /value", func(w http.ResponseWriter, _ *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result":"` + f.value + `"}`))
+	})
+	f.handler = mux
+	return f
+}
+
+func TestSeedFake(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name  string
+		cfg   *OCR2
+		drops int
+		seeds int
+		value string
+	}{
+		{name: "default value", cfg: &OCR2{}, seeds: 1, value: "200"},
+		{name: "nil config", seeds: 1, value: "200"},
+		{name: "configured value", cfg: &OCR2{InitialValue: 42}, seeds: 1, value: "42"},
+		{name: "retried until confirmed", cfg: &OCR2{}, drops: 2, seeds: 3, value: "200"},
+		{name: "not required", cfg: &OCR2{RequireInitialValue: &disabled}, seeds: 0, value: "0"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newLossyFake(tc.drops)
+			srv := httptest.NewServer(f.handler)
+			defer srv.Close()
+			err := tc.cfg.SeedFake(context.Background(), newRestyClient(srv.URL).SetRetryCount(0))
+			require.NoError(t, err)
+			require.Equal(t, tc.seeds, f.seeds)
+			require.Equal(t, tc.value, f.value)
+		})
+	}
+}
+
+func TestSeedInitialValueExhausted(t *testing.T) {
+	f := newLossyFake(100)
+	srv := httptest.NewServer(f.handler)
+	defer srv.Close()
+	err := SeedInitialValue(context.Background(), newRestyClient(srv.URL).SetRetryCount(0), 7, WaitConfig{PollIntervalMs: 1, MaxAttempts: 3})
+	require.ErrorIs(t, err, ErrWaitExhausted)
+	require.ErrorContains(t, err, "could not seed fake initial value 7")
+	require.Equal(t, 3, f.seeds)
+}
+
+func TestSetLatency(t *testing.T) {
+	var got []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /set_latency", func(w http.ResponseWriter, r *http.Request) {
+		d := r.URL.Query().Get("duration")
+		if strings.HasPrefix(d, "-") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got = append(got, d)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c := newRestyClient(srv.URL).SetRetryCount(0)
+
+	require.NoError(t, SetLatency(c, 5250*time.Millisecond))
+	require.NoError(t, SetLatency(c, 0))
+	require.ErrorContains(t, SetLatency(c, -time.Second), "could not set fake latency -1s")
+	require.Equal(t, []string{"5.25s", "0s"}, got)
+}
+
+func TestFakeRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`# HELP fake_requests_total Requests served by route, method and status
+# TYPE fake_requests_total counter
+fake_requests_total{method="POST",path="/ea",status="200"} 12
+fake_requests_total{method="POST",path="/ea",status="499"} 2
+fake_requests_total{method="GET",path="/value",status="200"} 3
+`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c := newRestyClient(srv.URL).SetRetryCount(0)
+
+	got, err := FakeRequests(c, "/ea", "200")
+	require.NoError(t, err)
+	require.InDelta(t, 12, got, 0, "requests nodes gave up on must not be counted as served")
+	got, err = FakeRequests(c, "/ea", "499")
+	require.NoError(t, err)
+	require.InDelta(t, 2, got, 0)
+	got, err = FakeRequests(c, "/ea", "")
+	require.NoError(t, err)
+	require.InDelta(t, 14, got, 0)
+	got, err = FakeRequests(c, "/set_latency", "200")
+	require.NoError(t, err)
+	require.Zero(t, got)
+}
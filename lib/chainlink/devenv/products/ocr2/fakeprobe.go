@@ -0,0 +1,143 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/fake"
+)
+
+// fakeURLWarnings returns human-readable problems with fake server URLs, ex.: host and docker URLs look swapped.
+// Docker URL is what nodes call from inside the network, so a loopback host there is never reachable from a container
+func fakeURLWarnings(out *fake.Output) []string {
+	if out == nil {
+		return []string{"fake server has no output, its URLs are unknown"}
+	}
+	var warns []string
+	hostURL, dockerURL := strings.TrimRight(out.BaseURLHost, "/"), strings.TrimRight(out.BaseURLDocker, "/")
+	if dockerURL == "" {
+		return append(warns, "fake server docker URL is empty, nodes can't reach it")
+	}
+	if hostURL == dockerURL {
+		warns = append(warns, fmt.Sprintf("fake server host and docker URLs are the same (%s), nodes in docker usually can't reach a host URL", dockerURL))
+	}
+	dockerLoopback, hostLoopback := isLoopbackURL(dockerURL), isLoopbackURL(hostURL)
+	switch {
+	case dockerLoopback && hostURL != "" && !hostLoopback:
+		warns = append(warns, fmt.Sprintf("fake server host URL %s and docker URL %s look swapped", hostURL, dockerURL))
+	case dockerLoopback:
+		warns = append(warns, fmt.Sprintf("fake server docker URL %s points to loopback, nodes in docker can't reach it", dockerURL))
+	}
+	return warns
+}
+
+// isLoopbackURL reports whether URL host is localhost or a loopback IP
+func isLoopbackURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	h := u.Hostname()
+	if h == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(h)
+	return ip != nil && ip.IsLoopback()
+}
+
+// probeFakeFromNode makes the node call fake juels source through a temporary bridge and a webhook job,
+// so a docker URL nodes can't reach fails before real jobs are created. EA isn't probed, it has no value until fake is seeded.
+// Bridge and job are removed afterwards
+func probeFakeFromNode(ctx context.Context, node *clclient.ChainlinkClient, f *fake.Input) error {
+	for _, w := range fakeURLWarnings(f.Out) {
+		ctxLogger(ctx).Warn().Msg(w)
+	}
+	if f.Out == nil || f.Out.BaseURLDocker == "" {
+		return errors.New("fake server has no docker URL to probe")
+	}
+	name := "fake-probe-" + uuid.NewString()
+	bridge := &clclient.BridgeTypeAttributes{
+		Name: name,
+		URL:  strings.TrimRight(f.Out.BaseURLDocker, "/") + "/juelsPerFeeCoinSource",
+	}
+	if err := node.MustCreateBridge(bridge); err != nil {
+		return fmt.Errorf("creating probe bridge to %s on node %s have failed: %w", bridge.URL, node.URL(), err)
+	}
+	defer deleteBridge(context.WithoutCancel(ctx), node, name)
+
+	externalJobID := uuid.NewString()
+	spec := RawJobSpec(fmt.Sprintf(`type = "webhook"
+schemaVersion = 1
+name = "%s"
+externalJobID = "%s"
+observationSource = """
+ea [type="bridge" name="%s" requestData="{\\"data\\":{}}"]
+"""
+`, name, externalJobID, name))
+	job, err := node.MustCreateJob(spec)
+	if err != nil {
+		return fmt.Errorf("creating probe job on node %s have failed: %w", node.URL(), err)
+	}
+	defer func() {
+		if dErr := DeleteJob(context.WithoutCancel(ctx), node, job.Data.ID); dErr != nil {
+			ctxLogger(ctx).Warn().Err(dErr).Msg("Could not delete fake probe job")
+		}
+	}()
+
+	var res struct {
+		Data struct {
+			Attributes struct {
+				FatalErrors []*string `json:"fatalErrors"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	resp, err := node.APIClient.R().
+		SetContext(ctx).
+		SetPathParam("id", externalJobID).
+		SetResult(&res).
+		Post("/v2/jobs/{id}/runs")
+	if err != nil {
+		return fmt.Errorf("running probe job on node %s have failed: %w", node.URL(), err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("node %s refused to run probe job (status %d): %s", node.URL(), resp.StatusCode(), resp.String())
+	}
+	if errs := runErrors(res.Data.Attributes.FatalErrors); len(errs) > 0 {
+		return fmt.Errorf("node %s can't reach fake server at %s, check ea fake docker URL: %s", node.URL(), bridge.URL, strings.Join(errs, "; "))
+	}
+	ctxLogger(ctx).Info().Str("Node", node.URL()).Str("URL", bridge.URL).Msg("Fake server is reachable from nodes")
+	return nil
+}
+
+// runErrors drops empty pipeline run errors, node reports a null for every task that succeeded
+func runErrors(errs []*string) []string {
+	out := make([]string, 0, len(errs))
+	for _, e := range errs {
+		if e != nil && *e != "" {
+			out = append(out, *e)
+		}
+	}
+	return out
+}
+
+// deleteBridge removes a bridge from the node, failures are only logged since the bridge is harmless
+func deleteBridge(ctx context.Context, node *clclient.ChainlinkClient, name string) {
+	resp, err := node.APIClient.R().
+		SetContext(ctx).
+		SetPathParam("name", name).
+		Delete("/v2/bridge_types/{name}")
+	if err != nil {
+		ctxLogger(ctx).Warn().Err(err).Str("Bridge", name).Msg("Could not delete bridge")
+		return
+	}
+	if resp.IsError() {
+		ctxLogger(ctx).Warn().Str("Bridge", name).Int("Status", resp.StatusCode()).Str("Response", resp.String()).Msg("Node refused to delete bridge")
+	}
+}
@@ -0,0 +1,39 @@
+package ocr2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/fake"
+)
+
+func TestFakeURLWarnings(t *testing.T) {
+	tests := []struct {
+		name string
+		out  *fake.Output
+		want []string
+	}{
+		{name: "ok", out: &fake.Output{BaseURLHost: "http://localhost:9111", BaseURLDocker: "http://fake:9111"}},
+		{name: "no output", want: []string{"fake server has no output, its URLs are unknown"}},
+		{name: "empty docker URL", out: &fake.Output{BaseURLHost: "http://localhost:9111"}, want: []string{"fake server docker URL is empty, nodes can't reach it"}},
+		{
+			name: "swapped",
+			out:  &fake.Output{BaseURLHost: "http://fake:9111", BaseURLDocker: "http://127.0.0.1:9111/"},
+			want: []string{"fake server host URL http://fake:9111 and docker URL http://127.0.0.1:9111 look swapped"},
+		},
+		{
+			name: "same loopback URL",
+			out:  &fake.Output{BaseURLHost: "http://localhost:9111", BaseURLDocker: "http://localhost:9111"},
+			want: []string{
+				"fake server host and docker URLs are the same (http://localhost:9111), nodes in docker usually can't reach a host URL",
+				"fake server docker URL http://localhost:9111 points to loopback, nodes in docker can't reach it",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, fakeURLWarnings(tc.out))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package ocr2
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+)
+
+// DefaultFeedName names the only feed if [[ocr2.feeds]] is not set
+const DefaultFeedName = "default"
+
+// Feed is an aggregator of a multi-feed setup, ex.: [[ocr2.feeds]], unset values fall back to [ocr2.ocr2]
+type Feed struct {
+	Name          string   `toml:"name"`
+	Decimals      uint8    `toml:"decimals"`
+	MinimumAnswer *big.Int `toml:"minimum_answer"`
+	MaximumAnswer *big.Int `toml:"maximum_answer"`
+	Description   string   `toml:"description"`
+}
+
+// FeedSpec is a feed with its aggregator options resolved
+type FeedSpec struct {
+	Name    string
+	Options *OCRv2OffChainOptions
+}
+
+// DeployedFeed is an aggregator deployed for a feed, Decimals are stored so answers are decoded without reading the contract
+type DeployedFeed struct {
+	Name         string `toml:"name"`
+	Address      string `toml:"address"`
+	Decimals     uint8  `toml:"decimals"`
+	ConfigDigest string `toml:"config_digest"`
+}
+
+// FeedSpecs returns options of every feed, the first feed is the primary one jobs and tests used before multi-feed setups,
+// a single feed with [ocr2.ocr2] options is returned if [[ocr2.feeds]] is not set
+func (o *OCR2) FeedSpecs() ([]FeedSpec, error) {
+	if o.OCR2 == nil {
+		return nil, errors.New("no [ocr2.ocr2] aggregator options found")
+	}
+	if len(o.Feeds) == 0 {
+		return []FeedSpec{{Name: DefaultFeedName, Options: o.OCR2.withDefaultDecimals()}}, nil
+	}
+	if err := validateFeeds(o.Feeds); err != nil {
+		return nil, err
+	}
+	specs := make([]FeedSpec, 0, len(o.Feeds))
+	for _, f := range o.Feeds {
+		specs = append(specs, FeedSpec{Name: f.Name, Options: f.options(o.OCR2)})
+	}
+	return specs, nil
+}
+
+// validateFeeds checks every feed is set and named uniquely, names identify feeds in outputs and logs
+func validateFeeds(feeds []*Feed) error {
+	seen := make(map[string]bool, len(feeds))
+	for i, f := range feeds {
+		if f == nil {
+			return fmt.Errorf("feed %d is empty", i)
+		}
+		if strings.TrimSpace(f.Name) == "" {
+			return fmt.Errorf("feed %d has no name", i)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("feed name %s is not unique", f.Name)
+		}
+		seen[f.Name] = true
+	}
+	return nil
+}
+
+// options returns a copy of base with feed values applied, access controllers and billing are shared by all feeds
+func (f *Feed) options(base *OCRv2OffChainOptions) *OCRv2OffChainOptions {
+	o := *base
+	o.Decimals = defaults.Coalesce(f.Decimals, base.Decimals, DefaultAggregatorDecimals)
+	o.Description = defaults.Coalesce(f.Description, base.Description)
+	if f.MinimumAnswer != nil {
+		o.MinimumAnswer = f.MinimumAnswer
+	}
+	if f.MaximumAnswer != nil {
+		o.MaximumAnswer = f.MaximumAnswer
+	}
+	return &o
+}
+
+// withDefaultDecimals returns a copy of options with DefaultAggregatorDecimals if decimals are not set
+func (o *OCRv2OffChainOptions) withDefaultDecimals() *OCRv2OffChainOptions {
+	c := *o
+	c.Decimals = defaults.Coalesce(o.Decimals, DefaultAggregatorDecimals)
+	return &c
+}
+
+// Feed returns deployed feed by name
+func (d *DeployedContracts) Feed(name string) (*DeployedFeed, error) {
+	if d == nil {
+		return nil, errors.New("no deployed contracts found")
+	}
+	for _, f := range d.AllFeeds() {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("feed %s is not deployed", name)
+}
+
+// AllFeeds returns every deployed feed, outputs written before feeds were recorded have only the primary aggregator,
+// it's returned with DefaultAggregatorDecimals
+func (d *DeployedContracts) AllFeeds() []*DeployedFeed {
+	if d == nil {
+		return nil
+	}
+	if len(d.Feeds) > 0 || d.OCRv2AggregatorAddr == "" {
+		return d.Feeds
+	}
+	return []*DeployedFeed{{Name: DefaultFeedName, Address: d.OCRv2AggregatorAddr, Decimals: DefaultAggregatorDecimals}}
+}
+
+// FormatAnswer decodes a raw aggregator answer with feed decimals, ex.: 150000000 with 8 decimals is "1.5"
+func FormatAnswer(answer *big.Int, decimals uint8) string {
+	if answer == nil {
+		return ""
+	}
+	s := new(big.Rat).SetFrac(answer, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)).FloatString(int(decimals))
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// AnswerFloat decodes a raw aggregator answer with feed decimals for logging only, comparisons must use raw answers
+func AnswerFloat(answer *big.Int, decimals uint8) float64 {
+	if answer == nil {
+		return 0
+	}
+	f, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(answer),
+		new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)),
+	).Float64()
+	return f
+}
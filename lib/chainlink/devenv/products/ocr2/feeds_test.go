@@ -0,0 +1,110 @@
+package ocr2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedSpecs(t *testing.T) {
+	billing := common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	base := &OCRv2OffChainOptions{
+		MinimumAnswer:           big.NewInt(1),
+		MaximumAnswer:           big.NewInt(1e18),
+		Description:             "fake-ea-price",
+		BillingAccessController: billing,
+	}
+
+	specs, err := (&OCR2{OCR2: base}).FeedSpecs()
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	require.Equal(t, DefaultFeedName, specs[0].Name)
+	require.Equal(t, uint8(DefaultAggregatorDecimals), specs[0].Options.Decimals)
+	require.Zero(t, base.Decimals, "base options must not be modified")
+
+	specs, err = (&OCR2{OCR2: base, Feeds: []*Feed{
+		{Name: "eth-usd", Decimals: 8, MinimumAnswer: big.NewInt(100), MaximumAnswer: big.NewInt(1e12), Description: "ETH / USD"},
+		{Name: "link-eth"},
+	}}).FeedSpecs()
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	require.Equal(t, "eth-usd", specs[0].Name)
+	require.Equal(t, &OCRv2OffChainOptions{
+		MinimumAnswer:           big.NewInt(100),
+		MaximumAnswer:           big.NewInt(1e12),
+		Description:             "ETH / USD",
+		BillingAccessController: billing,
+		Decimals:                8,
+	}, specs[0].Options)
+	require.Equal(t, "link-eth", specs[1].Name)
+	require.Equal(t, &OCRv2OffChainOptions{
+		MinimumAnswer:           big.NewInt(1),
+		MaximumAnswer:           big.NewInt(1e18),
+		Description:             "fake-ea-price",
+		BillingAccessController: billing,
+		Decimals:                DefaultAggregatorDecimals,
+	}, specs[1].Options)
+
+	tests := []struct {
+		name    string
+		o       *OCR2
+		wantErr string
+	}{
+		{name: "no options", o: &OCR2{}, wantErr: "no [ocr2.ocr2] aggregator options found"},
+		{name: "empty feed", o: &OCR2{OCR2: base, Feeds: []*Feed{nil}}, wantErr: "feed 0 is empty"},
+		{name: "no name", o: &OCR2{OCR2: base, Feeds: []*Feed{{Name: "a"}, {Decimals: 8}}}, wantErr: "feed 1 has no name"},
+		{name: "duplicate name", o: &OCR2{OCR2: base, Feeds: []*Feed{{Name: "a"}, {Name: "a"}}}, wantErr: "feed name a is not unique"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.o.FeedSpecs()
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestDeployedFeeds(t *testing.T) {
+	var none *DeployedContracts
+	require.Empty(t, none.AllFeeds())
+	_, err := none.Feed("eth-usd")
+	require.ErrorContains(t, err, "no deployed contracts found")
+
+	legacy := &DeployedContracts{OCRv2AggregatorAddr: "0x01"}
+	require.Equal(t, []*DeployedFeed{{Name: DefaultFeedName, Address: "0x01", Decimals: DefaultAggregatorDecimals}}, legacy.AllFeeds())
+
+	d := &DeployedContracts{
+		OCRv2AggregatorAddr: "0x01",
+		Feeds: []*DeployedFeed{
+			{Name: "eth-usd", Address: "0x01", Decimals: 8},
+			{Name: "link-eth", Address: "0x02", Decimals: 18},
+		},
+	}
+	require.Equal(t, d.Feeds, d.AllFeeds())
+	f, err := d.Feed("link-eth")
+	require.NoError(t, err)
+	require.Equal(t, "0x02", f.Address)
+	_, err = d.Feed("btc-usd")
+	require.ErrorContains(t, err, "feed btc-usd is not deployed")
+}
+
+func TestFormatAnswer(t *testing.T) {
+	tests := []struct {
+		answer   *big.Int
+		decimals uint8
+		want     string
+	}{
+		{answer: big.NewInt(150000000), decimals: 8, want: "1.5"},
+		{answer: big.NewInt(150000000), decimals: 0, want: "150000000"},
+		{answer: big.NewInt(-25), decimals: 2, want: "-0.25"},
+		{answer: big.NewInt(3e18), decimals: 18, want: "3"},
+		{answer: new(big.Int).Lsh(big.NewInt(1), 100), decimals: 18, want: "1267650600228.229401496703205376"},
+		{answer: nil, decimals: 8, want: ""},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.want, FormatAnswer(tc.answer, tc.decimals))
+	}
+	require.InDelta(t, 1.5, AnswerFloat(big.NewInt(150000000), 8), 1e-9)
+	require.InDelta(t, 3e18, AnswerFloat(big.NewInt(3e18), 0), 1e3)
+}
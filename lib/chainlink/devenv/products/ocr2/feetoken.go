@@ -0,0 +1,111 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/smartcontractkit/chainlink-evm/gethwrappers/shared/generated/burn_mint_erc677"
+	"github.com/smartcontractkit/chainlink-evm/gethwrappers/shared/generated/link_token"
+)
+
+// DefaultFeeTokenDecimals are decimals of a custom fee token if they are not set, the same as LINK has
+const DefaultFeeTokenDecimals = 18
+
+// FeeToken replaces LINK transmitters are paid in, ex.: a fee token with 6 decimals, standard LINK is deployed if not set.
+// Either Address of an existing token or Symbol of a token to deploy is set
+type FeeToken struct {
+	// Address of an existing ERC20 token, nothing is deployed and transmitters are funded by transfers from the root account,
+	// decimals and symbol are read from the token
+	Address string `toml:"address"`
+	// Name of a deployed ERC677 token, Symbol is used if it's empty
+	Name   string `toml:"name"`
+	Symbol string `toml:"symbol"`
+	// Decimals of a deployed ERC677 token, DefaultFeeTokenDecimals if not set
+	Decimals *uint8 `toml:"decimals"`
+}
+
+// Validate checks the token is either an existing one or has a symbol to be deployed with, nil token is valid
+func (t *FeeToken) Validate() error {
+	if t == nil {
+		return nil
+	}
+	if t.Address != "" {
+		if !common.IsHexAddress(t.Address) {
+			return fmt.Errorf("fee token address %q is not a hex address", t.Address)
+		}
+		if t.Name != "" || t.Symbol != "" || t.Decimals != nil {
+			return errors.New("fee token address can't be combined with name, symbol or decimals, they are read from the token")
+		}
+		return nil
+	}
+	if t.Symbol == "" {
+		return errors.New("fee token symbol is required to deploy a token, set address to use an existing one")
+	}
+	return nil
+}
+
+// feeToken is the token transmitters are paid in, LINK and ERC677 bindings implement it
+type feeToken interface {
+	Address() common.Address
+	Symbol(opts *bind.CallOpts) (string, error)
+	Decimals(opts *bind.CallOpts) (uint8, error)
+	BalanceOf(opts *bind.CallOpts, account common.Address) (*big.Int, error)
+	GrantMintRole(opts *bind.TransactOpts, minter common.Address) (*types.Transaction, error)
+	Mint(opts *bind.TransactOpts, account common.Address, amount *big.Int) (*types.Transaction, error)
+	Transfer(opts *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error)
+}
+
+// deployFeeToken deploys LINK or a custom ERC677 token, an existing token is only bound and reported as not deployed
+func deployFeeToken(ctx context.Context, c *ethclient.Client, auth *bind.TransactOpts, t *FeeToken, w WaitConfig) (feeToken, bool, error) {
+	if t != nil && t.Address != "" {
+		token, err := burn_mint_erc677.NewBurnMintERC677(common.HexToAddress(t.Address), c)
+		if err != nil {
+			return nil, false, fmt.Errorf("could not connect to fee token %s: %w", t.Address, err)
+		}
+		ctxLogger(ctx).Info().Str("Address", t.Address).Msg("Using existing fee token contract")
+		return token, false, nil
+	}
+	var (
+		addr  common.Address
+		tx    *types.Transaction
+		token feeToken
+		err   error
+	)
+	if t == nil {
+		addr, tx, token, err = link_token.DeployLinkToken(auth, c)
+	} else {
+		decimals := uint8(DefaultFeeTokenDecimals)
+		if t.Decimals != nil {
+			decimals = *t.Decimals
+		}
+		name := t.Name
+		if name == "" {
+			name = t.Symbol
+		}
+		// max supply 0 is unlimited, so the root account can mint any funding
+		addr, tx, token, err = burn_mint_erc677.DeployBurnMintERC677(auth, c, name, t.Symbol, decimals, big.NewInt(0))
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("could not create fee token contract: %w", err)
+	}
+	if _, err := WaitDeployed(ctx, c, tx, w); err != nil {
+		return nil, false, err
+	}
+	ctxLogger(ctx).Info().Str("Address", addr.Hex()).Msg("Deployed fee token contract")
+	return token, true, nil
+}
+
+// fundWithToken mints amount of a token the environment deployed for an address, or transfers it if the token is not mintable
+func fundWithToken(t feeToken, opts *bind.TransactOpts, to common.Address, amount *big.Int, mint bool) (*types.Transaction, error) {
+	if mint {
+		return t.Mint(opts, to, amount)
+	}
+	return t.Transfer(opts, to, amount)
+}
@@ -0,0 +1,33 @@
+package ocr2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeTokenValidate(t *testing.T) {
+	six := uint8(6)
+	tests := []struct {
+		name    string
+		token   *FeeToken
+		wantErr string
+	}{
+		{name: "standard LINK"},
+		{name: "deploy", token: &FeeToken{Symbol: "USDC", Decimals: &six}},
+		{name: "existing", token: &FeeToken{Address: "0x5FbDB2315678afecb367f032d93F642f64180aa3"}},
+		{name: "no symbol", token: &FeeToken{Name: "Fee Token"}, wantErr: "fee token symbol is required"},
+		{name: "bad address", token: &FeeToken{Address: "0x5FbDB"}, wantErr: "is not a hex address"},
+		{name: "existing with decimals", token: &FeeToken{Address: "0x5FbDB2315678afecb367f032d93F642f64180aa3", Decimals: &six}, wantErr: "can't be combined"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.token.Validate()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
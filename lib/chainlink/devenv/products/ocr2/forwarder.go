@@ -0,0 +1,71 @@
+package ocr2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/smartcontractkit/chainlink-evm/gethwrappers/operatorforwarder/generated/authorized_forwarder"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+)
+
+// deployForwarders deploys an authorized forwarder for every node transmitter and authorizes the node to send through it,
+// returns transmitter -> forwarder addresses
+func deployForwarders(ctx context.Context, c *ethclient.Client, auth *bind.TransactOpts, link, owner common.Address, transmitters []common.Address, w WaitConfig) (map[string]string, error) {
+	forwarders := make(map[string]string, len(transmitters))
+	for _, transmitter := range transmitters {
+		addr, tx, fwd, err := authorized_forwarder.DeployAuthorizedForwarder(auth, c, link, owner, common.Address{}, []byte{})
+		if err != nil {
+			return nil, fmt.Errorf("could not deploy forwarder for %s: %w", transmitter.Hex(), err)
+		}
+		if _, err = WaitDeployed(ctx, c, tx, w); err != nil {
+			return nil, err
+		}
+		tx, err = fwd.SetAuthorizedSenders(auth, []common.Address{transmitter})
+		if err != nil {
+			return nil, fmt.Errorf("could not authorize %s on forwarder %s: %w", transmitter.Hex(), addr.Hex(), err)
+		}
+		if _, err = WaitMined(ctx, c, tx, w); err != nil {
+			return nil, err
+		}
+		ctxLogger(ctx).Info().
+			Str("Transmitter", transmitter.Hex()).
+			Str("Forwarder", addr.Hex()).
+			Msg("Deployed authorized forwarder")
+		forwarders[transmitter.Hex()] = addr.Hex()
+	}
+	return forwarders, nil
+}
+
+// onchainTransmitter returns the address aggregator sees as a transmitter, it's the forwarder if node transmits through one
+func (d *DeployedContracts) onchainTransmitter(transmitter common.Address) common.Address {
+	if d == nil {
+		return transmitter
+	}
+	if fwd, ok := d.Forwarders[transmitter.Hex()]; ok {
+		return common.HexToAddress(fwd)
+	}
+	return transmitter
+}
+
+// trackForwarder registers forwarder on the node, so node sends OCR2 transmissions through it
+func trackForwarder(ctx context.Context, node *clclient.ChainlinkClient, chainID, forwarder string) error {
+	resp, err := node.APIClient.R().
+		SetContext(ctx).
+		SetBody(map[string]string{
+			"evmChainId": chainID,
+			"address":    forwarder,
+		}).
+		Post("/v2/nodes/evm/forwarders/track")
+	if err != nil {
+		return fmt.Errorf("tracking forwarder %s on node %s have failed: %w", forwarder, node.URL(), err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("node %s refused to track forwarder %s (status %d): %s", node.URL(), forwarder, resp.StatusCode(), resp.String())
+	}
+	return nil
+}
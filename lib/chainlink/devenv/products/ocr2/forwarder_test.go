@@ -0,0 +1,22 @@
+package ocr2
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnchainTransmitter(t *testing.T) {
+	eoa := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	fwd := common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	var none *DeployedContracts
+	require.Equal(t, eoa, none.onchainTransmitter(eoa))
+	require.Equal(t, eoa, (&DeployedContracts{}).onchainTransmitter(eoa))
+
+	d := &DeployedContracts{Forwarders: map[string]string{eoa.Hex(): fwd.Hex()}}
+	require.Equal(t, fwd, d.onchainTransmitter(eoa))
+	require.Equal(t, other, d.onchainTransmitter(other))
+}
@@ -0,0 +1,64 @@
+package ocr2
+
+import (
+	"fmt"
+	"math"
+)
+
+// NodeFunding overrides cl_nodes_funding_eth and cl_nodes_funding_link of a single node, ex.: to starve a node of ETH
+// so it can't transmit. The node stays a transmitter and payee like any other node
+type NodeFunding struct {
+	// Node is the index of the node in the first node set, bootstrap nodes of the set included, it's the same index
+	// transmitters and payees are ordered by
+	Node int `toml:"node"`
+	// ETH and Link replace global amounts for the node, a global amount is used if not set, 0 leaves the node unfunded
+	ETH  *float64 `toml:"eth"`
+	Link *float64 `toml:"link"`
+}
+
+// validateNodeFunding checks node indexes are unique and amounts are non-negative, indexes are checked against nodes in nodeFunding
+func validateNodeFunding(funding []*NodeFunding) error {
+	seen := make(map[int]bool, len(funding))
+	for i, f := range funding {
+		if f == nil {
+			return fmt.Errorf("node funding %d is empty", i)
+		}
+		if f.Node < 0 {
+			return fmt.Errorf("node funding %d has negative node index %d", i, f.Node)
+		}
+		if seen[f.Node] {
+			return fmt.Errorf("node %d funding is set more than once", f.Node)
+		}
+		seen[f.Node] = true
+		for _, a := range []struct {
+			name   string
+			amount *float64
+		}{{"eth", f.ETH}, {"link", f.Link}} {
+			if a.amount != nil && (*a.amount < 0 || math.IsNaN(*a.amount) || math.IsInf(*a.amount, 0)) {
+				return fmt.Errorf("node %d %s funding must be a non-negative number, got %v", f.Node, a.name, *a.amount)
+			}
+		}
+	}
+	return nil
+}
+
+// nodeFunding returns ETH and LINK amounts of every node by index, global amounts are used for nodes without overrides
+func (o *OCR2) nodeFunding(nodes int) (eth, link []float64, err error) {
+	eth = make([]float64, nodes)
+	link = make([]float64, nodes)
+	for i := range nodes {
+		eth[i], link[i] = o.CLNodesFundingETH, o.CLNodesFundingLink
+	}
+	for _, f := range o.NodeFunding {
+		if f.Node >= nodes {
+			return nil, nil, fmt.Errorf("node funding index %d is out of range, there are %d nodes in the node set", f.Node, nodes)
+		}
+		if f.ETH != nil {
+			eth[f.Node] = *f.ETH
+		}
+		if f.Link != nil {
+			link[f.Node] = *f.Link
+		}
+	}
+	return eth, link, nil
+}
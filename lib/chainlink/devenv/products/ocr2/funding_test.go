@@ -0,0 +1,59 @@
+package ocr2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeFunding(t *testing.T) {
+	zero, half, hundred := 0.0, 0.5, 100.0
+	o := &OCR2{
+		CLNodesFundingETH:  10,
+		CLNodesFundingLink: 50,
+		NodeFunding: []*NodeFunding{
+			{Node: 1, ETH: &zero},
+			{Node: 3, ETH: &half, Link: &hundred},
+		},
+	}
+	eth, link, err := o.nodeFunding(4)
+	require.NoError(t, err)
+	require.Equal(t, []float64{10, 0, 10, 0.5}, eth)
+	require.Equal(t, []float64{50, 50, 50, 100}, link)
+
+	_, _, err = o.nodeFunding(3)
+	require.ErrorContains(t, err, "node funding index 3 is out of range, there are 3 nodes")
+
+	eth, link, err = (&OCR2{CLNodesFundingETH: 1, CLNodesFundingLink: 2}).nodeFunding(2)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 1}, eth)
+	require.Equal(t, []float64{2, 2}, link)
+}
+
+func TestValidateNodeFunding(t *testing.T) {
+	one, negative, nan := 1.0, -1.0, math.NaN()
+	tests := []struct {
+		name    string
+		funding []*NodeFunding
+		wantErr string
+	}{
+		{name: "no overrides"},
+		{name: "valid", funding: []*NodeFunding{{Node: 0, ETH: &one}, {Node: 2, Link: &one}}},
+		{name: "empty", funding: []*NodeFunding{nil}, wantErr: "node funding 0 is empty"},
+		{name: "negative index", funding: []*NodeFunding{{Node: -1}}, wantErr: "negative node index -1"},
+		{name: "duplicate", funding: []*NodeFunding{{Node: 1}, {Node: 1}}, wantErr: "node 1 funding is set more than once"},
+		{name: "negative amount", funding: []*NodeFunding{{Node: 1, ETH: &negative}}, wantErr: "node 1 eth funding must be a non-negative number"},
+		{name: "nan amount", funding: []*NodeFunding{{Node: 1, Link: &nan}}, wantErr: "node 1 link funding must be a non-negative number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNodeFunding(tt.funding)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// BaseFeeSetter sets the base fee of the next block, ex.: *rpc.RPCClient of Anvil
+type BaseFeeSetter interface {
+	AnvilSetNextBlockBaseFeePerGas(fee *big.Int) error
+	PrintBlockBaseFee() error
+}
+
+// GasController drives chain base fee through gas spike phases: ramp, hold and release,
+// so gas scenarios are composed from steps instead of hand-written loops
+type GasController struct {
+	chain BaseFeeSetter
+	// OnBaseFee is called with every base fee set, ex.: to push it to the fake gas_linked juels source
+	OnBaseFee func(fee *big.Int) error
+	// Miner mines exactly one block per step instead of waiting for the interval, it must run with manual mining,
+	// see AnvilMiner.ManualMining. Steps are timed by the interval if it's nil
+	Miner BlockMiner
+}
+
+// BlockMiner mines a single block on demand, ex.: *AnvilMiner
+type BlockMiner interface {
+	Mine(ctx context.Context) error
+}
+
+// NewGasController creates a gas controller for the chain
+func NewGasController(chain BaseFeeSetter) *GasController {
+	return &GasController{chain: chain}
+}
+
+// RampBaseFee sets base fee to start and raises it by bump every interval, steps fees are set in total
+func (g *GasController) RampBaseFee(ctx context.Context, start, bump *big.Int, steps int, interval time.Duration) error {
+	if start == nil || bump == nil {
+		return errors.New("ramp start and bump base fees must be set")
+	}
+	fee := new(big.Int).Set(start)
+	for range steps {
+		if err := g.setBaseFee(fee); err != nil {
+			return err
+		}
+		if err := g.step(ctx, interval); err != nil {
+			return err
+		}
+		fee.Add(fee, bump)
+	}
+	return nil
+}
+
+// HoldBaseFee keeps setting the same base fee every interval for steps intervals
+func (g *GasController) HoldBaseFee(ctx context.Context, fee *big.Int, steps int, interval time.Duration) error {
+	if fee == nil {
+		return errors.New("hold base fee must be set")
+	}
+	for range steps {
+		if err := g.setBaseFee(fee); err != nil {
+			return err
+		}
+		if err := g.step(ctx, interval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseBaseFee stops setting base fee for steps intervals or blocks, chain lowers it back on its own
+func (g *GasController) ReleaseBaseFee(ctx context.Context, steps int, interval time.Duration) error {
+	for range steps {
+		if err := g.chain.PrintBlockBaseFee(); err != nil {
+			return fmt.Errorf("could not read block base fee: %w", err)
+		}
+		if err := g.step(ctx, interval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RampPeak returns the fee RampBaseFee would set after steps, it's where a spike is usually held
+func RampPeak(start, bump *big.Int, steps int) *big.Int {
+	return new(big.Int).Add(start, new(big.Int).Mul(bump, big.NewInt(int64(steps))))
+}
+
+func (g *GasController) setBaseFee(fee *big.Int) error {
+	if err := g.chain.PrintBlockBaseFee(); err != nil {
+		return fmt.Errorf("could not read block base fee: %w", err)
+	}
+	L.Info().Str("BaseFee", fee.String()).Msg("Setting next block base fee")
+	if err := g.chain.AnvilSetNextBlockBaseFeePerGas(fee); err != nil {
+		return fmt.Errorf("could not set next block base fee to %s: %w", fee, err)
+	}
+	if g.OnBaseFee != nil {
+		if err := g.OnBaseFee(new(big.Int).Set(fee)); err != nil {
+			return fmt.Errorf("base fee %s hook failed: %w", fee, err)
+		}
+	}
+	return nil
+}
+
+// step moves to the next block, it's mined right away with Miner, otherwise the interval is awaited
+func (g *GasController) step(ctx context.Context, interval time.Duration) error {
+	if g.Miner != nil {
+		return g.Miner.Mine(ctx)
+	}
+	return sleepCtx(ctx, interval)
+}
+
+// sleepCtx waits for d or until ctx is done
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
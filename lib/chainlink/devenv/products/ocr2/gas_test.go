@@ -0,0 +1,79 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingChain records base fees set on it
+type recordingChain struct {
+	fees  []int64
+	reads int
+	err   error
+}
+
+func (c *recordingChain) AnvilSetNextBlockBaseFeePerGas(fee *big.Int) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.fees = append(c.fees, fee.Int64())
+	return nil
+}
+
+func (c *recordingChain) PrintBlockBaseFee() error {
+	c.reads++
+	return nil
+}
+
+func TestGasController(t *testing.T) {
+	ctx := context.Background()
+	chain := &recordingChain{}
+	pushed := make([]int64, 0)
+	g := NewGasController(chain)
+	g.OnBaseFee = func(fee *big.Int) error {
+		pushed = append(pushed, fee.Int64())
+		return nil
+	}
+	start, bump := big.NewInt(100), big.NewInt(50)
+
+	require.NoError(t, g.RampBaseFee(ctx, start, bump, 3, 0))
+	require.NoError(t, g.HoldBaseFee(ctx, RampPeak(start, bump, 3), 2, 0))
+	require.NoError(t, g.ReleaseBaseFee(ctx, 2, 0))
+	require.Equal(t, []int64{100, 150, 200, 250, 250}, chain.fees)
+	require.Equal(t, chain.fees, pushed)
+	require.Equal(t, 7, chain.reads)
+	require.Equal(t, int64(100), start.Int64(), "ramp must not change its start fee")
+
+	chain.err = errors.New("method not found")
+	require.ErrorContains(t, g.HoldBaseFee(ctx, start, 1, 0), "could not set next block base fee to 100: method not found")
+	require.ErrorContains(t, g.RampBaseFee(ctx, nil, bump, 1, 0), "must be set")
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	chain.err = nil
+	require.ErrorIs(t, g.ReleaseBaseFee(cancelled, 1, time.Hour), context.Canceled)
+}
+
+type countingMiner int
+
+func (m *countingMiner) Mine(context.Context) error {
+	*m++
+	return nil
+}
+
+func TestGasControllerManualMining(t *testing.T) {
+	chain := &recordingChain{}
+	miner := new(countingMiner)
+	g := NewGasController(chain)
+	g.Miner = miner
+	// a block is mined per step, so a long interval is never awaited
+	require.NoError(t, g.RampBaseFee(context.Background(), big.NewInt(1), big.NewInt(1), 2, time.Hour))
+	require.NoError(t, g.ReleaseBaseFee(context.Background(), 1, time.Hour))
+	require.Equal(t, []int64{1, 2}, chain.fees)
+	require.Equal(t, countingMiner(3), *miner)
+}
@@ -0,0 +1,92 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReceiptReader reads transaction receipts, ex.: *ethclient.Client
+type ReceiptReader interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// GasStats accumulates gas used by transmissions of a run
+type GasStats struct {
+	Transmissions int    `json:"transmissions"`
+	Total         uint64 `json:"total"`
+	Max           uint64 `json:"max"`
+}
+
+// Add records gas used by a single transmission
+func (s *GasStats) Add(gasUsed uint64) {
+	s.Transmissions++
+	s.Total += gasUsed
+	s.Max = max(s.Max, gasUsed)
+}
+
+// Merge adds transmissions of other stats, ex.: of every load test case to stats of the whole run
+func (s *GasStats) Merge(o GasStats) {
+	s.Transmissions += o.Transmissions
+	s.Total += o.Total
+	s.Max = max(s.Max, o.Max)
+}
+
+// Average returns average gas used by a transmission, 0 if nothing was transmitted
+func (s GasStats) Average() uint64 {
+	if s.Transmissions == 0 {
+		return 0
+	}
+	return s.Total / uint64(s.Transmissions)
+}
+
+// GasBudget bounds gas used by OCR2 transmissions, ex.: [ocr2.gas_budget], it catches regressions in the transmission path
+type GasBudget struct {
+	// MaxAverage is the highest acceptable average gas used by a transmission, 0 disables the check
+	MaxAverage uint64 `toml:"max_average"`
+	// Max is the highest acceptable gas used by a single transmission, 0 disables the check
+	Max uint64 `toml:"max"`
+}
+
+// Validate checks the average budget fits into the single transmission budget, nil budget is valid
+func (b *GasBudget) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if b.Max > 0 && b.MaxAverage > b.Max {
+		return fmt.Errorf("gas budget max_average %d must not be greater than max %d", b.MaxAverage, b.Max)
+	}
+	return nil
+}
+
+// Check returns an error if average or max gas used by transmissions is over the budget or no transmissions were measured,
+// nil or disabled budget passes
+func (b *GasBudget) Check(s GasStats) error {
+	if b == nil || (b.MaxAverage == 0 && b.Max == 0) {
+		return nil
+	}
+	if s.Transmissions == 0 {
+		return errors.New("no transmissions were measured, gas budget can't be checked")
+	}
+	if b.MaxAverage > 0 && s.Average() > b.MaxAverage {
+		return fmt.Errorf("average transmission gas %d of %d transmissions is over the budget of %d", s.Average(), s.Transmissions, b.MaxAverage)
+	}
+	if b.Max > 0 && s.Max > b.Max {
+		return fmt.Errorf("max transmission gas %d is over the budget of %d", s.Max, b.Max)
+	}
+	return nil
+}
+
+// TransmissionGas returns gas statistics of successful transmissions of every report
+func TransmissionGas(rounds []RoundTransmissions) GasStats {
+	var stats GasStats
+	for _, r := range rounds {
+		for _, g := range r.GasUsed {
+			stats.Add(g)
+		}
+	}
+	return stats
+}
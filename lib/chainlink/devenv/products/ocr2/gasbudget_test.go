@@ -0,0 +1,56 @@
+package ocr2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasStatsMerge(t *testing.T) {
+	stats := TransmissionGas([]RoundTransmissions{
+		{GasUsed: []uint64{100_000}},
+		{GasUsed: []uint64{120_000, 80_000}},
+		{Transmissions: 1, Reverted: 1},
+	})
+	require.Equal(t, GasStats{Transmissions: 3, Total: 300_000, Max: 120_000}, stats)
+	require.Equal(t, uint64(100_000), stats.Average())
+
+	run := GasStats{Transmissions: 1, Total: 150_000, Max: 150_000}
+	run.Merge(stats)
+	require.Equal(t, GasStats{Transmissions: 4, Total: 450_000, Max: 150_000}, run)
+}
+
+func TestGasBudget(t *testing.T) {
+	stats := GasStats{Transmissions: 4, Total: 400_000, Max: 130_000}
+	tests := []struct {
+		name    string
+		budget  *GasBudget
+		stats   *GasStats
+		wantErr string
+	}{
+		{name: "no budget", budget: nil},
+		{name: "disabled", budget: &GasBudget{}},
+		{name: "within", budget: &GasBudget{MaxAverage: 100_000, Max: 130_000}},
+		{name: "average over", budget: &GasBudget{MaxAverage: 99_999}, wantErr: "average transmission gas 100000 of 4 transmissions is over the budget of 99999"},
+		{name: "max over", budget: &GasBudget{Max: 120_000}, wantErr: "max transmission gas 130000 is over the budget of 120000"},
+		{name: "no transmissions", budget: &GasBudget{Max: 130_000}, stats: &GasStats{}, wantErr: "no transmissions were measured"},
+		{name: "no transmissions, disabled", budget: &GasBudget{}, stats: &GasStats{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := stats
+			if tt.stats != nil {
+				s = *tt.stats
+			}
+			err := tt.budget.Check(s)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+	require.Zero(t, GasStats{}.Average())
+	require.NoError(t, (*GasBudget)(nil).Validate())
+	require.ErrorContains(t, (&GasBudget{MaxAverage: 2, Max: 1}).Validate(), "max_average 2 must not be greater than max 1")
+}
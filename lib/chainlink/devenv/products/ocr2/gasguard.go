@@ -0,0 +1,145 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+const (
+	// GasGuardWait makes guarded transactions wait until base fee subsides, it's the default policy
+	GasGuardWait = "wait"
+	// GasGuardAbort fails guarded transactions at once if base fee is too high
+	GasGuardAbort = "abort"
+	// DefaultGasGuardBlocks is how many blocks before the latest one the median base fee is computed over
+	DefaultGasGuardBlocks = 10
+)
+
+// ErrBaseFeeSpike is returned when base fee is over gas guard limits
+var ErrBaseFeeSpike = errors.New("base fee spike")
+
+// DefaultGasGuardWait bounds waiting for base fee to subside, a self-induced spike is released within a minute
+var DefaultGasGuardWait = WaitConfig{PollIntervalMs: 2000, TimeoutSec: 120}
+
+// GasGuard holds off deploy, set config and fund transactions while base fee is spiking,
+// so they are not submitted at a multiplied fee cap of a spike, ex.: a deploy coinciding with a gas spike scenario
+type GasGuard struct {
+	// MaxBaseFeeGwei is the highest acceptable base fee, 0 disables the limit
+	MaxBaseFeeGwei uint64 `toml:"max_base_fee_gwei"`
+	// MaxMedianMultiple is the highest acceptable ratio of the latest base fee to the median of recent blocks, 0 disables the limit
+	MaxMedianMultiple float64 `toml:"max_median_multiple"`
+	// Blocks is how many recent blocks the median is computed over, DefaultGasGuardBlocks if 0
+	Blocks int `toml:"blocks"`
+	// Policy is "wait" to wait until base fee subsides or "abort" to fail at once, "wait" if unset
+	Policy string `toml:"policy"`
+	// Wait bounds waiting with the "wait" policy, DefaultGasGuardWait is used if it's not set
+	Wait *WaitConfig `toml:"wait"`
+}
+
+// Validate checks policy is known and limits are not negative, nil guard is valid
+func (g *GasGuard) Validate() error {
+	if g == nil {
+		return nil
+	}
+	if g.Policy != "" && !slices.Contains([]string{GasGuardWait, GasGuardAbort}, g.Policy) {
+		return fmt.Errorf("gas guard policy must be %s or %s, got %s", GasGuardWait, GasGuardAbort, g.Policy)
+	}
+	if g.MaxMedianMultiple < 0 {
+		return fmt.Errorf("gas guard max_median_multiple must not be negative, got %f", g.MaxMedianMultiple)
+	}
+	if g.MaxMedianMultiple > 0 && g.MaxMedianMultiple < 1 {
+		return fmt.Errorf("gas guard max_median_multiple must be at least 1, got %f, base fee would never be accepted", g.MaxMedianMultiple)
+	}
+	if g.Blocks < 0 {
+		return fmt.Errorf("gas guard blocks must not be negative, got %d", g.Blocks)
+	}
+	if g.Wait != nil {
+		return g.Wait.Validate()
+	}
+	return nil
+}
+
+// Await returns once base fee is within limits, with the "abort" policy it fails at once with ErrBaseFeeSpike,
+// with the "wait" policy it polls until base fee subsides and fails with ErrWaitExhausted wrapping the last spike. Nil guard passes
+func (g *GasGuard) Await(ctx context.Context, c HeaderReader) error {
+	if g == nil || (g.MaxBaseFeeGwei == 0 && g.MaxMedianMultiple == 0) {
+		return nil
+	}
+	if g.Policy == GasGuardAbort {
+		return g.check(ctx, c)
+	}
+	err := g.Wait.Or(DefaultGasGuardWait).Poll(ctx, func(ctx context.Context) (bool, error) {
+		if err := g.check(ctx, c); err != nil {
+			if errors.Is(err, ErrBaseFeeSpike) {
+				ctxLogger(ctx).Warn().Err(err).Msg("Waiting for base fee to subside before sending transactions")
+			}
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("base fee didn't subside: %w", err)
+	}
+	return nil
+}
+
+// check returns ErrBaseFeeSpike if base fee of the latest block is over the limits
+func (g *GasGuard) check(ctx context.Context, c HeaderReader) error {
+	h, err := c.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not read latest block header: %w", err)
+	}
+	if h.BaseFee == nil {
+		return fmt.Errorf("block %s has no base fee, chain doesn't support EIP-1559", h.Number)
+	}
+	if g.MaxBaseFeeGwei > 0 {
+		limit := new(big.Int).Mul(new(big.Int).SetUint64(g.MaxBaseFeeGwei), big.NewInt(1e9))
+		if h.BaseFee.Cmp(limit) > 0 {
+			return fmt.Errorf("%w: block %s base fee %s wei is over max_base_fee_gwei %d", ErrBaseFeeSpike, h.Number, h.BaseFee, g.MaxBaseFeeGwei)
+		}
+	}
+	if g.MaxMedianMultiple > 0 {
+		median, err := medianBaseFee(ctx, c, h.Number, g.blocks())
+		if err != nil {
+			return err
+		}
+		limit, _ := new(big.Float).Mul(new(big.Float).SetInt(median), big.NewFloat(g.MaxMedianMultiple)).Int(nil)
+		if median.Sign() > 0 && h.BaseFee.Cmp(limit) > 0 {
+			return fmt.Errorf("%w: block %s base fee %s wei is over %.2fx the median %s wei of %d previous blocks",
+				ErrBaseFeeSpike, h.Number, h.BaseFee, g.MaxMedianMultiple, median, g.blocks())
+		}
+	}
+	return nil
+}
+
+func (g *GasGuard) blocks() int {
+	if g.Blocks == 0 {
+		return DefaultGasGuardBlocks
+	}
+	return g.Blocks
+}
+
+// medianBaseFee returns the median base fee of up to n blocks before head, 0 if head is the first block
+func medianBaseFee(ctx context.Context, c HeaderReader, head *big.Int, n int) (*big.Int, error) {
+	fees := make([]*big.Int, 0, n)
+	for i := 1; i <= n; i++ {
+		number := new(big.Int).Sub(head, big.NewInt(int64(i)))
+		if number.Sign() < 0 {
+			break
+		}
+		h, err := c.HeaderByNumber(ctx, number)
+		if err != nil {
+			return nil, fmt.Errorf("could not read block %s header: %w", number, err)
+		}
+		if h.BaseFee != nil {
+			fees = append(fees, h.BaseFee)
+		}
+	}
+	if len(fees) == 0 {
+		return new(big.Int), nil
+	}
+	slices.SortFunc(fees, func(a, b *big.Int) int { return a.Cmp(b) })
+	return fees[len(fees)/2], nil
+}
@@ -0,0 +1,88 @@
+package ocr2
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// feeChain returns headers with base fees in wei by block number, every latest header read moves latest to the next block
+// until the last one, so a spike can be released while a guard waits
+type feeChain struct {
+	fees   []int64
+	latest int
+}
+
+func (c *feeChain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	n := c.latest
+	switch {
+	case number != nil:
+		n = int(number.Int64())
+	case c.latest < len(c.fees)-1:
+		c.latest++
+	}
+	if n >= len(c.fees) {
+		return nil, fmt.Errorf("block %d not found", n)
+	}
+	return &types.Header{Number: big.NewInt(int64(n)), BaseFee: big.NewInt(c.fees[n])}, nil
+}
+
+func TestGasGuardCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		guard *GasGuard
+		fees  []int64
+		err   string
+	}{
+		{name: "under max base fee", guard: &GasGuard{MaxBaseFeeGwei: 2}, fees: []int64{2e9}},
+		{name: "over max base fee", guard: &GasGuard{MaxBaseFeeGwei: 2}, fees: []int64{3e9}, err: "base fee 3000000000 wei is over max_base_fee_gwei 2"},
+		{name: "under median multiple", guard: &GasGuard{MaxMedianMultiple: 3}, fees: []int64{1e9, 1e9, 5e9, 3e9}},
+		{name: "over median multiple", guard: &GasGuard{MaxMedianMultiple: 3}, fees: []int64{1e9, 1e9, 2e9, 4e9}, err: "over 3.00x the median 1000000000 wei"},
+		{name: "median of recent blocks only", guard: &GasGuard{MaxMedianMultiple: 3, Blocks: 2}, fees: []int64{1e9, 5e9, 5e9, 6e9}},
+		{name: "first block", guard: &GasGuard{MaxMedianMultiple: 3}, fees: []int64{9e9}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &feeChain{fees: tc.fees, latest: len(tc.fees) - 1}
+			err := tc.guard.check(context.Background(), c)
+			if tc.err != "" {
+				require.ErrorIs(t, err, ErrBaseFeeSpike)
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGasGuardAwait(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, (*GasGuard)(nil).Await(ctx, nil))
+	require.NoError(t, (&GasGuard{}).Await(ctx, nil))
+
+	// the spike is released on the next blocks
+	c := &feeChain{fees: []int64{1e9, 1e9, 1e9, 9e9, 9e9, 1e9}, latest: 3}
+	err := (&GasGuard{MaxBaseFeeGwei: 2, Policy: GasGuardAbort}).Await(ctx, c)
+	require.ErrorIs(t, err, ErrBaseFeeSpike)
+
+	c.latest = 3
+	require.NoError(t, (&GasGuard{MaxBaseFeeGwei: 2, Wait: &WaitConfig{PollIntervalMs: 1, MaxAttempts: 5}}).Await(ctx, c))
+	require.Equal(t, 5, c.latest)
+
+	c = &feeChain{fees: []int64{1e9, 9e9}, latest: 1}
+	err = (&GasGuard{MaxBaseFeeGwei: 2, Wait: &WaitConfig{PollIntervalMs: 1, MaxAttempts: 3}}).Await(ctx, c)
+	require.ErrorIs(t, err, ErrWaitExhausted)
+	require.ErrorIs(t, err, ErrBaseFeeSpike)
+}
+
+func TestGasGuardValidate(t *testing.T) {
+	require.NoError(t, (*GasGuard)(nil).Validate())
+	require.NoError(t, (&GasGuard{MaxBaseFeeGwei: 100, MaxMedianMultiple: 3, Policy: GasGuardWait}).Validate())
+	require.ErrorContains(t, (&GasGuard{Policy: "skip"}).Validate(), "policy must be wait or abort")
+	require.ErrorContains(t, (&GasGuard{MaxMedianMultiple: 0.5}).Validate(), "must be at least 1")
+	require.ErrorContains(t, (&GasGuard{Blocks: -1}).Validate(), "blocks must not be negative")
+}
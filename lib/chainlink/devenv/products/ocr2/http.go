@@ -0,0 +1,48 @@
+package ocr2
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// DefaultHTTPRequestTimeout is used for HTTP requests if a service doesn't set its own timeout
+	DefaultHTTPRequestTimeout = 10 * time.Second
+	// DefaultHTTPRetryCount is how many times failed HTTP requests are retried if a service doesn't set its own count
+	DefaultHTTPRetryCount = 3
+	// httpRetryWait and httpRetryMaxWait bound exponential backoff between retries
+	httpRetryWait    = 200 * time.Millisecond
+	httpRetryMaxWait = 2 * time.Second
+)
+
+// newRestyClient creates a resty client every HTTP call of the package goes through,
+// transport errors, 429 and 5xx are retried with backoff, any non-2xx response is returned as an error
+func newRestyClient(baseURL string) *resty.Client {
+	return resty.New().
+		SetBaseURL(baseURL).
+		SetTimeout(DefaultHTTPRequestTimeout).
+		SetRetryCount(DefaultHTTPRetryCount).
+		SetRetryWaitTime(httpRetryWait).
+		SetRetryMaxWaitTime(httpRetryMaxWait).
+		AddRetryCondition(retryable).
+		OnAfterResponse(statusError)
+}
+
+// retryable retries transport errors and responses a service may recover from, other 4xx are final
+func retryable(resp *resty.Response, err error) bool {
+	if resp == nil || resp.RawResponse == nil {
+		return err != nil
+	}
+	return resp.StatusCode() == http.StatusTooManyRequests || resp.StatusCode() >= http.StatusInternalServerError
+}
+
+// statusError turns non-2xx responses into errors so callers can't ignore them
+func statusError(_ *resty.Client, resp *resty.Response) error {
+	if resp.IsSuccess() {
+		return nil
+	}
+	return fmt.Errorf("%s %s returned status %d: %s", resp.Request.Method, resp.Request.URL, resp.StatusCode(), resp.String())
+}
@@ -0,0 +1,56 @@
+package ocr2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRestyClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		status    int
+		wantCalls int32
+		wantErr   string
+	}{
+		{name: "ok", wantCalls: 1},
+		{name: "recovers after 5xx", failures: 2, status: http.StatusServiceUnavailable, wantCalls: 3},
+		{name: "recovers after 429", failures: 1, status: http.StatusTooManyRequests, wantCalls: 2},
+		{name: "5xx exhausts retries", failures: 10, status: http.StatusInternalServerError, wantCalls: DefaultHTTPRetryCount + 1, wantErr: "returned status 500: boom"},
+		{name: "4xx is not retried", failures: 10, status: http.StatusBadRequest, wantCalls: 1, wantErr: "returned status 400: boom"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if int(calls.Add(1)) <= tc.failures {
+					w.WriteHeader(tc.status)
+					_, _ = w.Write([]byte("boom"))
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			_, err := newRestyClient(srv.URL).SetRetryWaitTime(0).SetRetryMaxWaitTime(0).R().Post("/trigger_deviation")
+			require.Equal(t, tc.wantCalls, calls.Load())
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEAFakeRetryCount(t *testing.T) {
+	var e *EAFake
+	require.Equal(t, DefaultHTTPRetryCount, e.RetryCount())
+	require.Equal(t, DefaultHTTPRetryCount, (&EAFake{}).RetryCount())
+	require.Equal(t, 5, (&EAFake{Retries: 5}).RetryCount())
+	require.Equal(t, 0, (&EAFake{Retries: -1}).RetryCount())
+}
@@ -0,0 +1,322 @@
+package ocr2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+
+	"github.com/smartcontractkit/chainlink/devenv/products"
+
+	csav1 "github.com/smartcontractkit/chainlink-protos/job-distributor/v1/csa"
+	jobv1 "github.com/smartcontractkit/chainlink-protos/job-distributor/v1/job"
+	nodev1 "github.com/smartcontractkit/chainlink-protos/job-distributor/v1/node"
+)
+
+// DefaultJDProposalWait is how long a node connection to JD and a proposed job on the node are awaited,
+// jobs.proposal_wait overrides it
+var DefaultJDProposalWait = WaitConfig{PollIntervalMs: 1000, TimeoutSec: 60}
+
+// UseJobDistributor sets Job Distributor OCR2 jobs are proposed through if jobs.via_jd is set,
+// wsrpcURL is JD WSRPC URL reachable from node containers
+func (m *Configurator) UseJobDistributor(jd products.JobDistributor, wsrpcURL string) {
+	m.jd = jd
+	m.jdWSRPC = wsrpcURL
+}
+
+// jobCreator creates a rendered job on a node, either directly or through Job Distributor
+type jobCreator func(ctx context.Context, node *clclient.ChainlinkClient, spec *TaskJobSpec) error
+
+// createJobOnNode creates a job through node API
+func createJobOnNode(_ context.Context, node *clclient.ChainlinkClient, spec *TaskJobSpec) error {
+	_, err := node.MustCreateJob(spec)
+	return err
+}
+
+// jobCreator returns how OCR2 jobs are created, by default they are created on nodes directly
+func (m *Configurator) jobCreator(ctx context.Context) (jobCreator, error) {
+	if !m.OCR2.Jobs.viaJD() {
+		return createJobOnNode, nil
+	}
+	if m.jd == nil {
+		return nil, errors.New("jobs.via_jd is set but no job distributor is available, configure [jd]")
+	}
+	d, err := newJDJobs(ctx, m.jd, m.jdWSRPC, m.OCR2.Jobs.proposalWait())
+	if err != nil {
+		return nil, err
+	}
+	return d.create, nil
+}
+
+// jdJobs proposes jobs through Job Distributor and approves them on nodes, nodes are registered in JD on first use unless JD already knows them
+type jdJobs struct {
+	jd      products.JobDistributor
+	wsrpc   string
+	csaKey  string
+	w       WaitConfig
+	mu      sync.Mutex
+	nodeIDs map[string]string
+}
+
+func newJDJobs(ctx context.Context, jd products.JobDistributor, wsrpc string, w WaitConfig) (*jdJobs, error) {
+	keypairs, err := jd.ListKeypairs(ctx, &csav1.ListKeypairsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("could not read job distributor CSA keys: %w", err)
+	}
+	if len(keypairs.GetKeypairs()) == 0 {
+		return nil, errors.New("job distributor has no CSA keys")
+	}
+	return &jdJobs{
+		jd:      jd,
+		wsrpc:   wsrpc,
+		csaKey:  keypairs.GetKeypairs()[0].GetPublicKey(),
+		w:       w,
+		nodeIDs: make(map[string]string),
+	}, nil
+}
+
+// create proposes the spec to the node through JD, waits until the node receives the proposal and approves it
+func (d *jdJobs) create(ctx context.Context, node *clclient.ChainlinkClient, spec *TaskJobSpec) error {
+	nodeID, err := d.nodeID(ctx, node)
+	if err != nil {
+		return err
+	}
+	definition, err := spec.String()
+	if err != nil {
+		return fmt.Errorf("could not render job spec: %w", err)
+	}
+	res, err := d.jd.ProposeJob(ctx, &jobv1.ProposeJobRequest{NodeId: nodeID, Spec: definition})
+	if err != nil {
+		return fmt.Errorf("could not propose job %s to node %s: %w", spec.Name, node.URL(), err)
+	}
+	var specID string
+	err = d.w.Poll(ctx, func(ctx context.Context) (bool, error) {
+		specID, err = pendingProposalSpec(ctx, node, definition)
+		return specID != "", err
+	})
+	if err != nil {
+		return fmt.Errorf("job %s proposal %s is not received by node %s: %w", spec.Name, res.GetProposal().GetId(), node.URL(), err)
+	}
+	if err := approveProposalSpec(ctx, node, specID); err != nil {
+		return err
+	}
+	ctxLogger(ctx).Info().
+		Str("Node", node.URL()).
+		Str("JDNodeID", nodeID).
+		Str("ProposalID", res.GetProposal().GetId()).
+		Str("Job", spec.Name).
+		Msg("Job proposed through JD and approved")
+	return nil
+}
+
+// nodeID returns JD node ID of the node, node is registered in JD and connected to it as a feeds manager once
+func (d *jdJobs) nodeID(ctx context.Context, node *clclient.ChainlinkClient) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if id, ok := d.nodeIDs[node.URL()]; ok {
+		return id, nil
+	}
+	csaKey, err := nodeCSAKey(ctx, node)
+	if err != nil {
+		return "", err
+	}
+	id, err := d.registerNode(ctx, node, csaKey)
+	if err != nil {
+		return "", err
+	}
+	if err := createFeedsManager(ctx, node, d.wsrpc, d.csaKey); err != nil {
+		return "", err
+	}
+	// proposals sent before the node connects to JD are never delivered
+	err = d.w.Poll(ctx, func(ctx context.Context) (bool, error) {
+		return feedsManagerConnected(ctx, node, d.csaKey)
+	})
+	if err != nil {
+		return "", fmt.Errorf("node %s is not connected to job distributor: %w", node.URL(), err)
+	}
+	d.nodeIDs[node.URL()] = id
+	return id, nil
+}
+
+// registerNode returns JD node ID of the node CSA key, the node is registered only if JD doesn't know it yet,
+// ex.: JD or nodes are reused from a previous run
+func (d *jdJobs) registerNode(ctx context.Context, node *clclient.ChainlinkClient, csaKey string) (string, error) {
+	existing, err := d.jd.ListNodes(ctx, &nodev1.ListNodesRequest{Filter: &nodev1.ListNodesRequest_Filter{PublicKeys: []string{csaKey}}})
+	if err != nil {
+		return "", fmt.Errorf("could not list job distributor nodes: %w", err)
+	}
+	for _, n := range existing.GetNodes() {
+		if n.GetPublicKey() == csaKey {
+			return n.GetId(), nil
+		}
+	}
+	res, err := d.jd.RegisterNode(ctx, &nodev1.RegisterNodeRequest{PublicKey: csaKey, Name: node.URL()})
+	if err != nil {
+		return "", fmt.Errorf("could not register node %s in job distributor: %w", node.URL(), err)
+	}
+	return res.GetNode().GetId(), nil
+}
+
+// nodeGQL runs a GraphQL request on the node, transport, status and GraphQL errors are returned as errors
+func nodeGQL(ctx context.Context, node *clclient.ChainlinkClient, query string, vars map[string]any, out any) error {
+	var res struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	resp, err := node.APIClient.R().
+		SetContext(ctx).
+		SetBody(map[string]any{"query": query, "variables": vars}).
+		SetResult(&res).
+		Post("/query")
+	if err != nil {
+		return fmt.Errorf("GraphQL request to node %s have failed: %w", node.URL(), err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("node %s refused GraphQL request (status %d): %s", node.URL(), resp.StatusCode(), resp.String())
+	}
+	if len(res.Errors) > 0 {
+		msgs := make([]string, 0, len(res.Errors))
+		for _, e := range res.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return fmt.Errorf("node %s returned GraphQL errors: %s", node.URL(), strings.Join(msgs, "; "))
+	}
+	if err := json.Unmarshal(res.Data, out); err != nil {
+		return fmt.Errorf("could not decode GraphQL response of node %s: %w", node.URL(), err)
+	}
+	return nil
+}
+
+// nodeCSAKey returns node CSA public key in the format JD registers nodes with
+func nodeCSAKey(ctx context.Context, node *clclient.ChainlinkClient) (string, error) {
+	var res struct {
+		CSAKeys struct {
+			Results []struct {
+				PublicKey string `json:"publicKey"`
+			} `json:"results"`
+		} `json:"csaKeys"`
+	}
+	if err := nodeGQL(ctx, node, `query { csaKeys { results { publicKey } } }`, nil, &res); err != nil {
+		return "", err
+	}
+	if len(res.CSAKeys.Results) == 0 {
+		return "", fmt.Errorf("node %s has no CSA keys", node.URL())
+	}
+	return strings.TrimPrefix(res.CSAKeys.Results[0].PublicKey, "csa_"), nil
+}
+
+// createFeedsManager connects the node to JD, it's skipped if the node already has JD as a feeds manager
+func createFeedsManager(ctx context.Context, node *clclient.ChainlinkClient, uri, publicKey string) error {
+	var managers struct {
+		FeedsManagers struct {
+			Results []struct {
+				PublicKey string `json:"publicKey"`
+			} `json:"results"`
+		} `json:"feedsManagers"`
+	}
+	if err := nodeGQL(ctx, node, `query { feedsManagers { results { publicKey } } }`, nil, &managers); err != nil {
+		return err
+	}
+	for _, fm := range managers.FeedsManagers.Results {
+		if fm.PublicKey == publicKey {
+			return nil
+		}
+	}
+	var res struct {
+		CreateFeedsManager struct {
+			Typename string `json:"__typename"`
+			Message  string `json:"message"`
+		} `json:"createFeedsManager"`
+	}
+	err := nodeGQL(ctx, node, `mutation CreateFeedsManager($input: CreateFeedsManagerInput!) {
+  createFeedsManager(input: $input) {
+    __typename
+    ... on SingleFeedsManagerError { message }
+    ... on NotFoundError { message }
+  }
+}`, map[string]any{"input": map[string]any{"name": "job-distributor", "uri": uri, "publicKey": publicKey}}, &res)
+	if err != nil {
+		return err
+	}
+	if res.CreateFeedsManager.Typename != "CreateFeedsManagerSuccess" {
+		return fmt.Errorf("node %s could not create feeds manager: %s %s", node.URL(), res.CreateFeedsManager.Typename, res.CreateFeedsManager.Message)
+	}
+	return nil
+}
+
+// feedsManagerConnected reports whether the node has an active connection to the feeds manager with publicKey
+func feedsManagerConnected(ctx context.Context, node *clclient.ChainlinkClient, publicKey string) (bool, error) {
+	var res struct {
+		FeedsManagers struct {
+			Results []struct {
+				PublicKey          string `json:"publicKey"`
+				IsConnectionActive bool   `json:"isConnectionActive"`
+			} `json:"results"`
+		} `json:"feedsManagers"`
+	}
+	if err := nodeGQL(ctx, node, `query { feedsManagers { results { publicKey isConnectionActive } } }`, nil, &res); err != nil {
+		return false, err
+	}
+	for _, fm := range res.FeedsManagers.Results {
+		if fm.PublicKey == publicKey {
+			return fm.IsConnectionActive, nil
+		}
+	}
+	return false, fmt.Errorf("node %s has no feeds manager %s", node.URL(), publicKey)
+}
+
+// pendingProposalSpec returns ID of the pending proposal spec with the definition, empty if node hasn't received it yet
+func pendingProposalSpec(ctx context.Context, node *clclient.ChainlinkClient, definition string) (string, error) {
+	var res struct {
+		JobProposals struct {
+			Results []struct {
+				Specs []struct {
+					ID         string `json:"id"`
+					Status     string `json:"status"`
+					Definition string `json:"definition"`
+				} `json:"specs"`
+			} `json:"results"`
+		} `json:"jobProposals"`
+	}
+	if err := nodeGQL(ctx, node, `query { jobProposals { results { specs { id status definition } } } }`, nil, &res); err != nil {
+		return "", err
+	}
+	for _, p := range res.JobProposals.Results {
+		for _, s := range p.Specs {
+			if s.Status == "PENDING" && strings.TrimSpace(s.Definition) == strings.TrimSpace(definition) {
+				return s.ID, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// approveProposalSpec approves a pending proposal spec, node creates the job on approval
+func approveProposalSpec(ctx context.Context, node *clclient.ChainlinkClient, specID string) error {
+	var res struct {
+		ApproveJobProposalSpec struct {
+			Typename string `json:"__typename"`
+			Message  string `json:"message"`
+		} `json:"approveJobProposalSpec"`
+	}
+	err := nodeGQL(ctx, node, `mutation ApproveJobProposalSpec($id: ID!) {
+  approveJobProposalSpec(id: $id, force: false) {
+    __typename
+    ... on NotFoundError { message }
+    ... on JobAlreadyExistsError { message }
+  }
+}`, map[string]any{"id": specID}, &res)
+	if err != nil {
+		return err
+	}
+	if res.ApproveJobProposalSpec.Typename != "ApproveJobProposalSpecSuccess" {
+		return fmt.Errorf("node %s could not approve job proposal spec %s: %s %s", node.URL(), specID, res.ApproveJobProposalSpec.Typename, res.ApproveJobProposalSpec.Message)
+	}
+	return nil
+}
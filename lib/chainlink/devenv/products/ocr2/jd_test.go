@@ -0,0 +1,204 @@
+package ocr2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+
+	"github.com/smartcontractkit/chainlink/devenv/products"
+
+	csav1 "github.com/smartcontractkit/chainlink-protos/job-distributor/v1/csa"
+	jobv1 "github.com/smartcontractkit/chainlink-protos/job-distributor/v1/job"
+	nodev1 "github.com/smartcontractkit/chainlink-protos/job-distributor/v1/node"
+)
+
+type fakeJD struct {
+	products.JobDistributor
+	keys []string
+	mu   sync.Mutex
+	// nodes are known to JD, registered nodes are added
+	nodes      []*nodev1.Node
+	registered int
+	proposed   []*jobv1.ProposeJobRequest
+}
+
+func (f *fakeJD) ListNodes(_ context.Context, in *nodev1.ListNodesRequest, _ ...grpc.CallOption) (*nodev1.ListNodesResponse, error) {
+	res := &nodev1.ListNodesResponse{}
+	for _, n := range f.nodes {
+		if slices.Contains(in.GetFilter().GetPublicKeys(), n.GetPublicKey()) {
+			res.Nodes = append(res.Nodes, n)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeJD) RegisterNode(_ context.Context, in *nodev1.RegisterNodeRequest, _ ...grpc.CallOption) (*nodev1.RegisterNodeResponse, error) {
+	f.registered++
+	n := &nodev1.Node{Id: fmt.Sprintf("node-%d", len(f.nodes)+1), PublicKey: in.GetPublicKey(), Name: in.GetName()}
+	f.nodes = append(f.nodes, n)
+	return &nodev1.RegisterNodeResponse{Node: n}, nil
+}
+
+func (f *fakeJD) ProposeJob(_ context.Context, in *jobv1.ProposeJobRequest, _ ...grpc.CallOption) (*jobv1.ProposeJobResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.proposed = append(f.proposed, in)
+	return &jobv1.ProposeJobResponse{Proposal: &jobv1.Proposal{Id: fmt.Sprintf("proposal-%d", len(f.proposed))}}, nil
+}
+
+func (f *fakeJD) ListKeypairs(context.Context, *csav1.ListKeypairsRequest, ...grpc.CallOption) (*csav1.ListKeypairsResponse, error) {
+	res := &csav1.ListKeypairsResponse{}
+	for _, k := range f.keys {
+		res.Keypairs = append(res.Keypairs, &csav1.Keypair{PublicKey: k})
+	}
+	return res, nil
+}
+
+func TestJobCreator(t *testing.T) {
+	ctx := context.Background()
+	direct := &Configurator{OCR2: &OCR2{}}
+	create, err := direct.jobCreator(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, create)
+
+	viaJD := &Configurator{OCR2: &OCR2{Jobs: &Jobs{ViaJD: true}}}
+	_, err = viaJD.jobCreator(ctx)
+	require.ErrorContains(t, err, "no job distributor is available")
+
+	viaJD.UseJobDistributor(&fakeJD{}, "jd:8080")
+	_, err = viaJD.jobCreator(ctx)
+	require.ErrorContains(t, err, "job distributor has no CSA keys")
+
+	viaJD.UseJobDistributor(&fakeJD{keys: []string{"abcd"}}, "jd:8080")
+	create, err = viaJD.jobCreator(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, create)
+}
+
+func TestJobsProposalWait(t *testing.T) {
+	require.Equal(t, DefaultJDProposalWait, (*Jobs)(nil).proposalWait())
+	require.Equal(t, DefaultJDProposalWait, (&Jobs{}).proposalWait())
+	w := (&Jobs{ProposalWait: &WaitConfig{TimeoutSec: 300}}).proposalWait()
+	require.Equal(t, int64(300), w.TimeoutSec)
+	require.Equal(t, DefaultJDProposalWait.PollIntervalMs, w.PollIntervalMs)
+	// transaction wait doesn't change how proposals are awaited
+	require.Equal(t, DefaultJDProposalWait, (&OCR2{Wait: &WaitConfig{TimeoutSec: 1}}).Jobs.proposalWait())
+}
+
+// jdNode serves node GraphQL queries of the JD flow, every job proposed through jd is listed as a proposal of the node
+type jdNode struct {
+	jd       *fakeJD
+	mu       sync.Mutex
+	managers []string
+	approved []string
+}
+
+func (n *jdNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var data any
+	switch {
+	case strings.Contains(req.Query, "csaKeys"):
+		data = map[string]any{"csaKeys": map[string]any{"results": []any{map[string]any{"publicKey": "csa_nodekey"}}}}
+	case strings.Contains(req.Query, "createFeedsManager"):
+		n.managers = append(n.managers, req.Variables["input"].(map[string]any)["publicKey"].(string))
+		data = map[string]any{"createFeedsManager": map[string]any{"__typename": "CreateFeedsManagerSuccess"}}
+	case strings.Contains(req.Query, "feedsManagers"):
+		results := make([]any, 0, len(n.managers))
+		for _, k := range n.managers {
+			results = append(results, map[string]any{"publicKey": k, "isConnectionActive": true})
+		}
+		data = map[string]any{"feedsManagers": map[string]any{"results": results}}
+	case strings.Contains(req.Query, "jobProposals"):
+		n.jd.mu.Lock()
+		defer n.jd.mu.Unlock()
+		specs := make([]any, 0, len(n.jd.proposed))
+		for i, p := range n.jd.proposed {
+			status := "PENDING"
+			if slices.Contains(n.approved, strconv.Itoa(i+1)) {
+				status = "APPROVED"
+			}
+			specs = append(specs, map[string]any{"id": strconv.Itoa(i + 1), "status": status, "definition": p.GetSpec()})
+		}
+		data = map[string]any{"jobProposals": map[string]any{"results": []any{map[string]any{"specs": specs}}}}
+	case strings.Contains(req.Query, "approveJobProposalSpec"):
+		n.approved = append(n.approved, req.Variables["id"].(string))
+		data = map[string]any{"approveJobProposalSpec": map[string]any{"__typename": "ApproveJobProposalSpecSuccess"}}
+	default:
+		_ = json.NewEncoder(w).Encode(map[string]any{"errors": []any{map[string]any{"message": "unexpected query"}}})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func TestJDJobsCreate(t *testing.T) {
+	ctx := context.Background()
+	spec := &TaskJobSpec{
+		Name:    "ocr2_bootstrap",
+		JobType: JobTypeBootstrap,
+		OCR2OracleSpec: OracleSpec{
+			ContractID:  "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+			Relay:       "evm",
+			RelayConfig: map[string]any{"chainID": "1337"},
+		},
+	}
+	definition, err := spec.String()
+	require.NoError(t, err)
+	w := WaitConfig{PollIntervalMs: 10, TimeoutSec: 1}
+
+	tests := []struct {
+		name string
+		// nodes are already registered in JD, ex.: JD is reused from a previous run
+		nodes          []*nodev1.Node
+		wantNodeID     string
+		wantRegistered int
+	}{
+		{name: "new node", wantNodeID: "node-1", wantRegistered: 1},
+		{name: "registered node is reused", nodes: []*nodev1.Node{{Id: "existing", PublicKey: "nodekey"}}, wantNodeID: "existing"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			jd := &fakeJD{keys: []string{"jdkey"}, nodes: tc.nodes}
+			node := &jdNode{jd: jd}
+			srv := httptest.NewServer(node)
+			defer srv.Close()
+			cl := &clclient.ChainlinkClient{APIClient: newRestyClient(srv.URL), Config: &clclient.Config{URL: srv.URL}}
+
+			d, err := newJDJobs(ctx, jd, "jd:8080", w)
+			require.NoError(t, err)
+			// jobs of the same node are proposed to the node registered once
+			require.NoError(t, d.create(ctx, cl, spec))
+			require.NoError(t, d.create(ctx, cl, spec))
+			node.mu.Lock()
+			defer node.mu.Unlock()
+			require.Equal(t, tc.wantRegistered, jd.registered)
+			require.Equal(t, []string{"jdkey"}, node.managers)
+			require.Len(t, jd.proposed, 2)
+			for _, p := range jd.proposed {
+				require.Equal(t, tc.wantNodeID, p.GetNodeId())
+				require.Equal(t, definition, p.GetSpec())
+			}
+			require.Equal(t, []string{"1", "2"}, node.approved)
+		})
+	}
+}
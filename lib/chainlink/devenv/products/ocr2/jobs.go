@@ -0,0 +1,180 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+)
+
+var jobTypeRe = regexp.MustCompile(`(?m)^\s*type\s*=\s*"([^"]+)"`)
+
+// RawJobSpec is a rendered TOML job spec, ex.: read from a file
+type RawJobSpec string
+
+// Type returns the type of the job parsed from the spec.
+func (s RawJobSpec) Type() string {
+	m := jobTypeRe.FindStringSubmatch(string(s))
+	if len(m) < 2 {
+		return ""
+	}
+	return m[1]
+}
+
+// String representation of the job.
+func (s RawJobSpec) String() (string, error) { return string(s), nil }
+
+// CreateJobOnNode creates a single job on the node with index idx without touching other nodes.
+// If node rejects the spec its response is returned verbatim.
+func CreateJobOnNode(ctx context.Context, cl []*clclient.ChainlinkClient, idx int, spec clclient.JobSpec) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if idx < 0 || idx >= len(cl) {
+		return "", fmt.Errorf("node index %d is out of range, there are %d nodes", idx, len(cl))
+	}
+	if _, err := spec.String(); err != nil {
+		return "", fmt.Errorf("could not render job spec: %w", err)
+	}
+	job, resp, err := cl[idx].CreateJob(spec)
+	if err != nil {
+		return "", fmt.Errorf("creating job on node %d have failed: %w", idx, err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return "", fmt.Errorf("node %d rejected the job (status %d): %s", idx, resp.StatusCode(), resp.String())
+	}
+	ctxLogger(ctx).Info().
+		Int("Node", idx).
+		Str("JobID", job.Data.ID).
+		Str("Type", spec.Type()).
+		Msg("Job created")
+	return job.Data.ID, nil
+}
+
+// jobsPageSize is big enough to list all jobs on a dev node in one request
+const jobsPageSize = 1000
+
+// JobSummary is a job as listed by the node API
+type JobSummary struct {
+	ID   string
+	Type string
+	Name string
+}
+
+// ListJobs returns all jobs on the node
+func ListJobs(ctx context.Context, node *clclient.ChainlinkClient) ([]JobSummary, error) {
+	var res struct {
+		Data []struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	resp, err := node.APIClient.R().
+		SetContext(ctx).
+		SetQueryParam("size", fmt.Sprint(jobsPageSize)).
+		SetResult(&res).
+		Get("/v2/jobs")
+	if err != nil {
+		return nil, fmt.Errorf("listing jobs on node %s have failed: %w", node.URL(), err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("node %s refused to list jobs (status %d): %s", node.URL(), resp.StatusCode(), resp.String())
+	}
+	jobs := make([]JobSummary, 0, len(res.Data))
+	for _, j := range res.Data {
+		jobs = append(jobs, JobSummary{ID: j.ID, Type: j.Attributes.Type, Name: j.Attributes.Name})
+	}
+	return jobs, nil
+}
+
+// DeleteJob deletes a job from the node, if node refuses the deletion, ex.: job is still running, its response is returned verbatim
+func DeleteJob(ctx context.Context, node *clclient.ChainlinkClient, id string) error {
+	resp, err := node.APIClient.R().
+		SetContext(ctx).
+		SetPathParam("id", id).
+		Delete("/v2/jobs/{id}")
+	if err != nil {
+		return fmt.Errorf("deleting job %s on node %s have failed: %w", id, node.URL(), err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("node %s refused to delete job %s (status %d): %s", node.URL(), id, resp.StatusCode(), resp.String())
+	}
+	ctxLogger(ctx).Info().Str("Node", node.URL()).Str("JobID", id).Msg("Job deleted")
+	return nil
+}
+
+// DeleteOCR2Jobs deletes OCR2 and bootstrap jobs from the node and returns how many were deleted, other jobs are kept
+func DeleteOCR2Jobs(ctx context.Context, node *clclient.ChainlinkClient) (int, error) {
+	jobs, err := ListJobs(ctx, node)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, j := range jobs {
+		if j.Type != JobTypeOCR2 && j.Type != JobTypeBootstrap {
+			continue
+		}
+		if err := DeleteJob(ctx, node, j.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Placeholders rendered in place of node keys by GenerateJobSpecs
+const (
+	PlaceholderAggregatorAddress = "<ocr2_aggregator_address>"
+	PlaceholderTransmitter       = "<transmitter_address>"
+	PlaceholderOCRKeyBundleID    = "<ocr2_key_bundle_id>"
+	PlaceholderBootstrapPeerID   = "<bootstrap_p2p_peer_id>"
+	PlaceholderBootstrapHost     = "<bootstrap_host>"
+)
+
+// GenerateJobSpecs renders bootstrap and worker job specs the environment would create, without running nodes or contracts.
+// Node keys are replaced with placeholders, one worker spec is rendered per transmitter, or a single one if there are none.
+// Specs are keyed by file name, ex.: bootstrap.toml, worker-1.toml
+func GenerateJobSpecs(cfg *Configurator, aggregatorAddr string, transmitters []string, chainID string) (map[string]string, error) {
+	if cfg == nil || cfg.OCR2 == nil {
+		return nil, errors.New("no OCR2 config to render job specs from")
+	}
+	aggregatorAddr = defaults.Coalesce(aggregatorAddr, PlaceholderAggregatorAddress)
+	if len(transmitters) == 0 {
+		transmitters = []string{PlaceholderTransmitter}
+	}
+	p2p, err := cfg.OCR2.p2pSettings()
+	if err != nil {
+		return nil, err
+	}
+	bootstrapper := fmt.Sprintf("%s@%s:%d", PlaceholderBootstrapPeerID, PlaceholderBootstrapHost, p2p.AdvertisedPort)
+	specs := make(map[string]string, len(transmitters)+1)
+	bootstrap, err := cfg.bootstrapJobSpec("ocr2_bootstrap", chainID, aggregatorAddr)
+	if err != nil {
+		return nil, err
+	}
+	if specs["bootstrap.toml"], err = bootstrap.String(); err != nil {
+		return nil, fmt.Errorf("could not render bootstrap job spec: %w", err)
+	}
+	ea := &clclient.BridgeTypeAttributes{Name: "ea"}
+	juels := &clclient.BridgeTypeAttributes{Name: "juels"}
+	for i, transmitter := range transmitters {
+		// node 0 is the bootstrap node, workers start from 1
+		worker, wErr := cfg.workerJobSpec(fmt.Sprintf("ocr2-node%d", i+1), chainID, aggregatorAddr, []string{bootstrapper}, PlaceholderOCRKeyBundleID, transmitter, ea, juels)
+		if wErr != nil {
+			return nil, wErr
+		}
+		name := fmt.Sprintf("worker-%d.toml", i+1)
+		if specs[name], err = worker.String(); err != nil {
+			return nil, fmt.Errorf("could not render job spec %s: %w", name, err)
+		}
+	}
+	return specs, nil
+}
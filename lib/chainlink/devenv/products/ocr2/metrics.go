@@ -0,0 +1,32 @@
+package ocr2
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// EnvVarExportMetrics enables saving raw Prometheus query results of test checks as JSON artifacts, ex.: OCR2_EXPORT_METRICS=true
+const EnvVarExportMetrics = "OCR2_EXPORT_METRICS"
+
+// ExportMetricsEnabled returns true if query results should be saved, it's off by default
+func ExportMetricsEnabled() bool {
+	return os.Getenv(EnvVarExportMetrics) == "true"
+}
+
+// WriteJSONArtifact writes v as indented JSON to dir/name.json and returns the file path, dir is created if it doesn't exist
+func WriteJSONArtifact(dir, name string, v any) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("could not create artifacts dir %s: %w", dir, err)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("could not encode artifact %s: %w", name, err)
+	}
+	path := filepath.Join(dir, name+".json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("could not write artifact %s: %w", path, err)
+	}
+	return path, nil
+}
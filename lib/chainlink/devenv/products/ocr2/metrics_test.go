@@ -0,0 +1,35 @@
+package ocr2
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSONArtifact(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "TestLoad", "clean", "metrics")
+	v := map[string]any{"status": "success", "data": map[string]any{"resultType": "vector"}}
+
+	path, err := WriteJSONArtifact(dir, "cpu", v)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "cpu.json"), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Equal(t, v, got)
+
+	_, err = WriteJSONArtifact(dir, "bad", func() {})
+	require.ErrorContains(t, err, "could not encode artifact bad")
+}
+
+func TestExportMetricsEnabled(t *testing.T) {
+	t.Setenv(EnvVarExportMetrics, "")
+	require.False(t, ExportMetricsEnabled())
+	t.Setenv(EnvVarExportMetrics, "true")
+	require.True(t, ExportMetricsEnabled())
+}
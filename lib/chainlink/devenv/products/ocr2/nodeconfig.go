@@ -0,0 +1,63 @@
+package ocr2
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/libocr/offchainreporting2/types"
+)
+
+// configSwitchRe matches the digest libocr logs when an oracle or bootstrapper switches to a new config,
+// ex.: "newConfigDigest":"000e..." in JSON logs or newConfigDigest=000e... in console logs
+var configSwitchRe = regexp.MustCompile(`newConfigDigest\W{1,4}([0-9a-fA-F]{64})`)
+
+// LastConfigDigest returns the config digest a node switched to last for a contract according to its logs, false if it never
+// switched to a config of the contract. Config switches are logged with contractID of the job, a node running jobs of
+// several feeds logs switches of every aggregator
+func LastConfigDigest(logs []byte, contractID common.Address) (types.ConfigDigest, bool) {
+	contractRe := regexp.MustCompile(`(?i)contractID\W{1,4}` + contractID.Hex())
+	var last []byte
+	for _, line := range bytes.Split(logs, []byte("\n")) {
+		m := configSwitchRe.FindSubmatch(line)
+		if m == nil || !contractRe.Match(line) {
+			continue
+		}
+		last = m[1]
+	}
+	if last == nil {
+		return types.ConfigDigest{}, false
+	}
+	var d types.ConfigDigest
+	// the pattern only matches 64 hex characters
+	_, _ = hex.Decode(d[:], last)
+	return d, true
+}
+
+// NodeDigest is the config digest a node runs, Found is false if the node didn't switch to any config yet
+type NodeDigest struct {
+	Node   string
+	Digest types.ConfigDigest
+	Found  bool
+}
+
+func (n NodeDigest) String() string {
+	if !n.Found {
+		return fmt.Sprintf("%s: no config", n.Node)
+	}
+	return fmt.Sprintf("%s: %s", n.Node, n.Digest.Hex())
+}
+
+// DivergentNodes returns nodes that don't run the expected config, ex.: a node stuck on a stale config
+// while the others keep the feed alive
+func DivergentNodes(nodes []NodeDigest, expected types.ConfigDigest) []NodeDigest {
+	var divergent []NodeDigest
+	for _, n := range nodes {
+		if !n.Found || n.Digest != expected {
+			divergent = append(divergent, n)
+		}
+	}
+	return divergent
+}
@@ -0,0 +1,69 @@
+package ocr2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/libocr/offchainreporting2/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastConfigDigest(t *testing.T) {
+	first := strings.Repeat("0a", 32)
+	second := strings.Repeat("0b", 32)
+	other := strings.Repeat("0c", 32)
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	otherContract := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	tests := []struct {
+		name   string
+		logs   string
+		digest string
+		found  bool
+	}{
+		{name: "no switch", logs: "2025-01-01T00:00:00Z [INFO] Starting node\n"},
+		{
+			name:   "json",
+			logs:   `{"level":"info","msg":"ManagedOCR2Oracle: switching between configs","contractID":"` + contract.Hex() + `","oldConfigDigest":"` + strings.Repeat("0", 64) + `","newConfigDigest":"` + first + `"}` + "\n",
+			digest: first,
+			found:  true,
+		},
+		{
+			name: "console, last switch wins",
+			logs: "switching between configs contractID=" + contract.Hex() + " newConfigDigest=" + first + " oldConfigDigest=" + strings.Repeat("0", 64) + "\n" +
+				"switching between configs contractID=" + strings.ToLower(contract.Hex()) + " newConfigDigest=" + second + " oldConfigDigest=" + first + "\n",
+			digest: second,
+			found:  true,
+		},
+		{
+			name: "switches of other feeds are ignored",
+			logs: `{"contractID":"` + contract.Hex() + `","newConfigDigest":"` + first + `"}` + "\n" +
+				`{"contractID":"` + otherContract.Hex() + `","newConfigDigest":"` + other + `"}` + "\n",
+			digest: first,
+			found:  true,
+		},
+		{name: "only other feeds", logs: `{"contractID":"` + otherContract.Hex() + `","newConfigDigest":"` + other + `"}` + "\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, found := LastConfigDigest([]byte(tc.logs), contract)
+			require.Equal(t, tc.found, found)
+			if tc.found {
+				require.Equal(t, tc.digest, d.Hex())
+			}
+		})
+	}
+}
+
+func TestDivergentNodes(t *testing.T) {
+	expected := types.ConfigDigest{1}
+	nodes := []NodeDigest{
+		{Node: "don-node0", Digest: expected, Found: true},
+		{Node: "don-node1", Digest: types.ConfigDigest{2}, Found: true},
+		{Node: "don-node2"},
+	}
+	divergent := DivergentNodes(nodes, expected)
+	require.Equal(t, nodes[1:], divergent)
+	require.Equal(t, "don-node2: no config", divergent[1].String())
+	require.Empty(t, DivergentNodes(nodes[:1], expected))
+}
@@ -0,0 +1,254 @@
+package ocr2
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/libocr/offchainreporting2/confighelper"
+	"github.com/smartcontractkit/libocr/offchainreporting2/types"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+)
+
+// NodeKeyReader reads node keys, ex.: *clclient.ChainlinkClient
+type NodeKeyReader interface {
+	ReadPrimaryETHKey(chainID string) (*clclient.ETHKeyData, error)
+	MustReadOCR2Keys() (*clclient.OCR2Keys, error)
+	MustReadP2PKeys() (*clclient.P2PKeys, error)
+}
+
+// NodeInfo is everything OCR2 config and jobs need to know about a node
+type NodeInfo struct {
+	// ETHAddress is the primary ETH key of the chain, node transmits from it
+	ETHAddress common.Address
+	// OCR2BundleID is the ID of the EVM OCR2 key bundle jobs reference
+	OCR2BundleID          string
+	OCR2OnchainPublicKey  []byte
+	OCR2OffchainPublicKey [ed25519.PublicKeySize]byte
+	OCR2ConfigPublicKey   [ed25519.PublicKeySize]byte
+	P2PPeerID             string
+}
+
+// KeyBundles selects the EVM OCR2 key bundle every worker node signs with, the first EVM bundle is used if not set
+type KeyBundles struct {
+	// Index is the position of the bundle among node EVM bundles, it's used for nodes without an ID
+	Index int `toml:"index"`
+	// IDs are bundle IDs by node index in the worker node set, empty entries fall back to Index
+	IDs []string `toml:"ids"`
+}
+
+// Validate checks bundle index is not negative
+func (k *KeyBundles) Validate() error {
+	if k == nil {
+		return nil
+	}
+	if k.Index < 0 {
+		return fmt.Errorf("key_bundles index must not be negative, got %d", k.Index)
+	}
+	return nil
+}
+
+// forNode returns bundle ID and index node with index i uses, an empty ID selects the bundle by index
+func (k *KeyBundles) forNode(i int) (string, int) {
+	if k == nil {
+		return "", 0
+	}
+	if i < len(k.IDs) && k.IDs[i] != "" {
+		return k.IDs[i], 0
+	}
+	return "", k.Index
+}
+
+// CollectNodeInfo reads keys of all nodes concurrently, chainID selects the primary ETH key and bundles the OCR2 key bundle,
+// nil bundles use the first EVM bundle of every node
+func CollectNodeInfo(ctx context.Context, cl []*clclient.ChainlinkClient, chainID string, bundles *KeyBundles) ([]NodeInfo, error) {
+	return collectNodeInfo(ctx, keyReaders(cl), chainID, bundles)
+}
+
+// keyReaders returns clients as key readers
+func keyReaders(cl []*clclient.ChainlinkClient) []NodeKeyReader {
+	nodes := make([]NodeKeyReader, 0, len(cl))
+	for _, c := range cl {
+		nodes = append(nodes, c)
+	}
+	return nodes
+}
+
+func collectNodeInfo(ctx context.Context, nodes []NodeKeyReader, chainID string, bundles *KeyBundles) ([]NodeInfo, error) {
+	infos := make([]NodeInfo, len(nodes))
+	eg, ctx := errgroup.WithContext(ctx)
+	for i, n := range nodes {
+		eg.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			bundleID, bundleIdx := bundles.forNode(i)
+			info, err := readNodeInfo(n, chainID, bundleID, bundleIdx)
+			if err != nil {
+				return fmt.Errorf("could not read keys of node %d: %w", i, err)
+			}
+			infos[i] = *info
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+func readNodeInfo(n NodeKeyReader, chainID, bundleID string, bundleIdx int) (*NodeInfo, error) {
+	ethKey, err := n.ReadPrimaryETHKey(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("could not read primary ETH key: %w", err)
+	}
+	ocr2Keys, err := n.MustReadOCR2Keys()
+	if err != nil {
+		return nil, fmt.Errorf("could not read OCR2 keys: %w", err)
+	}
+	bundle, err := selectBundle(ocr2Keys.Data, bundleID, bundleIdx)
+	if err != nil {
+		return nil, err
+	}
+	p2pKeys, err := n.MustReadP2PKeys()
+	if err != nil {
+		return nil, fmt.Errorf("could not read P2P keys: %w", err)
+	}
+	if len(p2pKeys.Data) == 0 {
+		return nil, errors.New("no P2P keys found")
+	}
+	info := &NodeInfo{
+		ETHAddress:   common.HexToAddress(ethKey.Attributes.Address),
+		OCR2BundleID: bundle.ID,
+		P2PPeerID:    p2pKeys.Data[0].Attributes.PeerID,
+	}
+	if info.OCR2OnchainPublicKey, err = hex.DecodeString(strings.TrimPrefix(bundle.Attributes.OnChainPublicKey, "ocr2on_evm_")); err != nil {
+		return nil, fmt.Errorf("invalid OCR2 onchain public key: %w", err)
+	}
+	if info.OCR2OffchainPublicKey, err = ed25519Key(bundle.Attributes.OffChainPublicKey, "ocr2off_evm_"); err != nil {
+		return nil, fmt.Errorf("invalid OCR2 offchain public key: %w", err)
+	}
+	if info.OCR2ConfigPublicKey, err = ed25519Key(bundle.Attributes.ConfigPublicKey, "ocr2cfg_evm_"); err != nil {
+		return nil, fmt.Errorf("invalid OCR2 config public key: %w", err)
+	}
+	return info, nil
+}
+
+// selectBundle returns EVM bundle with id, or the EVM bundle at idx if id is empty
+func selectBundle(keys []clclient.OCR2KeyData, id string, idx int) (*clclient.OCR2KeyData, error) {
+	evm := make([]*clclient.OCR2KeyData, 0, len(keys))
+	for i := range keys {
+		if keys[i].Attributes.ChainType == "evm" {
+			evm = append(evm, &keys[i])
+		}
+	}
+	if len(evm) == 0 {
+		return nil, errors.New("no EVM OCR2 key bundle found")
+	}
+	if id == "" {
+		if idx >= len(evm) {
+			return nil, fmt.Errorf("EVM OCR2 key bundle index %d is out of range, node has %d EVM bundles", idx, len(evm))
+		}
+		return evm[idx], nil
+	}
+	for _, b := range evm {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("EVM OCR2 key bundle %s not found", id)
+}
+
+// verifySigners checks signers the aggregator emitted in ConfigSet are onchain keys nodes report for the bundles their jobs
+// reference, bundleIDs are bundle IDs by node index. Keys are read from nodes again, so a bundle deleted or replaced
+// after identities were collected is caught, a mismatch makes every report fail verification
+func verifySigners(ctx context.Context, signers []common.Address, nodes []NodeKeyReader, bundleIDs []string) error {
+	if len(signers) != len(nodes) || len(bundleIDs) != len(nodes) {
+		return fmt.Errorf("aggregator has %d signers, but there are %d nodes with %d bundles", len(signers), len(nodes), len(bundleIDs))
+	}
+	eg, ctx := errgroup.WithContext(ctx)
+	for i, n := range nodes {
+		eg.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			keys, err := n.MustReadOCR2Keys()
+			if err != nil {
+				return fmt.Errorf("could not read OCR2 keys of node %d: %w", i, err)
+			}
+			bundle, err := selectBundle(keys.Data, bundleIDs[i], 0)
+			if err != nil {
+				return fmt.Errorf("node %d: %w", i, err)
+			}
+			key, err := hex.DecodeString(strings.TrimPrefix(bundle.Attributes.OnChainPublicKey, "ocr2on_evm_"))
+			if err != nil {
+				return fmt.Errorf("invalid OCR2 onchain public key of node %d: %w", i, err)
+			}
+			if want := common.BytesToAddress(key); signers[i] != want {
+				return fmt.Errorf("aggregator signer %d is %s, but node signs with bundle %s key %s", i, signers[i].Hex(), bundleIDs[i], want.Hex())
+			}
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// bundleIDs returns bundle IDs jobs of nodes reference by node index
+func bundleIDs(infos []NodeInfo) []string {
+	ids := make([]string, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.OCR2BundleID)
+	}
+	return ids
+}
+
+// ed25519Key decodes a prefixed hex key of exactly ed25519.PublicKeySize bytes
+func ed25519Key(key, prefix string) ([ed25519.PublicKeySize]byte, error) {
+	var fixed [ed25519.PublicKeySize]byte
+	b, err := hex.DecodeString(strings.TrimPrefix(key, prefix))
+	if err != nil {
+		return fixed, err
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return fixed, fmt.Errorf("key has %d bytes, expected %d", len(b), ed25519.PublicKeySize)
+	}
+	copy(fixed[:], b)
+	return fixed, nil
+}
+
+// OracleIdentity returns OCR2 oracle identity of the node, it transmits from its primary ETH key
+func (n NodeInfo) OracleIdentity() confighelper.OracleIdentityExtra {
+	return confighelper.OracleIdentityExtra{
+		OracleIdentity: confighelper.OracleIdentity{
+			OnchainPublicKey:  n.OCR2OnchainPublicKey,
+			OffchainPublicKey: n.OCR2OffchainPublicKey,
+			PeerID:            n.P2PPeerID,
+			TransmitAccount:   types.Account(n.ETHAddress.Hex()),
+		},
+		ConfigEncryptionPublicKey: n.OCR2ConfigPublicKey,
+	}
+}
+
+// oracleIdentities returns oracle identities of nodes and their transmission schedule, every node transmits once per stage
+func oracleIdentities(infos []NodeInfo) ([]int, []confighelper.OracleIdentityExtra) {
+	s := make([]int, len(infos))
+	ids := make([]confighelper.OracleIdentityExtra, len(infos))
+	for i, info := range infos {
+		s[i] = 1
+		ids[i] = info.OracleIdentity()
+		L.Trace().
+			Interface("OnChainPK", info.OCR2OnchainPublicKey).
+			Interface("OffChainPK", info.OCR2OffchainPublicKey).
+			Interface("ConfigPK", info.OCR2ConfigPublicKey).
+			Str("PeerID", info.P2PPeerID).
+			Str("Address", info.ETHAddress.Hex()).
+			Msg("Oracle identity")
+	}
+	return s, ids
+}
@@ -0,0 +1,162 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/libocr/offchainreporting2/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+)
+
+type mockKeyReader struct {
+	chainID string
+	eth     string
+	ocr2    []clclient.OCR2KeyData
+	peerID  string
+}
+
+func (m *mockKeyReader) ReadPrimaryETHKey(chainID string) (*clclient.ETHKeyData, error) {
+	if chainID != m.chainID {
+		return nil, errors.New("no ETH key for chain " + chainID)
+	}
+	k := &clclient.ETHKeyData{}
+	k.Attributes.Address = m.eth
+	return k, nil
+}
+
+func (m *mockKeyReader) MustReadOCR2Keys() (*clclient.OCR2Keys, error) {
+	return &clclient.OCR2Keys{Data: m.ocr2}, nil
+}
+
+func (m *mockKeyReader) MustReadP2PKeys() (*clclient.P2PKeys, error) {
+	k := &clclient.P2PKeys{Data: []clclient.P2PKeyData{{}}}
+	k.Data[0].Attributes.PeerID = m.peerID
+	return k, nil
+}
+
+func ocr2Key(id, chainType string, b byte) clclient.OCR2KeyData {
+	k := clclient.OCR2KeyData{ID: id}
+	k.Attributes.ChainType = chainType
+	k.Attributes.OnChainPublicKey = "ocr2on_evm_" + strings.Repeat("0a", 20)
+	k.Attributes.OffChainPublicKey = "ocr2off_evm_" + strings.Repeat(fmt.Sprintf("%02x", b), 32)
+	k.Attributes.ConfigPublicKey = "ocr2cfg_evm_" + strings.Repeat("0c", 32)
+	return k
+}
+
+func TestCollectNodeInfo(t *testing.T) {
+	ctx := context.Background()
+	node := func() *mockKeyReader {
+		return &mockKeyReader{
+			chainID: "1337",
+			eth:     "0x70997970c51812dc3a010c7d01b50e0d17dc79c8",
+			ocr2:    []clclient.OCR2KeyData{ocr2Key("solana-bundle", "solana", 0xff), ocr2Key("evm-bundle", "evm", 0x0b)},
+			peerID:  "12D3KooWPeer",
+		}
+	}
+
+	infos, err := collectNodeInfo(ctx, []NodeKeyReader{node()}, "1337", nil)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	info := infos[0]
+	require.Equal(t, common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), info.ETHAddress)
+	require.Equal(t, "evm-bundle", info.OCR2BundleID)
+	require.Equal(t, "12D3KooWPeer", info.P2PPeerID)
+	require.Equal(t, common.HexToAddress("0x0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a").Bytes(), info.OCR2OnchainPublicKey)
+	require.Equal(t, byte(0x0b), info.OCR2OffchainPublicKey[31])
+	require.Equal(t, byte(0x0c), info.OCR2ConfigPublicKey[0])
+
+	id := info.OracleIdentity()
+	require.Equal(t, types.Account("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), id.TransmitAccount)
+	require.Equal(t, info.OCR2ConfigPublicKey, [32]byte(id.ConfigEncryptionPublicKey))
+	s, ids := oracleIdentities(infos)
+	require.Equal(t, []int{1}, s)
+	require.Equal(t, id, ids[0])
+
+	tests := []struct {
+		name    string
+		mutate  func(m *mockKeyReader)
+		chainID string
+		wantErr string
+	}{
+		{name: "other chain", chainID: "2337", mutate: func(m *mockKeyReader) {}, wantErr: "could not read primary ETH key: no ETH key for chain 2337"},
+		{name: "no EVM bundle", chainID: "1337", mutate: func(m *mockKeyReader) { m.ocr2 = m.ocr2[:1] }, wantErr: "no EVM OCR2 key bundle found"},
+		{name: "short offchain key", chainID: "1337", mutate: func(m *mockKeyReader) { m.ocr2[1].Attributes.OffChainPublicKey = "ocr2off_evm_0b0b" }, wantErr: "key has 2 bytes, expected 32"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			broken := node()
+			tc.mutate(broken)
+			_, err := collectNodeInfo(ctx, []NodeKeyReader{node(), broken}, tc.chainID, nil)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestCollectNodeInfoKeyBundles(t *testing.T) {
+	ctx := context.Background()
+	node := func() *mockKeyReader {
+		return &mockKeyReader{
+			chainID: "1337",
+			eth:     "0x70997970c51812dc3a010c7d01b50e0d17dc79c8",
+			ocr2:    []clclient.OCR2KeyData{ocr2Key("evm-0", "evm", 0x01), ocr2Key("solana", "solana", 0xff), ocr2Key("evm-1", "evm", 0x02)},
+		}
+	}
+	tests := []struct {
+		name    string
+		bundles *KeyBundles
+		want    []string
+		wantErr string
+	}{
+		{name: "default", want: []string{"evm-0", "evm-0"}},
+		{name: "by index", bundles: &KeyBundles{Index: 1}, want: []string{"evm-1", "evm-1"}},
+		{name: "by node ID", bundles: &KeyBundles{IDs: []string{"", "evm-1"}}, want: []string{"evm-0", "evm-1"}},
+		{name: "index out of range", bundles: &KeyBundles{Index: 2}, wantErr: "EVM OCR2 key bundle index 2 is out of range, node has 2 EVM bundles"},
+		{name: "unknown ID", bundles: &KeyBundles{IDs: []string{"evm-2"}}, wantErr: "EVM OCR2 key bundle evm-2 not found"},
+		{name: "non-EVM ID", bundles: &KeyBundles{IDs: []string{"solana"}}, wantErr: "EVM OCR2 key bundle solana not found"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			infos, err := collectNodeInfo(ctx, []NodeKeyReader{node(), node()}, "1337", tc.bundles)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			// keys are read from the selected bundle, not the first one
+			offchainKeyByte := map[string]byte{"evm-0": 0x01, "evm-1": 0x02}
+			for i, info := range infos {
+				require.Equal(t, tc.want[i], info.OCR2BundleID)
+				require.Equal(t, offchainKeyByte[tc.want[i]], info.OCR2OffchainPublicKey[0])
+			}
+		})
+	}
+	require.ErrorContains(t, (&KeyBundles{Index: -1}).Validate(), "must not be negative")
+}
+
+func TestVerifySigners(t *testing.T) {
+	ctx := context.Background()
+	onchain := func(id string, addr common.Address) clclient.OCR2KeyData {
+		k := ocr2Key(id, "evm", 0x0b)
+		k.Attributes.OnChainPublicKey = "ocr2on_evm_" + strings.TrimPrefix(strings.ToLower(addr.Hex()), "0x")
+		return k
+	}
+	a1, a2, a3 := common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03")
+	nodes := []NodeKeyReader{
+		&mockKeyReader{ocr2: []clclient.OCR2KeyData{onchain("a", a1)}},
+		&mockKeyReader{ocr2: []clclient.OCR2KeyData{onchain("old", a3), onchain("b", a2)}},
+	}
+	require.NoError(t, verifySigners(ctx, []common.Address{a1, a2}, nodes, []string{"a", "b"}))
+	require.ErrorContains(t, verifySigners(ctx, []common.Address{a1}, nodes, []string{"a", "b"}), "aggregator has 1 signers, but there are 2 nodes with 2 bundles")
+	// node 1 job references bundle b, the aggregator was configured with the key of another bundle
+	err := verifySigners(ctx, []common.Address{a1, a3}, nodes, []string{"a", "b"})
+	require.ErrorContains(t, err, "aggregator signer 1 is 0x0000000000000000000000000000000000000003, but node signs with bundle b key 0x0000000000000000000000000000000000000002")
+	// bundle was deleted after identities were collected
+	err = verifySigners(ctx, []common.Address{a1, a2}, nodes, []string{"a", "gone"})
+	require.ErrorContains(t, err, "node 1: EVM OCR2 key bundle gone not found")
+}
@@ -0,0 +1,249 @@
+package ocr2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lib/pq"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+
+	"github.com/smartcontractkit/chainlink-common/pkg/types"
+)
+
+// disabledJobs keeps specs of jobs removed by DisableJob until EnableJob re-creates them, keyed by node URL and job ID
+var disabledJobs = struct {
+	sync.Mutex
+	specs map[string]*TaskJobSpec
+}{specs: make(map[string]*TaskJobSpec)}
+
+func disabledJobKey(nodeURL, id string) string { return nodeURL + "/" + id }
+
+// jobResource is an OCR2 or bootstrap job as returned by the node API, ex.: GET /v2/jobs/1
+type jobResource struct {
+	Data struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Name              string        `json:"name"`
+			Type              string        `json:"type"`
+			MaxTaskDuration   string        `json:"maxTaskDuration"`
+			ForwardingAllowed bool          `json:"forwardingAllowed"`
+			OCR2Spec          *jobOCR2Attrs `json:"offChainReporting2OracleSpec"`
+			BootstrapSpec     *jobOCR2Attrs `json:"bootstrapSpec"`
+			PipelineSpec      struct {
+				DotDAGSource string `json:"dotDagSource"`
+			} `json:"pipelineSpec"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// jobOCR2Attrs are oracle spec fields of OCR2 and bootstrap jobs, bootstrap jobs only have the contract and relay ones
+type jobOCR2Attrs struct {
+	ContractID                        string         `json:"contractID"`
+	Relay                             string         `json:"relay"`
+	RelayConfig                       map[string]any `json:"relayConfig"`
+	PluginType                        string         `json:"pluginType"`
+	PluginConfig                      map[string]any `json:"pluginConfig"`
+	P2PV2Bootstrappers                []string       `json:"p2pv2Bootstrappers"`
+	OCRKeyBundleID                    string         `json:"ocrKeyBundleID"`
+	TransmitterID                     string         `json:"transmitterID"`
+	MonitoringEndpoint                string         `json:"monitoringEndpoint"`
+	BlockchainTimeout                 string         `json:"blockchainTimeout"`
+	ContractConfigTrackerPollInterval string         `json:"contractConfigTrackerPollInterval"`
+	ContractConfigConfirmations       uint16         `json:"contractConfigConfirmations"`
+	FeedID                            *common.Hash   `json:"feedID"`
+	CaptureEATelemetry                bool           `json:"captureEATelemetry"`
+}
+
+// spec rebuilds the job spec the job was created from, only OCR2 and bootstrap jobs are supported
+func (r *jobResource) spec() (*TaskJobSpec, error) {
+	a := r.Data.Attributes
+	attrs := a.OCR2Spec
+	if a.Type == JobTypeBootstrap {
+		attrs = a.BootstrapSpec
+	}
+	if attrs == nil || (a.Type != JobTypeOCR2 && a.Type != JobTypeBootstrap) {
+		return nil, fmt.Errorf("job %s has type %q, only %s and %s jobs can be disabled", r.Data.ID, a.Type, JobTypeOCR2, JobTypeBootstrap)
+	}
+	var blockchainTimeout, pollInterval Interval
+	if attrs.BlockchainTimeout != "" {
+		if err := blockchainTimeout.UnmarshalText([]byte(attrs.BlockchainTimeout)); err != nil {
+			return nil, fmt.Errorf("could not parse blockchainTimeout of job %s: %w", r.Data.ID, err)
+		}
+	}
+	if attrs.ContractConfigTrackerPollInterval != "" {
+		if err := pollInterval.UnmarshalText([]byte(attrs.ContractConfigTrackerPollInterval)); err != nil {
+			return nil, fmt.Errorf("could not parse contractConfigTrackerPollInterval of job %s: %w", r.Data.ID, err)
+		}
+	}
+	maxTaskDuration := a.MaxTaskDuration
+	// node reports unset duration as 0s, spec template skips empty durations
+	if maxTaskDuration == "0s" {
+		maxTaskDuration = ""
+	}
+	pluginConfig, err := pluginConfigValues(attrs.PluginConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not render plugin config of job %s: %w", r.Data.ID, err)
+	}
+	return &TaskJobSpec{
+		Name:              a.Name,
+		JobType:           a.Type,
+		MaxTaskDuration:   maxTaskDuration,
+		ObservationSource: tomlEscape(strings.TrimSuffix(a.PipelineSpec.DotDAGSource, "\n")),
+		ForwardingAllowed: a.ForwardingAllowed,
+		OCR2OracleSpec: OracleSpec{
+			ContractID:                        attrs.ContractID,
+			Relay:                             attrs.Relay,
+			RelayConfig:                       attrs.RelayConfig,
+			PluginType:                        types.OCR2PluginType(attrs.PluginType),
+			PluginConfig:                      pluginConfig,
+			P2PV2Bootstrappers:                pq.StringArray(attrs.P2PV2Bootstrappers),
+			OCRKeyBundleID:                    null.NewString(attrs.OCRKeyBundleID, attrs.OCRKeyBundleID != ""),
+			TransmitterID:                     null.NewString(attrs.TransmitterID, attrs.TransmitterID != ""),
+			MonitoringEndpoint:                null.NewString(attrs.MonitoringEndpoint, attrs.MonitoringEndpoint != ""),
+			BlockchainTimeout:                 blockchainTimeout,
+			ContractConfigTrackerPollInterval: pollInterval,
+			ContractConfigConfirmations:       attrs.ContractConfigConfirmations,
+			FeedID:                            attrs.FeedID,
+			CaptureEATelemetry:                attrs.CaptureEATelemetry,
+		},
+	}, nil
+}
+
+// tomlEscape escapes backslashes of a decoded string, spec template writes pipelines into TOML basic strings verbatim,
+// ex.: bridge requestData quotes are stored as \" and must be written as \\"
+func tomlEscape(s string) string {
+	return strings.ReplaceAll(s, `\`, `\\`)
+}
+
+// pluginConfigValues renders decoded plugin config values back to TOML, spec template writes them verbatim,
+// strings are multiline, ex.: juelsPerFeeCoinSource pipeline
+func pluginConfigValues(cfg map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(cfg))
+	for k, v := range cfg {
+		if s, ok := v.(string); ok {
+			out[k] = fmt.Sprintf("\"\"\"%s\"\"\"", tomlEscape(s))
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode %s: %w", k, err)
+		}
+		out[k] = string(b)
+	}
+	return out, nil
+}
+
+// readJob reads the job from the node and rebuilds its spec
+func readJob(ctx context.Context, node *clclient.ChainlinkClient, id string) (*TaskJobSpec, error) {
+	var res jobResource
+	resp, err := node.APIClient.R().
+		SetContext(ctx).
+		SetPathParam("id", id).
+		SetResult(&res).
+		Get("/v2/jobs/{id}")
+	if err != nil {
+		return nil, fmt.Errorf("reading job %s on node %s have failed: %w", id, node.URL(), err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("node %s refused to read job %s (status %d): %s", node.URL(), id, resp.StatusCode(), resp.String())
+	}
+	return res.spec()
+}
+
+// DisableJob stops the job on the node the way an operator pausing it would, node API has no pause, so the job
+// spec is read back, kept and the job is deleted. EnableJob re-creates it, other nodes keep running their jobs
+func DisableJob(ctx context.Context, node *clclient.ChainlinkClient, jobID string) error {
+	spec, err := readJob(ctx, node, jobID)
+	if err != nil {
+		return err
+	}
+	if err := DeleteJob(ctx, node, jobID); err != nil {
+		return err
+	}
+	disabledJobs.Lock()
+	disabledJobs.specs[disabledJobKey(node.URL(), jobID)] = spec
+	disabledJobs.Unlock()
+	ctxLogger(ctx).Info().Str("Node", node.URL()).Str("JobID", jobID).Str("Name", spec.Name).Msg("Job disabled")
+	return nil
+}
+
+// EnableJob re-creates a job disabled by DisableJob and returns its new ID, node assigns a new ID to every created job.
+// If node rejects the spec the job stays disabled and its response is returned verbatim
+func EnableJob(ctx context.Context, node *clclient.ChainlinkClient, jobID string) (string, error) {
+	key := disabledJobKey(node.URL(), jobID)
+	disabledJobs.Lock()
+	spec, ok := disabledJobs.specs[key]
+	disabledJobs.Unlock()
+	if !ok {
+		return "", fmt.Errorf("job %s was not disabled on node %s", jobID, node.URL())
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	job, resp, err := node.CreateJob(spec)
+	if err != nil {
+		return "", fmt.Errorf("re-creating job %s on node %s have failed: %w", jobID, node.URL(), err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return "", fmt.Errorf("node %s rejected job %s (status %d): %s", node.URL(), jobID, resp.StatusCode(), resp.String())
+	}
+	disabledJobs.Lock()
+	delete(disabledJobs.specs, key)
+	disabledJobs.Unlock()
+	ctxLogger(ctx).Info().Str("Node", node.URL()).Str("JobID", jobID).Str("NewJobID", job.Data.ID).Msg("Job enabled")
+	return job.Data.ID, nil
+}
+
+// TransmittersReader reads transmitters of the current aggregator config, ex.: *ocr2aggregator.OCR2Aggregator
+type TransmittersReader interface {
+	GetTransmitters(opts *bind.CallOpts) ([]common.Address, error)
+}
+
+// OracleIndex returns the oracle index of a node transmitting from transmitter in the current aggregator config,
+// forwarders are resolved, so it's the index node observations are reported under
+func (d *DeployedContracts) OracleIndex(ctx context.Context, r TransmittersReader, transmitter common.Address) (int, error) {
+	transmitters, err := r.GetTransmitters(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return 0, fmt.Errorf("could not read aggregator transmitters: %w", err)
+	}
+	onchain := d.onchainTransmitter(transmitter)
+	idx := slices.Index(transmitters, onchain)
+	if idx < 0 {
+		return 0, fmt.Errorf("transmitter %s is not in the aggregator config", onchain.Hex())
+	}
+	return idx, nil
+}
+
+// ObservationIncluded reports whether a report transmitted since fromBlock includes an observation of oracle,
+// a node participates in rounds again once its observations are included
+func ObservationIncluded(ctx context.Context, o2 *ocr2aggregator.OCR2Aggregator, fromBlock uint64, oracle int) (bool, error) {
+	it, err := o2.FilterNewTransmission(&bind.FilterOpts{Context: ctx, Start: fromBlock}, nil)
+	if err != nil {
+		return false, fmt.Errorf("could not filter NewTransmission events: %w", err)
+	}
+	defer it.Close()
+	for it.Next() {
+		if observedBy(it.Event.Observers, oracle) {
+			return true, nil
+		}
+	}
+	if err := it.Error(); err != nil {
+		return false, fmt.Errorf("could not read NewTransmission events: %w", err)
+	}
+	return false, nil
+}
+
+// observedBy reports whether observers of a report, one oracle index per observation, include oracle
+func observedBy(observers []byte, oracle int) bool {
+	return oracle >= 0 && oracle < 256 && slices.Contains(observers, byte(oracle))
+}
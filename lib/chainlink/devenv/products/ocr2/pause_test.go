@@ -0,0 +1,131 @@
+package ocr2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// nodeJobResource returns a job resource the way node presents a job created from a golden spec,
+// node stores decoded TOML values, ex.: pipelines with unescaped quotes
+func nodeJobResource(t *testing.T, golden, specKey string) *jobResource {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", golden))
+	require.NoError(t, err)
+	var spec map[string]any
+	require.NoError(t, toml.Unmarshal(b, &spec))
+	attrs := map[string]any{
+		"name":              spec["name"],
+		"type":              spec["type"],
+		"maxTaskDuration":   "0s",
+		"forwardingAllowed": spec["forwardingAllowed"],
+		"pipelineSpec":      map[string]any{"dotDagSource": spec["observationSource"]},
+		specKey:             spec,
+	}
+	if d, ok := spec["maxTaskDuration"]; ok {
+		attrs["maxTaskDuration"] = d
+	}
+	body, err := json.Marshal(map[string]any{"data": map[string]any{"id": "1", "attributes": attrs}})
+	require.NoError(t, err)
+	var res jobResource
+	require.NoError(t, json.Unmarshal(body, &res))
+	return &res
+}
+
+func TestJobResourceSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		golden  string
+		specKey string
+	}{
+		{name: "worker", golden: "render_worker.toml", specKey: "offChainReporting2OracleSpec"},
+		{name: "bootstrap", golden: "render_bootstrap.toml", specKey: "bootstrapSpec"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := nodeJobResource(t, tt.golden, tt.specKey).spec()
+			require.NoError(t, err)
+			got, err := spec.String()
+			require.NoError(t, err)
+			// re-created job must be the same job the node had
+			requireGolden(t, tt.golden, got)
+		})
+	}
+
+	t.Run("unsupported type", func(t *testing.T) {
+		res := &jobResource{}
+		res.Data.ID = "2"
+		res.Data.Attributes.Type = "webhook"
+		_, err := res.spec()
+		require.ErrorContains(t, err, `job 2 has type "webhook"`)
+	})
+	t.Run("bad interval", func(t *testing.T) {
+		res := nodeJobResource(t, "render_worker.toml", "offChainReporting2OracleSpec")
+		res.Data.Attributes.OCR2Spec.ContractConfigTrackerPollInterval = "often"
+		_, err := res.spec()
+		require.ErrorContains(t, err, "could not parse contractConfigTrackerPollInterval of job 1")
+	})
+}
+
+func TestPluginConfigValues(t *testing.T) {
+	got, err := pluginConfigValues(map[string]any{
+		"juelsPerFeeCoinSource": `fetch [type=bridge requestData="{\"a\":1}"];`,
+		"allowedFaults":         float64(1),
+		"flag":                  true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"juelsPerFeeCoinSource": `"""fetch [type=bridge requestData="{\\"a\\":1}"];"""`,
+		"allowedFaults":         "1",
+		"flag":                  "true",
+	}, got)
+}
+
+type mockTransmitters struct {
+	addrs []common.Address
+	err   error
+}
+
+func (m *mockTransmitters) GetTransmitters(_ *bind.CallOpts) ([]common.Address, error) {
+	return m.addrs, m.err
+}
+
+func TestOracleIndex(t *testing.T) {
+	ctx := context.Background()
+	a := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	b := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	fwd := common.HexToAddress("0x00000000000000000000000000000000000000f2")
+
+	var none *DeployedContracts
+	idx, err := none.OracleIndex(ctx, &mockTransmitters{addrs: []common.Address{a, b}}, b)
+	require.NoError(t, err)
+	require.Equal(t, 1, idx)
+
+	forwarded := &DeployedContracts{Forwarders: map[string]string{b.Hex(): fwd.Hex()}}
+	idx, err = forwarded.OracleIndex(ctx, &mockTransmitters{addrs: []common.Address{a, fwd}}, b)
+	require.NoError(t, err)
+	require.Equal(t, 1, idx)
+
+	_, err = forwarded.OracleIndex(ctx, &mockTransmitters{addrs: []common.Address{a, b}}, b)
+	require.ErrorContains(t, err, "is not in the aggregator config")
+
+	_, err = none.OracleIndex(ctx, &mockTransmitters{err: errors.New("rpc down")}, a)
+	require.ErrorContains(t, err, "rpc down")
+}
+
+func TestObservedBy(t *testing.T) {
+	observers := []byte{0, 2, 3}
+	require.True(t, observedBy(observers, 2))
+	require.False(t, observedBy(observers, 1))
+	require.False(t, observedBy(observers, -1))
+	require.False(t, observedBy(observers, 256))
+	require.False(t, observedBy(nil, 0))
+}
@@ -0,0 +1,87 @@
+package ocr2
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+)
+
+// ObservationSource configures error handling of the generated job pipeline
+type ObservationSource struct {
+	// Sources is how many EA bridge fetches feed the median, 1 keeps a single bridge pipeline
+	Sources int `toml:"sources"`
+	// AllowedFaults is how many sources can fail before the observation is dropped
+	AllowedFaults int `toml:"allowed_faults"`
+	// LaxParse returns nil instead of an error when EA response has no result
+	LaxParse bool `toml:"lax_parse"`
+}
+
+// validate checks observation source settings, nil settings are valid
+func (o *ObservationSource) validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.Sources < 1 {
+		return fmt.Errorf("observation_source.sources must be at least 1, got %d", o.Sources)
+	}
+	if o.AllowedFaults < 0 || o.AllowedFaults >= o.Sources {
+		return fmt.Errorf("observation_source.allowed_faults must be in range 0-%d, got %d", o.Sources-1, o.AllowedFaults)
+	}
+	return nil
+}
+
+// bridgeHeadersAttr renders bridge task headers attribute, ex.: headers="[\\"Authorization\\",\\"Bearer token\\"]",
+// keys are sorted so the same headers always produce the same job spec
+func bridgeHeadersAttr(headers map[string]string) (string, error) {
+	if len(headers) == 0 {
+		return "", nil
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(headers)*2)
+	for _, k := range keys {
+		if k == "" {
+			return "", errors.New("bridge_headers can't have an empty header name")
+		}
+		for _, v := range []string{k, headers[k]} {
+			if strings.ContainsAny(v, "\"\\\n") {
+				return "", fmt.Errorf("bridge_headers can't contain quotes, backslashes or newlines, got %q", v)
+			}
+			pairs = append(pairs, fmt.Sprintf("\\\\\"%s\\\\\"", v))
+		}
+	}
+	return fmt.Sprintf(" headers=\"[%s]\"", strings.Join(pairs, ",")), nil
+}
+
+// observationSourceSpec renders pipeline DSL for EA bridge, default single bridge pipeline is used if no error handling or headers are set
+func observationSourceSpec(bridge *clclient.BridgeTypeAttributes, o *ObservationSource, headers map[string]string) (string, error) {
+	if err := o.validate(); err != nil {
+		return "", err
+	}
+	headersAttr, err := bridgeHeadersAttr(headers)
+	if err != nil {
+		return "", err
+	}
+	if o == nil || (o.Sources == 1 && o.AllowedFaults == 0 && !o.LaxParse) {
+		if headersAttr == "" {
+			return clclient.ObservationSourceSpecBridge(bridge), nil
+		}
+		// same pipeline as clclient.ObservationSourceSpecBridge, it has no way to set headers
+		return fmt.Sprintf("fetch [type=bridge name=\"%s\" requestData=\"%s\"%s];\nparse [type=jsonparse path=\"data,result\"];\nfetch -> parse;",
+			bridge.Name, bridge.RequestData, headersAttr), nil
+	}
+	var sb strings.Builder
+	for i := 1; i <= o.Sources; i++ {
+		fmt.Fprintf(&sb, "ds%d [type=bridge name=\"%s\" requestData=\"{\\\\\"data\\\\\":{}}\"%s];\n", i, bridge.Name, headersAttr)
+		fmt.Fprintf(&sb, "ds%d_parse [type=jsonparse path=\"data,result\" lax=%t];\n", i, o.LaxParse)
+		fmt.Fprintf(&sb, "ds%d -> ds%d_parse -> answer;\n", i, i)
+	}
+	fmt.Fprintf(&sb, "answer [type=median allowedFaults=%d];", o.AllowedFaults)
+	return sb.String(), nil
+}
@@ -0,0 +1,52 @@
+package ocr2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+)
+
+func TestObservationSourceSpec(t *testing.T) {
+	bridge := &clclient.BridgeTypeAttributes{Name: "ea"}
+
+	spec, err := observationSourceSpec(bridge, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, clclient.ObservationSourceSpecBridge(bridge), spec)
+
+	spec, err = observationSourceSpec(bridge, &ObservationSource{Sources: 2, AllowedFaults: 1, LaxParse: true}, nil)
+	require.NoError(t, err)
+	require.Equal(t, `ds1 [type=bridge name="ea" requestData="{\\"data\\":{}}"];
+ds1_parse [type=jsonparse path="data,result" lax=true];
+ds1 -> ds1_parse -> answer;
+ds2 [type=bridge name="ea" requestData="{\\"data\\":{}}"];
+ds2_parse [type=jsonparse path="data,result" lax=true];
+ds2 -> ds2_parse -> answer;
+answer [type=median allowedFaults=1];`, spec)
+
+	_, err = observationSourceSpec(bridge, &ObservationSource{Sources: 0}, nil)
+	require.Error(t, err)
+	_, err = observationSourceSpec(bridge, &ObservationSource{Sources: 2, AllowedFaults: 2}, nil)
+	require.Error(t, err)
+}
+
+func TestObservationSourceSpecHeaders(t *testing.T) {
+	bridge := &clclient.BridgeTypeAttributes{Name: "ea"}
+	headers := map[string]string{"X-Api-Key": "key", "Authorization": "Bearer token"}
+
+	spec, err := observationSourceSpec(bridge, nil, headers)
+	require.NoError(t, err)
+	require.Equal(t, `fetch [type=bridge name="ea" requestData="" headers="[\\"Authorization\\",\\"Bearer token\\",\\"X-Api-Key\\",\\"key\\"]"];
+parse [type=jsonparse path="data,result"];
+fetch -> parse;`, spec)
+
+	spec, err = observationSourceSpec(bridge, &ObservationSource{Sources: 2, AllowedFaults: 1}, map[string]string{"Authorization": "Bearer token"})
+	require.NoError(t, err)
+	require.Contains(t, spec, `ds2 [type=bridge name="ea" requestData="{\\"data\\":{}}" headers="[\\"Authorization\\",\\"Bearer token\\"]"];`)
+
+	_, err = observationSourceSpec(bridge, nil, map[string]string{"Authorization": `Bearer "token"`})
+	require.Error(t, err)
+	_, err = observationSourceSpec(bridge, nil, map[string]string{"": "value"})
+	require.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smartcontractkit/libocr/offchainreporting2/reportingplugin/median"
+
+	"github.com/smartcontractkit/chainlink-common/pkg/types"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+)
+
+const (
+	// PluginTypeMedian is the default OCR2 reporting plugin
+	PluginTypeMedian = "median"
+)
+
+// PluginConfigCodec encodes plugin-specific onchain and offchain configs used in SetConfig
+type PluginConfigCodec interface {
+	// OnchainConfig returns encoded onchain config
+	OnchainConfig(ctx context.Context) ([]byte, error)
+	// OffchainConfig returns encoded reporting plugin offchain config
+	OffchainConfig() ([]byte, error)
+}
+
+// NewPluginConfigCodec selects a config codec by plugin type, median is used if plugin type is not set
+func NewPluginConfigCodec(o *OCR2) (PluginConfigCodec, error) {
+	switch o.pluginType() {
+	case PluginTypeMedian:
+		return &MedianConfigCodec{OCR2: o}, nil
+	default:
+		return nil, fmt.Errorf("unknown OCR2 plugin type: %s", o.PluginType)
+	}
+}
+
+// pluginType returns configured plugin type, median is used if plugin type is not set
+func (o *OCR2) pluginType() types.OCR2PluginType {
+	return types.OCR2PluginType(defaults.Coalesce(o.PluginType, PluginTypeMedian))
+}
+
+// MedianConfigCodec encodes median plugin configs
+type MedianConfigCodec struct {
+	OCR2 *OCR2
+}
+
+func (c *MedianConfigCodec) OnchainConfig(ctx context.Context) ([]byte, error) {
+	return median.StandardOnchainConfigCodec{}.Encode(ctx, median.OnchainConfig{Min: c.OCR2.OCR2.MinimumAnswer, Max: c.OCR2.OCR2.MaximumAnswer})
+}
+
+func (c *MedianConfigCodec) OffchainConfig() ([]byte, error) {
+	mc := c.OCR2.OCR2MedianOffchainConfig
+	return median.OffchainConfig{
+		AlphaAcceptInfinite: mc.AlphaAcceptInfinite,
+		AlphaReportInfinite: mc.AlphaReportInfinite,
+		AlphaReportPPB:      mc.AlphaReportPPB,
+		AlphaAcceptPPB:      mc.AlphaAcceptPPB,
+		DeltaC:              time.Duration(mc.DeltaCSec) * time.Second,
+	}.Encode(), nil
+}
+
+// Validate checks median offchain config for combinations that produce a feed that looks configured but misbehaves.
+// A report is issued if the answer deviates by at least AlphaReportPPB parts per billion or DeltaC has passed,
+// AlphaReportInfinite disables the deviation check, so the feed reports on DeltaC heartbeat only and AlphaReportPPB is ignored.
+// AlphaReportPPB = 0 without AlphaReportInfinite means every round deviates, so a report is issued every round.
+// AlphaAcceptPPB and AlphaAcceptInfinite work the same for accepting a new report over a pending one
+func (mc *MedianOffchainConfig) Validate() error {
+	if mc == nil {
+		return errors.New("no [ocr2.ocr2_median_offchain_config] found")
+	}
+	if mc.DeltaCSec < 0 {
+		return fmt.Errorf("median delta_sec must be non-negative, got %d", mc.DeltaCSec)
+	}
+	if !mc.AlphaReportInfinite && mc.AlphaReportPPB == 0 {
+		return errors.New("median alpha_report_ppb = 0 reports every round regardless of deviation, set alpha_report_ppb or alpha_report_infinite")
+	}
+	if !mc.AlphaAcceptInfinite && mc.AlphaAcceptPPB == 0 {
+		return errors.New("median alpha_accept_ppb = 0 accepts every new report over a pending one, set alpha_accept_ppb or alpha_accept_infinite")
+	}
+	if mc.AlphaReportInfinite && mc.DeltaCSec == 0 {
+		return errors.New("median alpha_report_infinite with delta_sec = 0 reports every round, set delta_sec to the heartbeat")
+	}
+	for _, w := range mc.warnings() {
+		L.Warn().Msg(w)
+	}
+	return nil
+}
+
+// warnings returns settings that are ignored and likely a mistake
+func (mc *MedianOffchainConfig) warnings() []string {
+	var warnings []string
+	if mc.AlphaReportInfinite && mc.AlphaReportPPB != 0 {
+		warnings = append(warnings, fmt.Sprintf("median alpha_report_infinite is set, alpha_report_ppb %d is ignored, the feed only reports every delta_sec", mc.AlphaReportPPB))
+	}
+	if mc.AlphaAcceptInfinite && mc.AlphaAcceptPPB != 0 {
+		warnings = append(warnings, fmt.Sprintf("median alpha_accept_infinite is set, alpha_accept_ppb %d is ignored", mc.AlphaAcceptPPB))
+	}
+	return warnings
+}
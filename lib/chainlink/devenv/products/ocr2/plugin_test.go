@@ -0,0 +1,75 @@
+package ocr2
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/libocr/offchainreporting2/reportingplugin/median"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMedianConfigCodec(t *testing.T) {
+	o := &OCR2{
+		OCR2: &OCRv2OffChainOptions{
+			MinimumAnswer: big.NewInt(1),
+			MaximumAnswer: big.NewInt(50000000000000000),
+		},
+		OCR2MedianOffchainConfig: &MedianOffchainConfig{
+			AlphaReportPPB: 1,
+			AlphaAcceptPPB: 1,
+			DeltaCSec:      1800,
+		},
+	}
+	codec, err := NewPluginConfigCodec(o)
+	require.NoError(t, err)
+	require.IsType(t, &MedianConfigCodec{}, codec)
+
+	// encoding must stay the same as direct libocr median encoding
+	offchain, err := codec.OffchainConfig()
+	require.NoError(t, err)
+	require.Equal(t, median.OffchainConfig{
+		AlphaReportPPB: 1,
+		AlphaAcceptPPB: 1,
+		DeltaC:         1800 * time.Second,
+	}.Encode(), offchain)
+
+	onchain, err := codec.OnchainConfig(context.Background())
+	require.NoError(t, err)
+	expected, err := median.StandardOnchainConfigCodec{}.Encode(context.Background(), median.OnchainConfig{Min: big.NewInt(1), Max: big.NewInt(50000000000000000)})
+	require.NoError(t, err)
+	require.Equal(t, expected, onchain)
+
+	_, err = NewPluginConfigCodec(&OCR2{PluginType: "unknown"})
+	require.Error(t, err)
+}
+
+func TestMedianOffchainConfigValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		mc           *MedianOffchainConfig
+		wantErr      string
+		wantWarnings int
+	}{
+		{name: "deviation and heartbeat", mc: &MedianOffchainConfig{AlphaReportPPB: 1, AlphaAcceptPPB: 1, DeltaCSec: 1800}},
+		{name: "heartbeat only", mc: &MedianOffchainConfig{AlphaReportInfinite: true, AlphaAcceptPPB: 1, DeltaCSec: 60}},
+		{name: "ignored report ppb", mc: &MedianOffchainConfig{AlphaReportInfinite: true, AlphaReportPPB: 5e6, AlphaAcceptInfinite: true, AlphaAcceptPPB: 1, DeltaCSec: 60}, wantWarnings: 2},
+		{name: "no config", wantErr: "no [ocr2.ocr2_median_offchain_config] found"},
+		{name: "zero report ppb", mc: &MedianOffchainConfig{AlphaAcceptPPB: 1, DeltaCSec: 1800}, wantErr: "alpha_report_ppb = 0 reports every round"},
+		{name: "zero accept ppb", mc: &MedianOffchainConfig{AlphaReportPPB: 1, DeltaCSec: 1800}, wantErr: "alpha_accept_ppb = 0 accepts every new report"},
+		{name: "no heartbeat", mc: &MedianOffchainConfig{AlphaReportInfinite: true, AlphaAcceptPPB: 1}, wantErr: "alpha_report_infinite with delta_sec = 0"},
+		{name: "negative delta", mc: &MedianOffchainConfig{AlphaReportPPB: 1, AlphaAcceptPPB: 1, DeltaCSec: -1}, wantErr: "delta_sec must be non-negative"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.mc.Validate()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, tc.mc.warnings(), tc.wantWarnings)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package ocr2
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+)
+
+// DefaultRelay is used if [ocr2.jobs.relay] name is not set
+const DefaultRelay = "evm"
+
+// KnownRelays are relay (chain adapter) names CL nodes support in OCR2 job specs
+var KnownRelays = []string{"evm", "solana", "starknet", "cosmos", "aptos", "tron", "dummy"}
+
+// Relay selects the relay jobs track the contract and transmit through, ex.: [ocr2.jobs.relay],
+// environment chain with evm relay is used if it's not set
+type Relay struct {
+	// Name is the relay name, one of KnownRelays, evm if unset
+	Name string `toml:"name"`
+	// ChainID is the chain the relay connects to, environment chain is used if unset, ex.: a job writing to another chain
+	ChainID string `toml:"chain_id"`
+	// Config is added to the job relayConfig, ex.: fromBlock, chainID is set by ChainID
+	Config map[string]any `toml:"config"`
+}
+
+// validate checks relay name is known and config doesn't override chain ID, nil relay is valid
+func (r *Relay) validate() error {
+	if r == nil {
+		return nil
+	}
+	if name := defaults.Coalesce(r.Name, DefaultRelay); !slices.Contains(KnownRelays, name) {
+		return fmt.Errorf("unknown relay %s, expected one of %v", name, KnownRelays)
+	}
+	if _, ok := r.Config["chainID"]; ok {
+		return fmt.Errorf("relay config can't set chainID, use chain_id instead")
+	}
+	return nil
+}
+
+// relaySpec returns relay name and relay config of a job, chainID is the environment chain used if relay chain_id is not set
+func (j *Jobs) relaySpec(chainID string) (string, map[string]any, error) {
+	var r *Relay
+	if j != nil {
+		r = j.Relay
+	}
+	if err := r.validate(); err != nil {
+		return "", nil, err
+	}
+	if r == nil {
+		return DefaultRelay, map[string]any{"chainID": chainID}, nil
+	}
+	cfg := maps.Clone(r.Config)
+	if cfg == nil {
+		cfg = make(map[string]any, 1)
+	}
+	cfg["chainID"] = defaults.Coalesce(r.ChainID, chainID)
+	return defaults.Coalesce(r.Name, DefaultRelay), cfg, nil
+}
@@ -0,0 +1,53 @@
+package ocr2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelaySpec(t *testing.T) {
+	tests := []struct {
+		name        string
+		jobs        *Jobs
+		relay       string
+		relayConfig map[string]any
+		err         string
+	}{
+		{name: "no jobs", relay: "evm", relayConfig: map[string]any{"chainID": "1337"}},
+		{name: "no relay", jobs: &Jobs{}, relay: "evm", relayConfig: map[string]any{"chainID": "1337"}},
+		{
+			name:        "relay chain and config",
+			jobs:        &Jobs{Relay: &Relay{Name: "evm", ChainID: "2337", Config: map[string]any{"fromBlock": 10}}},
+			relay:       "evm",
+			relayConfig: map[string]any{"chainID": "2337", "fromBlock": 10},
+		},
+		{
+			name:        "relay name only",
+			jobs:        &Jobs{Relay: &Relay{Name: "dummy"}},
+			relay:       "dummy",
+			relayConfig: map[string]any{"chainID": "1337"},
+		},
+		{name: "unknown relay", jobs: &Jobs{Relay: &Relay{Name: "bitcoin"}}, err: "unknown relay bitcoin"},
+		{name: "chainID in config", jobs: &Jobs{Relay: &Relay{Config: map[string]any{"chainID": "1"}}}, err: "use chain_id instead"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			relay, relayConfig, err := tc.jobs.relaySpec("1337")
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.relay, relay)
+			require.Equal(t, tc.relayConfig, relayConfig)
+		})
+	}
+}
+
+func TestRelaySpecDoesNotMutateConfig(t *testing.T) {
+	jobs := &Jobs{Relay: &Relay{Config: map[string]any{"fromBlock": 10}}}
+	_, _, err := jobs.relaySpec("1337")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"fromBlock": 10}, jobs.Relay.Config)
+}
@@ -0,0 +1,154 @@
+package ocr2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// EnvVarRecordRPC is a file path to record all JSON-RPC traffic to, ex.: RECORD_RPC=rpc-trace.jsonl
+	EnvVarRecordRPC = "RECORD_RPC"
+	redactedValue   = "<redacted>"
+)
+
+var (
+	recorderMu      sync.Mutex
+	recorderFile    *os.File
+	recorderProxies = make(map[string]string)
+)
+
+// RPCRecord is a single recorded JSON-RPC exchange written as a JSON line
+type RPCRecord struct {
+	Time     time.Time       `json:"time"`
+	Target   string          `json:"target"`
+	Method   string          `json:"method,omitempty"`
+	Status   int             `json:"status,omitempty"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// RecordRPCURL returns rpcURL unchanged if RECORD_RPC is not set, otherwise it starts a local recording proxy
+// for rpcURL and returns the proxy URL. WS URLs are recorded through their HTTP equivalent on the same host and port.
+func RecordRPCURL(rpcURL string) (string, error) {
+	path := os.Getenv(EnvVarRecordRPC)
+	if path == "" {
+		return rpcURL, nil
+	}
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	if proxyURL, ok := recorderProxies[rpcURL]; ok {
+		return proxyURL, nil
+	}
+	target, err := url.Parse(rpcURL)
+	if err != nil {
+		return "", fmt.Errorf("could not parse RPC URL: %w", err)
+	}
+	switch target.Scheme {
+	case "ws":
+		target.Scheme = "http"
+	case "wss":
+		target.Scheme = "https"
+	}
+	if recorderFile == nil {
+		recorderFile, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return "", fmt.Errorf("could not open RPC record file: %w", err)
+		}
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("could not start RPC recording proxy: %w", err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.Transport = &recordingTransport{target: target.String(), base: http.DefaultTransport}
+	go func() {
+		//nolint:gosec // local debug proxy, no timeouts required
+		_ = http.Serve(ln, proxy)
+	}()
+	proxyURL := "http://" + ln.Addr().String()
+	recorderProxies[rpcURL] = proxyURL
+	L.Info().Str("Target", rpcURL).Str("Proxy", proxyURL).Str("File", path).Msg("Recording RPC traffic")
+	return proxyURL, nil
+}
+
+type recordingTransport struct {
+	target string
+	base   http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := &RPCRecord{Time: time.Now(), Target: t.target}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		rec.Request = redact(body)
+		var m struct {
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(body, &m) == nil {
+			rec.Method = m.Method
+		}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		rec.Error = err.Error()
+		writeRecord(rec)
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	rec.Status = resp.StatusCode
+	rec.Response = redact(body)
+	writeRecord(rec)
+	return resp, nil
+}
+
+// redact removes the network private key from recorded payloads, returns valid JSON
+func redact(data []byte) json.RawMessage {
+	pk := strings.ToLower(strings.TrimPrefix(getNetworkPrivateKey(), "0x"))
+	s := string(data)
+	if pk != "" {
+		lower := strings.ToLower(s)
+		for idx := strings.Index(lower, pk); idx != -1; idx = strings.Index(lower, pk) {
+			s = s[:idx] + redactedValue + s[idx+len(pk):]
+			lower = lower[:idx] + redactedValue + lower[idx+len(pk):]
+		}
+	}
+	if !json.Valid([]byte(s)) {
+		raw, _ := json.Marshal(s)
+		return raw
+	}
+	return json.RawMessage(s)
+}
+
+func writeRecord(rec *RPCRecord) {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		L.Warn().Err(err).Msg("Failed to marshal RPC record")
+		return
+	}
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	if _, err := recorderFile.Write(append(line, '\n')); err != nil {
+		L.Warn().Err(err).Msg("Failed to write RPC record")
+	}
+}
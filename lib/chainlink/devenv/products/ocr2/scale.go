@@ -0,0 +1,144 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
+
+	nodeset "github.com/smartcontractkit/chainlink-testing-framework/framework/components/simple_node_set"
+)
+
+const (
+	// FaultyOracles is the max number of faulty oracles (F) the DON is configured to tolerate
+	FaultyOracles = 1
+	// nodeReadyTimeout is how long we wait for restarted nodes to accept API requests
+	nodeReadyTimeout = 2 * time.Minute
+)
+
+// MinOracles returns the minimal DON size for OCR2, 3F+1
+func MinOracles() int {
+	return 3*FaultyOracles + 1
+}
+
+// Scale changes the number of nodes participating in the DON to count.
+// Nodes above count are stopped, stopped nodes below count are started again, then a new config
+// with the updated signer/transmitter set is applied so the feed keeps working with the new membership.
+// Node set containers must already exist, create the node set with a larger "nodes" value to scale beyond it.
+func (m *Configurator) Scale(ctx context.Context, bc *blockchain.Input, ns *nodeset.Input, count int) error {
+	total := len(ns.Out.CLNodes)
+	if count < MinOracles() {
+		return fmt.Errorf("can't scale node set %s to %d nodes, OCR2 requires at least 3F+1=%d nodes with F=%d", ns.Name, count, MinOracles(), FaultyOracles)
+	}
+	if count > total {
+		return fmt.Errorf("can't scale node set %s to %d nodes, it has only %d nodes, recreate it with a larger \"nodes\" value", ns.Name, count, total)
+	}
+	if m.OCR2.DeployedContracts == nil {
+		return errors.New("no deployed OCR2 aggregator found, run the environment first")
+	}
+	if err := checkBlockchainOut(bc); err != nil {
+		return err
+	}
+	dc, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("could not create docker client: %w", err)
+	}
+	defer dc.Close()
+
+	c, _, _, err := ETHClient(ctx, bc.Out.Nodes[0].ExternalHTTPUrl, m.OCR2.GasSettings.FeeCapMultiplier, m.OCR2.GasSettings.TipCapMultiplier)
+	if err != nil {
+		return fmt.Errorf("could not create basic eth client: %w", err)
+	}
+	ocr2i, err := ocr2aggregator.NewOCR2Aggregator(common.HexToAddress(m.OCR2.DeployedContracts.OCRv2AggregatorAddr), c)
+	if err != nil {
+		return fmt.Errorf("could not connect to OCR2 aggregator: %w", err)
+	}
+	current, err := ocr2i.GetTransmitters(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("could not read current transmitters: %w", err)
+	}
+	ctxLogger(ctx).Info().
+		Str("NodeSet", ns.Name).
+		Int("From", len(current)).
+		Int("To", count).
+		Msg("Scaling DON")
+
+	// start nodes before applying the new config, so new members are online when config is picked up
+	for i := 0; i < count; i++ {
+		name := ns.Out.CLNodes[i].Node.ContainerName
+		if err := dc.ContainerStart(ctx, name, container.StartOptions{}); err != nil {
+			return fmt.Errorf("could not start node %d (%s): %w", i, name, err)
+		}
+	}
+	cl, err := waitNodesReady(ctx, ns, count)
+	if err != nil {
+		return err
+	}
+	if _, err := UpdateOCR2ConfigOffChainValues(ctx, bc, m.OCR2, ocr2i, cl, m.OCR2.OCR2SetConfig.withSecondUnits()); err != nil {
+		return fmt.Errorf("could not apply OCR2 config for %d nodes: %w", count, err)
+	}
+	// stop removed nodes only after they are excluded from the config, so the DON doesn't lose quorum
+	for i := count; i < total; i++ {
+		name := ns.Out.CLNodes[i].Node.ContainerName
+		ctxLogger(ctx).Info().Int("Idx", i).Str("Container", name).Msg("Stopping node")
+		if err := dc.ContainerStop(ctx, name, container.StopOptions{}); err != nil {
+			return fmt.Errorf("could not stop node %d (%s): %w", i, name, err)
+		}
+	}
+	ctxLogger(ctx).Info().Str("NodeSet", ns.Name).Int("Nodes", count).Msg("DON is scaled")
+	return nil
+}
+
+// waitNodesReady connects to the first count nodes, retrying until they accept API requests
+func waitNodesReady(ctx context.Context, ns *nodeset.Input, count int) ([]*clclient.ChainlinkClient, error) {
+	ctx, cancel := context.WithTimeout(ctx, nodeReadyTimeout)
+	defer cancel()
+	for {
+		cl, err := clclient.New(ns.Out.CLNodes[:count])
+		if err == nil {
+			return cl, nil
+		}
+		ctxLogger(ctx).Debug().Err(err).Msg("Nodes are not ready yet")
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("nodes are not ready after %s: %w", nodeReadyTimeout, err)
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// SetConfigOptions returns set config options the environment applied with durations in their final units, nil if there are none
+func (o *OCR2) SetConfigOptions() *OCRv2SetConfigOptions {
+	if o == nil || o.OCR2SetConfig == nil {
+		return nil
+	}
+	return o.OCR2SetConfig.withSecondUnits()
+}
+
+// withSecondUnits returns a copy of set config options with durations converted from seconds,
+// TOML config keeps them as plain numbers of seconds
+func (o *OCRv2SetConfigOptions) withSecondUnits() *OCRv2SetConfigOptions {
+	return &OCRv2SetConfigOptions{
+		RMax:                                    o.RMax,
+		DeltaProgress:                           o.DeltaProgress * time.Second,
+		DeltaResend:                             o.DeltaResend * time.Second,
+		DeltaRound:                              o.DeltaRound * time.Second,
+		DeltaGrace:                              o.DeltaGrace * time.Second,
+		DeltaStage:                              o.DeltaStage * time.Second,
+		MaxDurationInitialization:               o.MaxDurationInitialization * time.Second,
+		MaxDurationQuery:                        o.MaxDurationQuery * time.Second,
+		MaxDurationObservation:                  o.MaxDurationObservation * time.Second,
+		MaxDurationReport:                       o.MaxDurationReport * time.Second,
+		MaxDurationShouldAcceptFinalizedReport:  o.MaxDurationShouldAcceptFinalizedReport * time.Second,
+		MaxDurationShouldTransmitAcceptedReport: o.MaxDurationShouldTransmitAcceptedReport * time.Second,
+	}
+}
@@ -0,0 +1,114 @@
+package ocr2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// EnvVarSetConfigOverrides passes set config overrides from "cl test" flags to tests as JSON
+const EnvVarSetConfigOverrides = "OCR2_SET_CONFIG_OVERRIDES"
+
+// Validate checks set config options, durations must be in their final units, see withSecondUnits
+func (o *OCRv2SetConfigOptions) Validate() error {
+	if o.RMax == 0 {
+		return errors.New("r_max must be positive")
+	}
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"delta_progress", o.DeltaProgress},
+		{"delta_resend", o.DeltaResend},
+		{"delta_round", o.DeltaRound},
+		{"delta_grace", o.DeltaGrace},
+		{"delta_stage", o.DeltaStage},
+		{"max_duration_initialization", o.MaxDurationInitialization},
+		{"max_duration_query", o.MaxDurationQuery},
+		{"max_duration_observation", o.MaxDurationObservation},
+		{"max_duration_report", o.MaxDurationReport},
+		{"max_duration_should_accept_finalized_report", o.MaxDurationShouldAcceptFinalizedReport},
+		{"max_duration_should_transmit_accepted_report", o.MaxDurationShouldTransmitAcceptedReport},
+	} {
+		if d.value < 0 {
+			return fmt.Errorf("%s must be non-negative, got %s", d.name, d.value)
+		}
+	}
+	if o.DeltaProgress <= 0 {
+		return fmt.Errorf("delta_progress must be positive, got %s", o.DeltaProgress)
+	}
+	// a round must fit into delta_progress, otherwise leader is replaced before it can produce a report
+	if round := o.MaxDurationQuery + o.MaxDurationObservation + o.MaxDurationReport; round >= o.DeltaProgress {
+		return fmt.Errorf("max_duration_query + max_duration_observation + max_duration_report (%s) must be less than delta_progress (%s)", round, o.DeltaProgress)
+	}
+	return nil
+}
+
+// ObservationLatency returns fake EA latency margin past max_duration_observation, nodes drop observations that take it,
+// and margin under it, observations still make it into reports, ex.: 5s with 500ms margin is 5.5s and 4.5s
+func (o *OCRv2SetConfigOptions) ObservationLatency(margin time.Duration) (late, onTime time.Duration, err error) {
+	if o == nil {
+		return 0, 0, errors.New("no set config options to derive observation latency from")
+	}
+	if margin <= 0 || margin >= o.MaxDurationObservation {
+		return 0, 0, fmt.Errorf("latency margin must be positive and less than max_duration_observation (%s), got %s", o.MaxDurationObservation, margin)
+	}
+	return o.MaxDurationObservation + margin, o.MaxDurationObservation - margin, nil
+}
+
+// SetConfigOverrides change individual set config options, nil fields keep their values
+type SetConfigOverrides struct {
+	RMax          *uint8         `json:"rmax,omitempty"`
+	DeltaProgress *time.Duration `json:"delta_progress,omitempty"`
+	DeltaResend   *time.Duration `json:"delta_resend,omitempty"`
+	DeltaRound    *time.Duration `json:"delta_round,omitempty"`
+	DeltaGrace    *time.Duration `json:"delta_grace,omitempty"`
+	DeltaStage    *time.Duration `json:"delta_stage,omitempty"`
+}
+
+// Apply returns a validated copy of o with overrides applied, o is returned as is if there are no overrides
+func (s *SetConfigOverrides) Apply(o *OCRv2SetConfigOptions) (*OCRv2SetConfigOptions, error) {
+	if s == nil {
+		return o, nil
+	}
+	if o == nil {
+		return nil, errors.New("no set config options to override")
+	}
+	out := *o
+	if s.RMax != nil {
+		out.RMax = *s.RMax
+	}
+	for _, f := range []struct {
+		override *time.Duration
+		field    *time.Duration
+	}{
+		{s.DeltaProgress, &out.DeltaProgress},
+		{s.DeltaResend, &out.DeltaResend},
+		{s.DeltaRound, &out.DeltaRound},
+		{s.DeltaGrace, &out.DeltaGrace},
+		{s.DeltaStage, &out.DeltaStage},
+	} {
+		if f.override != nil {
+			*f.field = *f.override
+		}
+	}
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid set config overrides: %w", err)
+	}
+	return &out, nil
+}
+
+// SetConfigOverridesFromEnv reads overrides from EnvVarSetConfigOverrides, nil is returned if it's not set
+func SetConfigOverridesFromEnv() (*SetConfigOverrides, error) {
+	v := os.Getenv(EnvVarSetConfigOverrides)
+	if v == "" {
+		return nil, nil
+	}
+	s := &SetConfigOverrides{}
+	if err := json.Unmarshal([]byte(v), s); err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", EnvVarSetConfigOverrides, err)
+	}
+	return s, nil
+}
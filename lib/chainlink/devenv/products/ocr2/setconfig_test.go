@@ -0,0 +1,83 @@
+package ocr2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetConfigOverridesApply(t *testing.T) {
+	base := &OCRv2SetConfigOptions{
+		RMax:                   3,
+		DeltaProgress:          20 * time.Second,
+		DeltaResend:            20 * time.Second,
+		MaxDurationQuery:       5 * time.Second,
+		MaxDurationObservation: 5 * time.Second,
+		MaxDurationReport:      5 * time.Second,
+	}
+	rmax := uint8(5)
+	deltaRound := 10 * time.Second
+	shortProgress := 10 * time.Second
+	zero := uint8(0)
+
+	t.Run("no overrides keep options", func(t *testing.T) {
+		var s *SetConfigOverrides
+		got, err := s.Apply(base)
+		require.NoError(t, err)
+		require.Same(t, base, got)
+	})
+	t.Run("overrides are layered over options", func(t *testing.T) {
+		got, err := (&SetConfigOverrides{RMax: &rmax, DeltaRound: &deltaRound}).Apply(base)
+		require.NoError(t, err)
+		require.Equal(t, uint8(5), got.RMax)
+		require.Equal(t, deltaRound, got.DeltaRound)
+		require.Equal(t, base.DeltaProgress, got.DeltaProgress)
+		require.Equal(t, uint8(3), base.RMax, "base options must not change")
+	})
+	t.Run("zero rmax is rejected", func(t *testing.T) {
+		_, err := (&SetConfigOverrides{RMax: &zero}).Apply(base)
+		require.ErrorContains(t, err, "r_max must be positive")
+	})
+	t.Run("round longer than delta progress is rejected", func(t *testing.T) {
+		_, err := (&SetConfigOverrides{DeltaProgress: &shortProgress}).Apply(base)
+		require.ErrorContains(t, err, "must be less than delta_progress")
+	})
+	t.Run("nil options can't be overridden", func(t *testing.T) {
+		_, err := (&SetConfigOverrides{RMax: &rmax}).Apply(nil)
+		require.Error(t, err)
+	})
+}
+
+func TestSetConfigOverridesFromEnv(t *testing.T) {
+	t.Setenv(EnvVarSetConfigOverrides, "")
+	s, err := SetConfigOverridesFromEnv()
+	require.NoError(t, err)
+	require.Nil(t, s)
+
+	t.Setenv(EnvVarSetConfigOverrides, `{"rmax":5,"delta_round":10000000000}`)
+	s, err = SetConfigOverridesFromEnv()
+	require.NoError(t, err)
+	require.Equal(t, uint8(5), *s.RMax)
+	require.Equal(t, 10*time.Second, *s.DeltaRound)
+	require.Nil(t, s.DeltaProgress)
+
+	t.Setenv(EnvVarSetConfigOverrides, "rmax=5")
+	_, err = SetConfigOverridesFromEnv()
+	require.ErrorContains(t, err, EnvVarSetConfigOverrides)
+}
+
+func TestObservationLatency(t *testing.T) {
+	o := &OCRv2SetConfigOptions{MaxDurationObservation: 5 * time.Second}
+	late, onTime, err := o.ObservationLatency(250 * time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, 5250*time.Millisecond, late)
+	require.Equal(t, 4750*time.Millisecond, onTime)
+
+	_, _, err = o.ObservationLatency(0)
+	require.ErrorContains(t, err, "latency margin must be positive")
+	_, _, err = o.ObservationLatency(5 * time.Second)
+	require.ErrorContains(t, err, "less than max_duration_observation (5s)")
+	_, _, err = (*OCRv2SetConfigOptions)(nil).ObservationLatency(time.Second)
+	require.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+	"github.com/smartcontractkit/libocr/offchainreporting2/types"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
+)
+
+// maxOracles is the maximum number of oracles OCR2 aggregator accepts
+const maxOracles = 31
+
+// Validate checks stored config is consistent before it's applied, aggregator checks the same on setConfig
+func (c *OCRv2Config) Validate() error {
+	if len(c.Signers) == 0 {
+		return errors.New("stored config has no signers")
+	}
+	if len(c.Signers) != len(c.Transmitters) {
+		return fmt.Errorf("stored config has %d signers and %d transmitters, they must match", len(c.Signers), len(c.Transmitters))
+	}
+	if len(c.Signers) > maxOracles {
+		return fmt.Errorf("stored config has %d oracles, at most %d are allowed", len(c.Signers), maxOracles)
+	}
+	if c.F == 0 {
+		return errors.New("stored config f must be positive")
+	}
+	if len(c.Signers) <= 3*int(c.F) {
+		return fmt.Errorf("stored config has %d oracles, more than 3f=%d are required", len(c.Signers), 3*int(c.F))
+	}
+	for _, addrs := range []struct {
+		name  string
+		value []common.Address
+	}{
+		{"signer", c.Signers},
+		{"transmitter", c.Transmitters},
+	} {
+		seen := make(map[common.Address]bool, len(addrs.value))
+		for _, a := range addrs.value {
+			if a == (common.Address{}) {
+				return fmt.Errorf("stored config has a zero %s address", addrs.name)
+			}
+			if seen[a] {
+				return fmt.Errorf("stored config has duplicate %s %s", addrs.name, a.Hex())
+			}
+			seen[a] = true
+		}
+	}
+	if len(c.OffchainConfig) == 0 {
+		return errors.New("stored config has no offchain config")
+	}
+	return nil
+}
+
+// ApplyStoredConfig calls setConfig with exact signers, transmitters and configs of a previously stored config,
+// ex.: to restore a known-good config after a test changed it. Aggregator increments config count, so the
+// returned digest differs from the stored one, o.OCR2SetConfigOut is updated with the new digest. Stored config
+// is the config of the primary feed, other feeds keep the config set at deploy
+func ApplyStoredConfig(ctx context.Context, bc *blockchain.Input, o *OCR2, ocr2i *ocr2aggregator.OCR2Aggregator, cfg *OCRv2Config) (types.ConfigDigest, error) {
+	if cfg == nil {
+		return types.ConfigDigest{}, errors.New("no stored OCR2 config to apply")
+	}
+	if err := cfg.Validate(); err != nil {
+		return types.ConfigDigest{}, err
+	}
+	if err := checkBlockchainOut(bc); err != nil {
+		return types.ConfigDigest{}, err
+	}
+	feeCapMult, tipCapMult := o.GasSettings.Multipliers(GasOpSetConfig)
+	c, auth, _, err := ETHClient(ctx, bc.Out.Nodes[0].ExternalHTTPUrl, feeCapMult, tipCapMult)
+	if err != nil {
+		return types.ConfigDigest{}, fmt.Errorf("could not create basic eth client: %w", err)
+	}
+	tx, err := ocr2i.SetConfig(auth, cfg.Signers, cfg.Transmitters, cfg.F, cfg.OnchainConfig, cfg.OffchainConfigVersion, cfg.OffchainConfig)
+	if err != nil {
+		return types.ConfigDigest{}, fmt.Errorf("could not apply stored OCRv2 config: %w", err)
+	}
+	ev, err := waitConfigSet(ctx, c, ocr2i, ocr2i.Address(), tx, o.txWait(bc.Type, bc.Out.Nodes[0].ExternalHTTPUrl))
+	if err != nil {
+		return types.ConfigDigest{}, err
+	}
+	digest := types.ConfigDigest(ev.ConfigDigest)
+	applied := *cfg
+	applied.ConfigDigest = digest.Hex()
+	applied.ConfigSetTxHash = ev.Raw.TxHash.Hex()
+	applied.ConfigSetBlock = ev.Raw.BlockNumber
+	applied.ConfigCount = ev.ConfigCount
+	applied.ConfigSet = ev
+	o.OCR2SetConfigOut = &applied
+	ctxLogger(ctx).Info().Str("ConfigDigest", digest.Hex()).Uint64("ConfigCount", ev.ConfigCount).Msg("Applied stored OCR2 config")
+	return digest, nil
+}
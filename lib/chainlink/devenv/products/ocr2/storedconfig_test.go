@@ -0,0 +1,66 @@
+package ocr2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func testAddresses(prefix, n int) []common.Address {
+	addrs := make([]common.Address, 0, n)
+	for i := range n {
+		addrs = append(addrs, common.HexToAddress(fmt.Sprintf("0x%02x%038x", prefix, i+1)))
+	}
+	return addrs
+}
+
+func TestOCRv2ConfigValidate(t *testing.T) {
+	valid := func() *OCRv2Config {
+		return &OCRv2Config{
+			Signers:               testAddresses(1, 4),
+			Transmitters:          testAddresses(2, 4),
+			F:                     1,
+			OnchainConfig:         []byte{1},
+			OffchainConfigVersion: 2,
+			OffchainConfig:        []byte{2},
+		}
+	}
+	tests := []struct {
+		name    string
+		mutate  func(c *OCRv2Config)
+		wantErr string
+	}{
+		{name: "valid", mutate: func(c *OCRv2Config) {}},
+		{name: "no signers", mutate: func(c *OCRv2Config) { c.Signers, c.Transmitters = nil, nil }, wantErr: "no signers"},
+		{name: "length mismatch", mutate: func(c *OCRv2Config) { c.Transmitters = c.Transmitters[:3] }, wantErr: "4 signers and 3 transmitters"},
+		{name: "too many oracles", mutate: func(c *OCRv2Config) { c.Signers, c.Transmitters = testAddresses(1, 32), testAddresses(2, 32) }, wantErr: "at most 31"},
+		{name: "zero f", mutate: func(c *OCRv2Config) { c.F = 0 }, wantErr: "f must be positive"},
+		{name: "not enough oracles for f", mutate: func(c *OCRv2Config) { c.F = 2 }, wantErr: "more than 3f=6"},
+		{name: "zero signer", mutate: func(c *OCRv2Config) { c.Signers[1] = common.Address{} }, wantErr: "zero signer"},
+		{name: "duplicate transmitter", mutate: func(c *OCRv2Config) { c.Transmitters[3] = c.Transmitters[0] }, wantErr: "duplicate transmitter"},
+		{name: "no offchain config", mutate: func(c *OCRv2Config) { c.OffchainConfig = nil }, wantErr: "no offchain config"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid()
+			tc.mutate(c)
+			err := c.Validate()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestApplyStoredConfigValidates(t *testing.T) {
+	// invalid configs are rejected before the chain is touched
+	_, err := ApplyStoredConfig(context.Background(), nil, &OCR2{}, nil, nil)
+	require.ErrorContains(t, err, "no stored OCR2 config to apply")
+	_, err = ApplyStoredConfig(context.Background(), nil, &OCR2{}, nil, &OCRv2Config{Signers: testAddresses(1, 4), Transmitters: testAddresses(2, 3)})
+	require.ErrorContains(t, err, "4 signers and 3 transmitters")
+}
@@ -0,0 +1,73 @@
+package ocr2
+
+import (
+	"errors"
+
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+)
+
+// Telemetry points CL nodes and OCR2 jobs at a telemetry ingress, either an external one or the one started by the environment
+// if [telemetry_ingress] is set, see UseTelemetryIngress
+type Telemetry struct {
+	// Enabled turns telemetry on for all nodes and jobs
+	Enabled bool `toml:"enabled"`
+	// URL is the ingress endpoint nodes send telemetry to, ex.: host.docker.internal:9090
+	URL string `toml:"url"`
+	// ServerPubKey is the CSA public key of the ingress
+	ServerPubKey string `toml:"server_pub_key"`
+	// MonitoringEndpoint is set on OCR2 jobs, URL is used if it's empty
+	MonitoringEndpoint string `toml:"monitoring_endpoint"`
+	// CaptureEATelemetry makes worker jobs send EA responses as telemetry
+	CaptureEATelemetry bool `toml:"capture_ea_telemetry"`
+}
+
+// Validate checks ingress endpoint and key are set if telemetry is enabled
+func (t *Telemetry) Validate() error {
+	if t == nil || !t.Enabled {
+		return nil
+	}
+	if t.URL == "" {
+		return errors.New("telemetry url is required if telemetry is enabled")
+	}
+	if t.ServerPubKey == "" {
+		return errors.New("telemetry server_pub_key is required if telemetry is enabled")
+	}
+	return nil
+}
+
+// UseTelemetryIngress enables telemetry and points nodes and OCR2 jobs at an ingress started by the environment, url is reachable
+// from node containers, monitoring_endpoint and capture_ea_telemetry of [ocr2.telemetry] are kept
+func (m *Configurator) UseTelemetryIngress(url, serverPubKey string) {
+	if m.OCR2.Telemetry == nil {
+		m.OCR2.Telemetry = &Telemetry{}
+	}
+	m.OCR2.Telemetry.Enabled = true
+	m.OCR2.Telemetry.URL = url
+	m.OCR2.Telemetry.ServerPubKey = serverPubKey
+}
+
+// monitoringEndpoint returns the endpoint set on OCR2 jobs, it's null if telemetry is disabled
+func (t *Telemetry) monitoringEndpoint() null.String {
+	if t == nil || !t.Enabled {
+		return null.String{}
+	}
+	return null.StringFrom(defaults.Coalesce(t.MonitoringEndpoint, t.URL))
+}
+
+// captureEATelemetry returns true if worker jobs should send EA responses as telemetry
+func (t *Telemetry) captureEATelemetry() bool {
+	return t != nil && t.Enabled && t.CaptureEATelemetry
+}
+
+// nodeConfig returns CL node TelemetryIngress config for a chain, nil if telemetry is disabled
+func (t *Telemetry) nodeConfig(chainID string) *TelemetryIngressConfig {
+	if t == nil || !t.Enabled {
+		return nil
+	}
+	return &TelemetryIngressConfig{
+		Logging:   true,
+		Endpoints: []*TelemetryEndpointConfig{{Network: "EVM", ChainID: chainID, URL: t.URL, ServerPubKey: t.ServerPubKey}},
+	}
+}
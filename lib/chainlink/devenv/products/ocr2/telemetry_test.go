@@ -0,0 +1,66 @@
+package ocr2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelemetryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       *Telemetry
+		wantErr string
+	}{
+		{name: "not set"},
+		{name: "disabled", t: &Telemetry{}},
+		{name: "enabled", t: &Telemetry{Enabled: true, URL: "host.docker.internal:9090", ServerPubKey: "abc"}},
+		{name: "no url", t: &Telemetry{Enabled: true, ServerPubKey: "abc"}, wantErr: "telemetry url is required"},
+		{name: "no server key", t: &Telemetry{Enabled: true, URL: "host.docker.internal:9090"}, wantErr: "telemetry server_pub_key is required"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.t.Validate()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTelemetryJobsAndNodeConfig(t *testing.T) {
+	var none *Telemetry
+	require.False(t, none.monitoringEndpoint().Valid)
+	require.False(t, none.captureEATelemetry())
+	require.Nil(t, none.nodeConfig("1337"))
+
+	tel := &Telemetry{Enabled: true, URL: "host.docker.internal:9090", ServerPubKey: "abc", CaptureEATelemetry: true}
+	require.Equal(t, "host.docker.internal:9090", tel.monitoringEndpoint().String)
+	require.True(t, tel.captureEATelemetry())
+	cfg := tel.nodeConfig("1337")
+	require.True(t, cfg.Logging)
+	require.Equal(t, []*TelemetryEndpointConfig{{Network: "EVM", ChainID: "1337", URL: "host.docker.internal:9090", ServerPubKey: "abc"}}, cfg.Endpoints)
+
+	tel.MonitoringEndpoint = "monitoring:9091"
+	require.Equal(t, "monitoring:9091", tel.monitoringEndpoint().String)
+
+	specs, err := GenerateJobSpecs(&Configurator{OCR2: &OCR2{Jobs: &Jobs{}, Telemetry: tel}}, "", []string{"0x70997970C51812dc3A010C7d01b50e0d17dc79C8"}, "1337")
+	require.NoError(t, err)
+	require.Contains(t, specs["worker-1.toml"], `"monitoring:9091"`)
+	require.Contains(t, specs["worker-1.toml"], "captureEATelemetry                     = true")
+	require.NotContains(t, specs["bootstrap.toml"], "monitoringEndpoint")
+}
+
+func TestUseTelemetryIngress(t *testing.T) {
+	m := &Configurator{OCR2: &OCR2{}}
+	m.UseTelemetryIngress("telemetry-ingress:9090", "abc")
+	require.Equal(t, &Telemetry{Enabled: true, URL: "telemetry-ingress:9090", ServerPubKey: "abc"}, m.OCR2.Telemetry)
+
+	m = &Configurator{OCR2: &OCR2{Telemetry: &Telemetry{URL: "host.docker.internal:9090", CaptureEATelemetry: true}}}
+	m.UseTelemetryIngress("telemetry-ingress:9090", "abc")
+	require.NoError(t, m.OCR2.Telemetry.Validate())
+	require.True(t, m.OCR2.Telemetry.captureEATelemetry())
+	require.Equal(t, "telemetry-ingress:9090", m.OCR2.Telemetry.monitoringEndpoint().String)
+}
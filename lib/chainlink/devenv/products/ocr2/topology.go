@@ -0,0 +1,75 @@
+package ocr2
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/clnode"
+
+	nodeset "github.com/smartcontractkit/chainlink-testing-framework/framework/components/simple_node_set"
+)
+
+// Bootstrap selects nodes running bootstrap jobs, by default it's node 0 of the worker node set
+type Bootstrap struct {
+	// NodeSet is the name of the node set hosting bootstrap nodes, the worker node set is used if empty
+	NodeSet string `toml:"node_set"`
+	// Nodes are indexes of bootstrap nodes in the node set, node 0 is used if empty
+	Nodes []int `toml:"nodes"`
+}
+
+// jobTopology is the set of nodes running bootstrap and worker jobs
+type jobTopology struct {
+	// bootstrap are nodes running bootstrap jobs, they can belong to any node set
+	bootstrap []*clnode.Output
+	// workers are indexes of nodes running OCR2 jobs in the worker node set
+	workers []int
+}
+
+// topology selects bootstrap nodes from node sets, worker jobs run on every other node of the worker node set
+func (b *Bootstrap) topology(workerSet *nodeset.Input, sets []*nodeset.Input) (*jobTopology, error) {
+	name, idx := workerSet.Name, []int{0}
+	if b != nil && b.NodeSet != "" {
+		name = b.NodeSet
+	}
+	if b != nil && len(b.Nodes) > 0 {
+		idx = b.Nodes
+	}
+	var bootstrapSet *nodeset.Input
+	for _, s := range sets {
+		if s.Name == name {
+			bootstrapSet = s
+			break
+		}
+	}
+	if bootstrapSet == nil || bootstrapSet.Out == nil {
+		return nil, fmt.Errorf("bootstrap node set %s is not found", name)
+	}
+	t := &jobTopology{}
+	for _, i := range idx {
+		if i < 0 || i >= len(bootstrapSet.Out.CLNodes) {
+			return nil, fmt.Errorf("bootstrap node %d is out of range, node set %s has %d nodes", i, name, len(bootstrapSet.Out.CLNodes))
+		}
+		t.bootstrap = append(t.bootstrap, bootstrapSet.Out.CLNodes[i])
+	}
+	shared := bootstrapSet == workerSet
+	for i := range workerSet.Out.CLNodes {
+		if shared && slices.Contains(idx, i) {
+			continue
+		}
+		t.workers = append(t.workers, i)
+	}
+	if len(t.workers) == 0 {
+		return nil, fmt.Errorf("no worker nodes left in node set %s after selecting bootstrap nodes %v", workerSet.Name, idx)
+	}
+	return t, nil
+}
+
+// WorkerNodes returns indexes of nodes running OCR2 jobs in the worker node set, ex.: to check only they transmit,
+// node sets must have outputs
+func (b *Bootstrap) WorkerNodes(workerSet *nodeset.Input, sets []*nodeset.Input) ([]int, error) {
+	t, err := b.topology(workerSet, sets)
+	if err != nil {
+		return nil, err
+	}
+	return t.workers, nil
+}
@@ -0,0 +1,61 @@
+package ocr2
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/clnode"
+
+	nodeset "github.com/smartcontractkit/chainlink-testing-framework/framework/components/simple_node_set"
+)
+
+func testNodeSet(name string, nodes int) *nodeset.Input {
+	out := &nodeset.Output{}
+	for i := range nodes {
+		out.CLNodes = append(out.CLNodes, &clnode.Output{Node: &clnode.NodeOut{ContainerName: fmt.Sprintf("%s-node%d", name, i)}})
+	}
+	return &nodeset.Input{Name: name, Out: out}
+}
+
+func TestBootstrapTopology(t *testing.T) {
+	workers := testNodeSet("don", 5)
+	bootstrap := testNodeSet("bootstrap", 2)
+	sets := []*nodeset.Input{workers, bootstrap}
+
+	tests := []struct {
+		name          string
+		b             *Bootstrap
+		wantBootstrap []string
+		wantWorkers   []int
+		wantErr       string
+	}{
+		{name: "default", wantBootstrap: []string{"don-node0"}, wantWorkers: []int{1, 2, 3, 4}},
+		{name: "worker node set nodes", b: &Bootstrap{Nodes: []int{0, 4}}, wantBootstrap: []string{"don-node0", "don-node4"}, wantWorkers: []int{1, 2, 3}},
+		{name: "dedicated node set", b: &Bootstrap{NodeSet: "bootstrap"}, wantBootstrap: []string{"bootstrap-node0"}, wantWorkers: []int{0, 1, 2, 3, 4}},
+		{name: "dedicated node set nodes", b: &Bootstrap{NodeSet: "bootstrap", Nodes: []int{0, 1}}, wantBootstrap: []string{"bootstrap-node0", "bootstrap-node1"}, wantWorkers: []int{0, 1, 2, 3, 4}},
+		{name: "unknown node set", b: &Bootstrap{NodeSet: "other"}, wantErr: "bootstrap node set other is not found"},
+		{name: "out of range", b: &Bootstrap{NodeSet: "bootstrap", Nodes: []int{2}}, wantErr: "bootstrap node 2 is out of range, node set bootstrap has 2 nodes"},
+		{name: "no workers left", b: &Bootstrap{Nodes: []int{0, 1, 2, 3, 4}}, wantErr: "no worker nodes left in node set don"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			topo, err := tc.b.topology(workers, sets)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			names := make([]string, 0, len(topo.bootstrap))
+			for _, n := range topo.bootstrap {
+				names = append(names, n.Node.ContainerName)
+			}
+			require.Equal(t, tc.wantBootstrap, names)
+			require.Equal(t, tc.wantWorkers, topo.workers)
+			nodes, err := tc.b.WorkerNodes(workers, sets)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantWorkers, nodes)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package ocr2
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+)
+
+// DefaultMaxTransmissionsPerRound is how many times a report may be transmitted on-chain, OCR2 transmits every report once
+const DefaultMaxTransmissionsPerRound = 1
+
+// TransmissionReader reads blocks and receipts of transmit transactions, ex.: *ethclient.Client
+type TransmissionReader interface {
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	ReceiptReader
+}
+
+// RoundTransmissions is how many transmit transactions were sent to the aggregator for a report, reports are identified
+// by epoch and round of their report context, so duplicates that revert without emitting events are counted as well
+type RoundTransmissions struct {
+	Epoch uint32
+	Round uint8
+	// Transmissions is the number of transmit transactions of the report, reverted ones included
+	Transmissions int
+	// Reverted is how many of them reverted, ex.: the report was already transmitted by another node
+	Reverted int
+	// FromBlock and ToBlock are the block range transactions were found in
+	FromBlock uint64
+	ToBlock   uint64
+	// TxHashes are transmit transactions of the report
+	TxHashes []common.Hash
+	// GasUsed is gas used by every successful transmit transaction
+	GasUsed []uint64
+}
+
+// Check returns an error if the report was transmitted more than maxTransmissions times or every transmission reverted
+func (r RoundTransmissions) Check(maxTransmissions int) error {
+	if r.Transmissions > maxTransmissions {
+		return fmt.Errorf("epoch %d round %d was transmitted %d times (%d reverted) in blocks %d-%d, expected at most %d",
+			r.Epoch, r.Round, r.Transmissions, r.Reverted, r.FromBlock, r.ToBlock, maxTransmissions)
+	}
+	if r.Transmissions == r.Reverted {
+		return fmt.Errorf("epoch %d round %d has %d transmissions, all of them reverted", r.Epoch, r.Round, r.Transmissions)
+	}
+	return nil
+}
+
+// CountRoundTransmissions counts transmit transactions sent to aggregator in blocks fromBlock-toBlock by report epoch and round,
+// reports are returned in order of their first transmission
+func CountRoundTransmissions(ctx context.Context, r TransmissionReader, aggregator common.Address, fromBlock, toBlock uint64) ([]RoundTransmissions, error) {
+	parsed, err := abi.JSON(strings.NewReader(ocr2aggregator.OCR2AggregatorABI))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse aggregator ABI: %w", err)
+	}
+	transmit := parsed.Methods["transmit"]
+	res := make([]RoundTransmissions, 0)
+	idx := make(map[[2]uint32]int)
+	for n := fromBlock; n <= toBlock; n++ {
+		block, err := r.BlockByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return nil, fmt.Errorf("could not read block %d: %w", n, err)
+		}
+		for _, tx := range block.Transactions() {
+			if tx.To() == nil || *tx.To() != aggregator || !bytes.HasPrefix(tx.Data(), transmit.ID) {
+				continue
+			}
+			epoch, round, err := transmitEpochAndRound(transmit, tx.Data())
+			if err != nil {
+				return nil, fmt.Errorf("could not decode transmission %s: %w", tx.Hash().Hex(), err)
+			}
+			receipt, err := r.TransactionReceipt(ctx, tx.Hash())
+			if err != nil {
+				return nil, fmt.Errorf("could not read receipt of transmission %s: %w", tx.Hash().Hex(), err)
+			}
+			key := [2]uint32{epoch, uint32(round)}
+			i, ok := idx[key]
+			if !ok {
+				i = len(res)
+				idx[key] = i
+				res = append(res, RoundTransmissions{Epoch: epoch, Round: round})
+			}
+			res[i].observe(n, tx.Hash(), receipt)
+		}
+	}
+	return res, nil
+}
+
+// transmitEpochAndRound decodes epoch and round from report context of transmit call data,
+// the second report context word is 27 zero bytes, 4 bytes of epoch and 1 byte of round
+func transmitEpochAndRound(transmit abi.Method, data []byte) (uint32, uint8, error) {
+	args, err := transmit.Inputs.Unpack(data[len(transmit.ID):])
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(args) == 0 {
+		return 0, 0, errors.New("transmit has no report context")
+	}
+	reportContext, ok := args[0].([3][32]byte)
+	if !ok {
+		return 0, 0, fmt.Errorf("unexpected report context type %T", args[0])
+	}
+	epochAndRound := reportContext[1]
+	return binary.BigEndian.Uint32(epochAndRound[27:31]), epochAndRound[31], nil
+}
+
+// observe records a transmit transaction mined in block
+func (r *RoundTransmissions) observe(block uint64, tx common.Hash, receipt *types.Receipt) {
+	r.Transmissions++
+	r.TxHashes = append(r.TxHashes, tx)
+	if receipt.Status == types.ReceiptStatusFailed {
+		r.Reverted++
+	} else {
+		r.GasUsed = append(r.GasUsed, receipt.GasUsed)
+	}
+	if r.FromBlock == 0 || block < r.FromBlock {
+		r.FromBlock = block
+	}
+	if block > r.ToBlock {
+		r.ToBlock = block
+	}
+}
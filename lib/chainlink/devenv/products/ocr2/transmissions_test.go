@@ -0,0 +1,117 @@
+package ocr2
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+	"github.com/stretchr/testify/require"
+)
+
+// transmitChain is a chain backend with transmit transactions by block, reverted transactions use less gas
+type transmitChain struct {
+	blocks   map[uint64][]*types.Transaction
+	reverted map[common.Hash]bool
+}
+
+func (c *transmitChain) BlockByNumber(_ context.Context, n *big.Int) (*types.Block, error) {
+	return types.NewBlockWithHeader(&types.Header{Number: n}).WithBody(types.Body{Transactions: c.blocks[n.Uint64()]}), nil
+}
+
+func (c *transmitChain) TransactionReceipt(_ context.Context, h common.Hash) (*types.Receipt, error) {
+	if c.reverted[h] {
+		return &types.Receipt{TxHash: h, Status: types.ReceiptStatusFailed, GasUsed: 30_000}, nil
+	}
+	for _, txs := range c.blocks {
+		for _, tx := range txs {
+			if tx.Hash() == h {
+				return &types.Receipt{TxHash: h, Status: types.ReceiptStatusSuccessful, GasUsed: 100_000}, nil
+			}
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+// transmitTx packs a transmit call of a report of epoch and round, nonce keeps transactions distinct
+func transmitTx(t *testing.T, to common.Address, nonce uint64, epoch uint32, round uint8) *types.Transaction {
+	t.Helper()
+	parsed, err := abi.JSON(strings.NewReader(ocr2aggregator.OCR2AggregatorABI))
+	require.NoError(t, err)
+	var reportContext [3][32]byte
+	binary.BigEndian.PutUint32(reportContext[1][27:31], epoch)
+	reportContext[1][31] = round
+	data, err := parsed.Pack("transmit", reportContext, []byte("report"), [][32]byte{{1}}, [][32]byte{{2}}, [32]byte{})
+	require.NoError(t, err)
+	return types.NewTx(&types.LegacyTx{Nonce: nonce, To: &to, Data: data})
+}
+
+func TestCountRoundTransmissions(t *testing.T) {
+	aggregator := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	first := transmitTx(t, aggregator, 1, 2, 1)
+	duplicate := transmitTx(t, aggregator, 2, 2, 1)
+	next := transmitTx(t, aggregator, 3, 2, 2)
+	chain := &transmitChain{
+		blocks: map[uint64][]*types.Transaction{
+			10: {first, transmitTx(t, other, 4, 2, 1)},
+			11: {duplicate, types.NewTx(&types.LegacyTx{Nonce: 5, To: &aggregator})},
+			12: {next},
+		},
+		reverted: map[common.Hash]bool{duplicate.Hash(): true},
+	}
+
+	rounds, err := CountRoundTransmissions(context.Background(), chain, aggregator, 10, 12)
+	require.NoError(t, err)
+	require.Equal(t, []RoundTransmissions{
+		{
+			Epoch: 2, Round: 1, Transmissions: 2, Reverted: 1, FromBlock: 10, ToBlock: 11,
+			TxHashes: []common.Hash{first.Hash(), duplicate.Hash()}, GasUsed: []uint64{100_000},
+		},
+		{Epoch: 2, Round: 2, Transmissions: 1, FromBlock: 12, ToBlock: 12, TxHashes: []common.Hash{next.Hash()}, GasUsed: []uint64{100_000}},
+	}, rounds)
+	// a reverted duplicate emits no events, it's still a redundant transmission
+	require.ErrorContains(t, rounds[0].Check(DefaultMaxTransmissionsPerRound), "epoch 2 round 1 was transmitted 2 times (1 reverted) in blocks 10-11, expected at most 1")
+	require.NoError(t, rounds[0].Check(2))
+	require.NoError(t, rounds[1].Check(DefaultMaxTransmissionsPerRound))
+	require.Equal(t, GasStats{Transmissions: 2, Total: 200_000, Max: 100_000}, TransmissionGas(rounds))
+
+	rounds, err = CountRoundTransmissions(context.Background(), chain, aggregator, 13, 14)
+	require.NoError(t, err)
+	require.Empty(t, rounds)
+}
+
+func TestRoundTransmissionsCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       RoundTransmissions
+		max     int
+		wantErr string
+	}{
+		{name: "single transmission", r: RoundTransmissions{Epoch: 1, Round: 3, Transmissions: 1}, max: 1},
+		{name: "within a looser bound", r: RoundTransmissions{Epoch: 1, Round: 3, Transmissions: 2, Reverted: 1}, max: 2},
+		{name: "all reverted", r: RoundTransmissions{Epoch: 1, Round: 3, Transmissions: 1, Reverted: 1}, max: 1, wantErr: "epoch 1 round 3 has 1 transmissions, all of them reverted"},
+		{
+			name:    "duplicate transmissions",
+			r:       RoundTransmissions{Epoch: 1, Round: 4, Transmissions: 2, FromBlock: 10, ToBlock: 11},
+			max:     1,
+			wantErr: "epoch 1 round 4 was transmitted 2 times (0 reverted) in blocks 10-11, expected at most 1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.r.Check(tc.max)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/devenv/defaults"
+)
+
+// ErrWaitExhausted is returned by WaitConfig.Poll when timeout or max attempts are reached
+var ErrWaitExhausted = errors.New("wait exhausted")
+
+// DefaultTxWait is used to wait for transactions if [ocr2.wait] is not set
+var DefaultTxWait = WaitConfig{PollIntervalMs: 1000, TimeoutSec: 300}
+
+// defaultPollInterval is used if neither wait config nor its fallback set the interval
+const defaultPollInterval = time.Second
+
+const (
+	// ConfirmPoll fetches transaction receipts every poll_interval_ms, it works with any RPC endpoint
+	// and is the default on HTTP RPC and chains other than Anvil
+	ConfirmPoll = "poll"
+	// ConfirmSubscribe fetches transaction receipts on every new head, it needs a websocket endpoint that supports subscriptions
+	ConfirmSubscribe = "subscribe"
+)
+
+// WaitConfig tunes wait loops, a short poll interval makes tests faster but puts more pressure on RPC,
+// which matters on rate-limited testnet endpoints
+type WaitConfig struct {
+	// PollIntervalMs is the delay between checks
+	PollIntervalMs int64 `toml:"poll_interval_ms"`
+	// TimeoutSec is the overall wait timeout, 0 waits until context is done
+	TimeoutSec int64 `toml:"timeout_sec"`
+	// MaxAttempts limits the number of checks, 0 is unlimited
+	MaxAttempts int `toml:"max_attempts"`
+	// Confirm selects how transactions are confirmed, ConfirmPoll or ConfirmSubscribe, see OCR2.txWait for the default
+	Confirm string `toml:"confirm"`
+}
+
+// chainTypeAnvil is blockchain type of Anvil, the environment sends transactions to it over websocket
+const chainTypeAnvil = "anvil"
+
+// txWait returns how transactions sent over rpcURL to a chain of chainType are waited for, [ocr2.wait] values take precedence.
+// Receipts are fetched on new heads on Anvil over websocket and polled otherwise, ex.: over HTTP RPC or on testnets
+func (o *OCR2) txWait(chainType, rpcURL string) WaitConfig {
+	fallback := DefaultTxWait
+	if chainType == chainTypeAnvil && isWebsocketURL(rpcURL) {
+		fallback.Confirm = ConfirmSubscribe
+	}
+	return o.Wait.Or(fallback)
+}
+
+// isWebsocketURL checks rpcURL is a ws:// or wss:// URL
+func isWebsocketURL(rpcURL string) bool {
+	u, err := url.Parse(rpcURL)
+	return err == nil && (u.Scheme == "ws" || u.Scheme == "wss")
+}
+
+// Validate checks that all wait settings are non-negative
+func (w *WaitConfig) Validate() error {
+	if w.PollIntervalMs < 0 {
+		return fmt.Errorf("wait poll_interval_ms must be non-negative, got %d", w.PollIntervalMs)
+	}
+	if w.TimeoutSec < 0 {
+		return fmt.Errorf("wait timeout_sec must be non-negative, got %d", w.TimeoutSec)
+	}
+	if w.MaxAttempts < 0 {
+		return fmt.Errorf("wait max_attempts must be non-negative, got %d", w.MaxAttempts)
+	}
+	switch w.Confirm {
+	case "", ConfirmPoll, ConfirmSubscribe:
+	default:
+		return fmt.Errorf("wait confirm must be %q or %q, got %q", ConfirmPoll, ConfirmSubscribe, w.Confirm)
+	}
+	return nil
+}
+
+// Or returns a copy of w with unset values taken from fallback, fallback is returned if w is nil
+func (w *WaitConfig) Or(fallback WaitConfig) WaitConfig {
+	if w == nil {
+		return fallback
+	}
+	return WaitConfig{
+		PollIntervalMs: defaults.Coalesce(w.PollIntervalMs, fallback.PollIntervalMs),
+		TimeoutSec:     defaults.Coalesce(w.TimeoutSec, fallback.TimeoutSec),
+		MaxAttempts:    defaults.Coalesce(w.MaxAttempts, fallback.MaxAttempts),
+		Confirm:        defaults.Coalesce(w.Confirm, fallback.Confirm),
+	}
+}
+
+// PollInterval returns the delay between checks
+func (w WaitConfig) PollInterval() time.Duration {
+	if w.PollIntervalMs <= 0 {
+		return defaultPollInterval
+	}
+	return time.Duration(w.PollIntervalMs) * time.Millisecond
+}
+
+// Timeout returns the overall wait timeout
+func (w WaitConfig) Timeout() time.Duration {
+	return time.Duration(w.TimeoutSec) * time.Second
+}
+
+// Poll calls check until it's done, errors returned by check are retried and the last one is wrapped
+// into ErrWaitExhausted when timeout or max attempts are reached
+func (w WaitConfig) Poll(ctx context.Context, check func(ctx context.Context) (bool, error)) error {
+	if w.TimeoutSec > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout())
+		defer cancel()
+	}
+	ticker := time.NewTicker(w.PollInterval())
+	defer ticker.Stop()
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		done, err := check(ctx)
+		switch {
+		case err != nil:
+			lastErr = err
+		case done:
+			return nil
+		}
+		if w.MaxAttempts > 0 && attempt >= w.MaxAttempts {
+			return exhausted(fmt.Sprintf("%d attempts", attempt), lastErr)
+		}
+		select {
+		case <-ctx.Done():
+			if w.TimeoutSec == 0 || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ctx.Err()
+			}
+			return exhausted(w.Timeout().String(), lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+// exhausted wraps ErrWaitExhausted and the last check error if there was one
+func exhausted(after string, lastErr error) error {
+	if lastErr != nil {
+		return fmt.Errorf("%w after %s, last check failed: %w", ErrWaitExhausted, after, lastErr)
+	}
+	return fmt.Errorf("%w after %s", ErrWaitExhausted, after)
+}
+
+// AdaptiveInterval is a poll interval that doubles on failed or slow checks and halves on healthy ones, staying within [Min, Max],
+// so a struggling RPC endpoint is polled less often and rounds are still detected quickly when it recovers
+type AdaptiveInterval struct {
+	Min time.Duration
+	Max time.Duration
+	// SlowAfter marks a successful check slower than this as unhealthy, 0 only counts errors
+	SlowAfter time.Duration
+	current   time.Duration
+	failures  int
+}
+
+// NewAdaptiveInterval starts at minInterval, maxInterval lower than minInterval is raised to it
+func NewAdaptiveInterval(minInterval, maxInterval, slowAfter time.Duration) *AdaptiveInterval {
+	return &AdaptiveInterval{Min: minInterval, Max: max(minInterval, maxInterval), SlowAfter: slowAfter, current: minInterval}
+}
+
+// Current returns the delay before the next check
+func (a *AdaptiveInterval) Current() time.Duration {
+	return a.current
+}
+
+// ConsecutiveFailures returns the number of failed checks since the last successful one
+func (a *AdaptiveInterval) ConsecutiveFailures() int {
+	return a.failures
+}
+
+// Observe adjusts the interval by check result and its duration and returns the new interval
+func (a *AdaptiveInterval) Observe(err error, took time.Duration) time.Duration {
+	if err != nil {
+		a.failures++
+	} else {
+		a.failures = 0
+	}
+	if err != nil || (a.SlowAfter > 0 && took > a.SlowAfter) {
+		a.current = min(a.Max, a.current*2)
+	} else {
+		a.current = max(a.Min, a.current/2)
+	}
+	return a.current
+}
@@ -0,0 +1,128 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitConfigOr(t *testing.T) {
+	fallback := WaitConfig{PollIntervalMs: 1000, TimeoutSec: 300}
+	var none *WaitConfig
+	require.Equal(t, fallback, none.Or(fallback))
+	require.Equal(t, WaitConfig{PollIntervalMs: 5000, TimeoutSec: 300, MaxAttempts: 10}, (&WaitConfig{PollIntervalMs: 5000, MaxAttempts: 10}).Or(fallback))
+	require.Equal(t, ConfirmSubscribe, (&WaitConfig{Confirm: ConfirmSubscribe}).Or(fallback).Confirm)
+}
+
+func TestTxWait(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		wait      *WaitConfig
+		chainType string
+		rpcURL    string
+		want      string
+	}{
+		{name: "anvil over websocket", chainType: "anvil", rpcURL: "ws://localhost:8545", want: ConfirmSubscribe},
+		// unset confirm polls receipts
+		{name: "anvil over HTTP", chainType: "anvil", rpcURL: "http://localhost:8545"},
+		{name: "geth over websocket", chainType: "geth", rpcURL: "ws://localhost:8546"},
+		{name: "confirm is set", wait: &WaitConfig{Confirm: ConfirmPoll}, chainType: "anvil", rpcURL: "ws://localhost:8545", want: ConfirmPoll},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := (&OCR2{Wait: tc.wait}).txWait(tc.chainType, tc.rpcURL)
+			require.Equal(t, DefaultTxWait.TimeoutSec, w.TimeoutSec)
+			require.Equal(t, tc.want, w.Confirm)
+		})
+	}
+}
+
+func TestWaitConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		w       WaitConfig
+		wantErr string
+	}{
+		{name: "zero values", w: WaitConfig{}},
+		{name: "all set", w: WaitConfig{PollIntervalMs: 500, TimeoutSec: 60, MaxAttempts: 10}},
+		{name: "negative poll interval", w: WaitConfig{PollIntervalMs: -1}, wantErr: "poll_interval_ms"},
+		{name: "negative timeout", w: WaitConfig{TimeoutSec: -1}, wantErr: "timeout_sec"},
+		{name: "negative max attempts", w: WaitConfig{MaxAttempts: -1}, wantErr: "max_attempts"},
+		{name: "subscribe", w: WaitConfig{Confirm: ConfirmSubscribe}},
+		{name: "unknown confirm", w: WaitConfig{Confirm: "websocket"}, wantErr: `wait confirm must be "poll" or "subscribe"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.w.Validate()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWaitConfigPoll(t *testing.T) {
+	checkErr := errors.New("rate limited")
+
+	t.Run("done after retries", func(t *testing.T) {
+		calls := 0
+		err := WaitConfig{PollIntervalMs: 1, TimeoutSec: 1}.Poll(context.Background(), func(context.Context) (bool, error) {
+			calls++
+			if calls == 1 {
+				return false, checkErr
+			}
+			return calls == 3, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+	})
+	t.Run("max attempts", func(t *testing.T) {
+		calls := 0
+		err := WaitConfig{PollIntervalMs: 1, MaxAttempts: 2}.Poll(context.Background(), func(context.Context) (bool, error) {
+			calls++
+			return false, nil
+		})
+		require.ErrorIs(t, err, ErrWaitExhausted)
+		require.ErrorContains(t, err, "after 2 attempts")
+		require.Equal(t, 2, calls)
+	})
+	t.Run("timeout with the last check error", func(t *testing.T) {
+		err := WaitConfig{PollIntervalMs: 100, TimeoutSec: 1}.Poll(context.Background(), func(context.Context) (bool, error) {
+			return false, checkErr
+		})
+		require.ErrorIs(t, err, ErrWaitExhausted)
+		require.ErrorIs(t, err, checkErr)
+		require.ErrorContains(t, err, "after 1s")
+	})
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := WaitConfig{PollIntervalMs: 1}.Poll(ctx, func(context.Context) (bool, error) {
+			return false, nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestAdaptiveInterval(t *testing.T) {
+	a := NewAdaptiveInterval(time.Second, 5*time.Second, 2*time.Second)
+	require.Equal(t, time.Second, a.Current())
+	rpcErr := errors.New("429 Too Many Requests")
+
+	require.Equal(t, 2*time.Second, a.Observe(rpcErr, 0))
+	require.Equal(t, 4*time.Second, a.Observe(rpcErr, 0))
+	require.Equal(t, 5*time.Second, a.Observe(rpcErr, 0), "interval must not exceed max")
+	require.Equal(t, 3, a.ConsecutiveFailures())
+
+	require.Equal(t, 5*time.Second, a.Observe(nil, 3*time.Second), "slow check must not narrow the interval")
+	require.Equal(t, 0, a.ConsecutiveFailures())
+	require.Equal(t, 2500*time.Millisecond, a.Observe(nil, 100*time.Millisecond))
+	require.Equal(t, 1250*time.Millisecond, a.Observe(nil, 100*time.Millisecond))
+	require.Equal(t, time.Second, a.Observe(nil, 100*time.Millisecond), "interval must not go below min")
+
+	require.Equal(t, time.Second, NewAdaptiveInterval(time.Second, 0, 0).Max)
+}
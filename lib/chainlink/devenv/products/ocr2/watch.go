@@ -0,0 +1,111 @@
+package ocr2
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+)
+
+// DefaultResubscribeBackoff is the longest delay between resubscribe attempts after a websocket disconnect
+const DefaultResubscribeBackoff = 10 * time.Second
+
+// RoundEventKind is the aggregator event a RoundEvent comes from
+type RoundEventKind string
+
+const (
+	RoundEventAnswerUpdated   RoundEventKind = "AnswerUpdated"
+	RoundEventNewTransmission RoundEventKind = "NewTransmission"
+)
+
+// RoundEvent is an AnswerUpdated or NewTransmission aggregator event, Transmitter is only set for NewTransmission.
+// Removed events were reorged out and are delivered again if their transaction is included in another block
+type RoundEvent struct {
+	Kind        RoundEventKind
+	RoundID     uint32
+	Answer      *big.Int
+	Transmitter common.Address
+	BlockNumber uint64
+	TxHash      common.Hash
+	Removed     bool
+}
+
+// AnswerWatcher subscribes to aggregator round events, ex.: *ocr2aggregator.OCR2Aggregator bound to a websocket client
+type AnswerWatcher interface {
+	WatchAnswerUpdated(opts *bind.WatchOpts, sink chan<- *ocr2aggregator.OCR2AggregatorAnswerUpdated, current []*big.Int, roundId []*big.Int) (event.Subscription, error)
+	WatchNewTransmission(opts *bind.WatchOpts, sink chan<- *ocr2aggregator.OCR2AggregatorNewTransmission, aggregatorRoundId []uint32) (event.Subscription, error)
+}
+
+// WatchAnswerUpdates calls fn for every AnswerUpdated and NewTransmission event until ctx is done, events are delivered
+// one at a time in arrival order. Subscriptions are re-established with backoff if the connection drops,
+// events emitted while disconnected are not replayed, so a round check should still run periodically
+func WatchAnswerUpdates(ctx context.Context, w AnswerWatcher, fn func(round RoundEvent)) error {
+	answers := make(chan *ocr2aggregator.OCR2AggregatorAnswerUpdated, 16)
+	transmissions := make(chan *ocr2aggregator.OCR2AggregatorNewTransmission, 16)
+	// resubscribe context is not derived from ctx, so the logger is taken from ctx before
+	log := ctxLogger(ctx)
+	sub := event.ResubscribeErr(DefaultResubscribeBackoff, func(ctx context.Context, lastErr error) (event.Subscription, error) {
+		if lastErr != nil {
+			log.Warn().Err(lastErr).Msg("Aggregator event subscription dropped, resubscribing")
+		}
+		return watchRoundEvents(ctx, w, answers, transmissions)
+	})
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return err
+		case ev := <-answers:
+			fn(RoundEvent{
+				Kind:        RoundEventAnswerUpdated,
+				RoundID:     uint32(ev.RoundId.Uint64()),
+				Answer:      ev.Current,
+				BlockNumber: ev.Raw.BlockNumber,
+				TxHash:      ev.Raw.TxHash,
+				Removed:     ev.Raw.Removed,
+			})
+		case ev := <-transmissions:
+			fn(RoundEvent{
+				Kind:        RoundEventNewTransmission,
+				RoundID:     ev.AggregatorRoundId,
+				Answer:      ev.Answer,
+				Transmitter: ev.Transmitter,
+				BlockNumber: ev.Raw.BlockNumber,
+				TxHash:      ev.Raw.TxHash,
+				Removed:     ev.Raw.Removed,
+			})
+		}
+	}
+}
+
+// watchRoundEvents subscribes to both events and returns a subscription that fails when either of them fails
+func watchRoundEvents(ctx context.Context, w AnswerWatcher, answers chan<- *ocr2aggregator.OCR2AggregatorAnswerUpdated, transmissions chan<- *ocr2aggregator.OCR2AggregatorNewTransmission) (event.Subscription, error) {
+	opts := &bind.WatchOpts{Context: ctx}
+	au, err := w.WatchAnswerUpdated(opts, answers, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	nt, err := w.WatchNewTransmission(opts, transmissions, nil)
+	if err != nil {
+		au.Unsubscribe()
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer au.Unsubscribe()
+		defer nt.Unsubscribe()
+		select {
+		case err := <-au.Err():
+			return err
+		case err := <-nt.Err():
+			return err
+		case <-quit:
+			return nil
+		}
+	}), nil
+}
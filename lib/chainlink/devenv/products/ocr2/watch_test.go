@@ -0,0 +1,119 @@
+package ocr2
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+	"github.com/stretchr/testify/require"
+)
+
+// droppingWatcher delivers a round per subscription, the first subscription drops right after its round
+type droppingWatcher struct {
+	mu            sync.Mutex
+	subscriptions int
+}
+
+func (w *droppingWatcher) WatchAnswerUpdated(opts *bind.WatchOpts, sink chan<- *ocr2aggregator.OCR2AggregatorAnswerUpdated, _ []*big.Int, _ []*big.Int) (event.Subscription, error) {
+	w.mu.Lock()
+	w.subscriptions++
+	round := w.subscriptions
+	w.mu.Unlock()
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		select {
+		case sink <- &ocr2aggregator.OCR2AggregatorAnswerUpdated{
+			Current: big.NewInt(int64(round * 100)),
+			RoundId: big.NewInt(int64(round)),
+			Raw:     types.Log{BlockNumber: uint64(round + 10)},
+		}:
+		case <-quit:
+			return nil
+		}
+		if round == 1 {
+			return errors.New("websocket: close 1006")
+		}
+		<-quit
+		return nil
+	}), nil
+}
+
+func (w *droppingWatcher) WatchNewTransmission(opts *bind.WatchOpts, sink chan<- *ocr2aggregator.OCR2AggregatorNewTransmission, _ []uint32) (event.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func TestWatchAnswerUpdatesResubscribes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	w := &droppingWatcher{}
+	var rounds []RoundEvent
+	err := WatchAnswerUpdates(ctx, w, func(round RoundEvent) {
+		rounds = append(rounds, round)
+		if len(rounds) == 2 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+	require.Equal(t, []RoundEvent{
+		{Kind: RoundEventAnswerUpdated, RoundID: 1, Answer: big.NewInt(100), BlockNumber: 11},
+		{Kind: RoundEventAnswerUpdated, RoundID: 2, Answer: big.NewInt(200), BlockNumber: 12},
+	}, rounds)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	require.GreaterOrEqual(t, w.subscriptions, 2)
+}
+
+// transmissionWatcher delivers a single NewTransmission event
+type transmissionWatcher struct{}
+
+func (transmissionWatcher) WatchAnswerUpdated(opts *bind.WatchOpts, sink chan<- *ocr2aggregator.OCR2AggregatorAnswerUpdated, _ []*big.Int, _ []*big.Int) (event.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func (transmissionWatcher) WatchNewTransmission(opts *bind.WatchOpts, sink chan<- *ocr2aggregator.OCR2AggregatorNewTransmission, _ []uint32) (event.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		select {
+		case sink <- &ocr2aggregator.OCR2AggregatorNewTransmission{
+			AggregatorRoundId: 7,
+			Answer:            big.NewInt(15),
+			Transmitter:       common.HexToAddress("0x01"),
+			Raw:               types.Log{BlockNumber: 42, TxHash: common.HexToHash("0xaa")},
+		}:
+		case <-quit:
+		}
+		<-quit
+		return nil
+	}), nil
+}
+
+func TestWatchAnswerUpdatesTransmissions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var got RoundEvent
+	err := WatchAnswerUpdates(ctx, transmissionWatcher{}, func(round RoundEvent) {
+		got = round
+		cancel()
+	})
+	require.NoError(t, err)
+	require.Equal(t, RoundEvent{
+		Kind:        RoundEventNewTransmission,
+		RoundID:     7,
+		Answer:      big.NewInt(15),
+		Transmitter: common.HexToAddress("0x01"),
+		BlockNumber: 42,
+		TxHash:      common.HexToHash("0xaa"),
+	}, got)
+}
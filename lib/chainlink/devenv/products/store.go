@@ -0,0 +1,83 @@
+package products
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ConfigStore is a backend used to read and persist TOML configs, filesystem is used by default
+type ConfigStore interface {
+	// Read reads config by name, returns an error wrapping fs.ErrNotExist if it's not found
+	Read(name string) ([]byte, error)
+	// Write writes config by name, overriding existing data
+	Write(name string, data []byte) error
+}
+
+// FSStore reads and writes configs in a local directory
+type FSStore struct {
+	Dir string
+}
+
+// NewFSStore creates a filesystem config store rooted at dir
+func NewFSStore(dir string) *FSStore {
+	return &FSStore{Dir: dir}
+}
+
+func (s *FSStore) Read(name string) ([]byte, error) {
+	return os.ReadFile(s.path(name))
+}
+
+// Write creates parent directories of name, ex.: the run directory of CTF_ARTIFACTS_DIR
+func (s *FSStore) Write(name string, data []byte) error {
+	path := s.path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (s *FSStore) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(s.Dir, name)
+}
+
+// MemoryStore keeps configs in memory, useful for tests and ephemeral environments
+type MemoryStore struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory config store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{files: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Read(name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[name]
+	if !ok {
+		return nil, fmt.Errorf("config %s: %w", name, fs.ErrNotExist)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (s *MemoryStore) Write(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[name] = append([]byte(nil), data...)
+	return nil
+}
+
+// SelectStore returns the first provided store or a filesystem store rooted at dir
+func SelectStore(dir string, store []ConfigStore) ConfigStore {
+	if len(store) > 0 && store[0] != nil {
+		return store[0]
+	}
+	return NewFSStore(dir)
+}
@@ -0,0 +1,31 @@
+package products
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCfg struct {
+	Name  string `toml:"name"`
+	Nodes int    `toml:"nodes"`
+}
+
+func TestMemoryStore(t *testing.T) {
+	t.Setenv(EnvVarTestConfigs, "env.toml")
+	s := NewMemoryStore()
+
+	_, err := s.Read("env-out.toml")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	require.NoError(t, s.Write("env.toml", []byte("name = \"don\"\nnodes = 4\n")))
+	in, err := Load[testCfg](s)
+	require.NoError(t, err)
+	require.Equal(t, &testCfg{Name: "don", Nodes: 4}, in)
+
+	require.NoError(t, Store(".", &testCfg{Name: "out", Nodes: 5}, s))
+	out, err := LoadOutput[testCfg]("env-out.toml", s)
+	require.NoError(t, err)
+	require.Equal(t, &testCfg{Name: "out", Nodes: 5}, out)
+}
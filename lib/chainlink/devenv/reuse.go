@@ -0,0 +1,174 @@
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+
+	ns "github.com/smartcontractkit/chainlink-testing-framework/framework/components/simple_node_set"
+
+	"github.com/smartcontractkit/chainlink/devenv/products"
+	"github.com/smartcontractkit/chainlink/devenv/products/ocr2"
+)
+
+// EnvVarReuseNodeSets makes NewEnvironment reuse running node sets recorded in the previous output instead of recreating them,
+// ex.: CTF_REUSE_NODE_SETS=true, it's set by cl up --reuse-nodes
+const EnvVarReuseNodeSets = "CTF_REUSE_NODE_SETS"
+
+// ReuseCheckWait is how long a fake server of the previous output may take to answer before it's recreated
+var ReuseCheckWait = ocr2.WaitConfig{PollIntervalMs: 500, TimeoutSec: 10}
+
+// reuseRunning copies outputs of running blockchains, fakes and node sets from the previous output into in,
+// so only jobs and contracts are configured again. OCR2 and bootstrap jobs of the previous run are deleted, so reused nodes
+// don't run them next to new jobs against the old aggregator. Nodes are bound to their chain and fakes, so everything is reused
+// or nothing, an error explains why the environment has to be recreated and in is left untouched
+func reuseRunning(ctx context.Context, in *Cfg, store ...products.ConfigStore) error {
+	path, err := OutputPath()
+	if err != nil {
+		return err
+	}
+	data, err := products.SelectStore(DefaultConfigDir, store).Read(path)
+	if err != nil {
+		return fmt.Errorf("could not read previous output %s: %w", path, err)
+	}
+	var prev Cfg
+	if err := products.DecodeTOML(path, data, &prev); err != nil {
+		return err
+	}
+	if err := matchOutputs(in, &prev); err != nil {
+		return fmt.Errorf("previous output %s doesn't match the config: %w", path, err)
+	}
+	if err := checkRunning(ctx, &prev); err != nil {
+		return err
+	}
+	if err := deleteOCR2Jobs(ctx, &prev); err != nil {
+		return err
+	}
+	for i, bc := range in.Blockchains {
+		bc.Out = prev.Blockchains[i].Out
+	}
+	prevFakes := prev.Fakes()
+	for i, f := range in.Fakes() {
+		f.Out = prevFakes[i].Out
+	}
+	for _, nodeSet := range in.NodeSets {
+		nodeSet.Out = prevNodeSet(&prev, nodeSet.Name).Out
+	}
+	ctxLogger(ctx).Info().Str("Output", path).Int("NodeSets", len(in.NodeSets)).Msg("Reusing running node sets, only jobs and contracts are configured")
+	return nil
+}
+
+// matchOutputs checks the previous output has outputs for every blockchain, fake and node set of the config,
+// node sets are matched by name and must have as many nodes as the config
+func matchOutputs(in, prev *Cfg) error {
+	if len(prev.Blockchains) != len(in.Blockchains) {
+		return fmt.Errorf("expected %d blockchains, got %d", len(in.Blockchains), len(prev.Blockchains))
+	}
+	for i, bc := range in.Blockchains {
+		p := prev.Blockchains[i]
+		if p.Out == nil || len(p.Out.Nodes) == 0 {
+			return fmt.Errorf("blockchain %d has no output", i)
+		}
+		if p.ChainID != bc.ChainID {
+			return fmt.Errorf("blockchain %d chain ID is %s, expected %s", i, p.ChainID, bc.ChainID)
+		}
+	}
+	prevFakes := prev.Fakes()
+	if len(prevFakes) != len(in.Fakes()) {
+		return fmt.Errorf("expected %d fake servers, got %d", len(in.Fakes()), len(prevFakes))
+	}
+	for i, f := range prevFakes {
+		if f.Out == nil {
+			return fmt.Errorf("fake server %d has no output", i)
+		}
+	}
+	for _, nodeSet := range in.NodeSets {
+		p := prevNodeSet(prev, nodeSet.Name)
+		if p == nil || p.Out == nil {
+			return fmt.Errorf("node set %s has no output", nodeSet.Name)
+		}
+		if len(p.Out.CLNodes) != len(nodeSet.NodeSpecs) {
+			return fmt.Errorf("node set %s has %d nodes, expected %d", nodeSet.Name, len(p.Out.CLNodes), len(nodeSet.NodeSpecs))
+		}
+	}
+	return nil
+}
+
+// checkRunning checks chains, fakes and nodes of the previous output respond, nodes must accept a session login
+func checkRunning(ctx context.Context, prev *Cfg) error {
+	for _, bc := range prev.Blockchains {
+		if err := checkChainID(ctx, bc.Out.Nodes[0].ExternalHTTPUrl, bc.ChainID); err != nil {
+			return fmt.Errorf("blockchain %s is not running: %w", bc.ChainID, err)
+		}
+	}
+	for i, f := range prev.Fakes() {
+		if err := ocr2.WaitFakeServerReady(ctx, ocr2.NewFakeServerClient(f.Out.BaseURLHost, nil), ReuseCheckWait); err != nil {
+			return fmt.Errorf("fake server %d is not running: %w", i, err)
+		}
+	}
+	for _, nodeSet := range prev.NodeSets {
+		if nodeSet.Out == nil {
+			continue
+		}
+		if _, err := clclient.New(nodeSet.Out.CLNodes); err != nil {
+			return fmt.Errorf("node set %s is not running: %w", nodeSet.Name, err)
+		}
+	}
+	return nil
+}
+
+// deleteOCR2Jobs deletes OCR2 and bootstrap jobs from nodes of the previous output before new ones are created
+func deleteOCR2Jobs(ctx context.Context, prev *Cfg) error {
+	for _, nodeSet := range prev.NodeSets {
+		if nodeSet.Out == nil {
+			continue
+		}
+		nodes, err := clclient.New(nodeSet.Out.CLNodes)
+		if err != nil {
+			return fmt.Errorf("node set %s is not running: %w", nodeSet.Name, err)
+		}
+		for i, node := range nodes {
+			deleted, err := ocr2.DeleteOCR2Jobs(ctx, node)
+			if err != nil {
+				return fmt.Errorf("could not delete jobs of node %d of node set %s: %w", i, nodeSet.Name, err)
+			}
+			ctxLogger(ctx).Info().Str("NodeSet", nodeSet.Name).Int("Node", i).Int("Jobs", deleted).Msg("Deleted jobs of the previous run")
+		}
+	}
+	return nil
+}
+
+// checkChainID checks the RPC responds with the expected chain ID
+func checkChainID(ctx context.Context, rpcURL, chainID string) error {
+	c, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	id, err := c.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	expected, ok := new(big.Int).SetString(chainID, 10)
+	if !ok {
+		return fmt.Errorf("invalid chain ID %s", chainID)
+	}
+	if id.Cmp(expected) != 0 {
+		return fmt.Errorf("RPC reports chain ID %s", id)
+	}
+	return nil
+}
+
+// prevNodeSet returns node set of the previous output by name
+func prevNodeSet(prev *Cfg, name string) *ns.Input {
+	for _, nodeSet := range prev.NodeSets {
+		if nodeSet.Name == name {
+			return nodeSet
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package devenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/blockchain"
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/clnode"
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/fake"
+
+	ns "github.com/smartcontractkit/chainlink-testing-framework/framework/components/simple_node_set"
+)
+
+func TestMatchOutputs(t *testing.T) {
+	config := func() *Cfg {
+		return &Cfg{
+			Blockchains: []*blockchain.Input{{ChainID: "1337"}},
+			FakeServer:  &fake.Input{Port: 9111},
+			NodeSets:    []*ns.Input{{Name: "don", NodeSpecs: []*clnode.Input{{}, {}}}},
+		}
+	}
+	output := func() *Cfg {
+		out := config()
+		out.Blockchains[0].Out = &blockchain.Output{Nodes: []*blockchain.Node{{ExternalHTTPUrl: "http://localhost:8545"}}}
+		out.FakeServer.Out = &fake.Output{BaseURLHost: "http://localhost:9111"}
+		out.NodeSets[0].Out = &ns.Output{CLNodes: []*clnode.Output{{}, {}}}
+		return out
+	}
+	require.NoError(t, matchOutputs(config(), output()))
+
+	tests := []struct {
+		name    string
+		mutate  func(in, prev *Cfg)
+		wantErr string
+	}{
+		{name: "chain not started", mutate: func(in, prev *Cfg) { prev.Blockchains[0].Out = nil }, wantErr: "blockchain 0 has no output"},
+		{name: "other chain", mutate: func(in, prev *Cfg) { in.Blockchains[0].ChainID = "2337" }, wantErr: "blockchain 0 chain ID is 1337, expected 2337"},
+		{name: "extra chain", mutate: func(in, prev *Cfg) { in.Blockchains = append(in.Blockchains, &blockchain.Input{}) }, wantErr: "expected 2 blockchains, got 1"},
+		{name: "extra fake", mutate: func(in, prev *Cfg) { in.FakeServers = []*fake.Input{{Port: 9112}} }, wantErr: "expected 2 fake servers, got 1"},
+		{name: "fake not started", mutate: func(in, prev *Cfg) { prev.FakeServer.Out = nil }, wantErr: "fake server 0 has no output"},
+		{name: "renamed node set", mutate: func(in, prev *Cfg) { in.NodeSets[0].Name = "bootstrap" }, wantErr: "node set bootstrap has no output"},
+		{name: "scaled node set", mutate: func(in, prev *Cfg) { in.NodeSets[0].NodeSpecs = append(in.NodeSets[0].NodeSpecs, &clnode.Input{}) }, wantErr: "node set don has 2 nodes, expected 3"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			in, prev := config(), output()
+			tc.mutate(in, prev)
+			require.ErrorContains(t, matchOutputs(in, prev), tc.wantErr)
+		})
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	for configs, want := range map[string]string{
+		"env.toml,overrides.toml": "env-out.toml",
+		"env-default.toml":        "env-default-out.toml",
+		"env-cache.toml":          "env-cache.toml",
+		"-":                       "env-out.toml",
+	} {
+		t.Setenv(EnvVarTestConfigs, configs)
+		got, err := OutputPath()
+		require.NoError(t, err)
+		require.Equal(t, want, got, configs)
+	}
+	t.Setenv(EnvVarTestConfigs, "")
+	_, err := OutputPath()
+	require.Error(t, err)
+}
@@ -0,0 +1,151 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/framework"
+)
+
+const (
+	// TelemetryIngressContainerName is the name of the ingress container, nodes reach it by this name in the docker network
+	TelemetryIngressContainerName = "telemetry-ingress"
+	// DefaultTelemetryIngressPort is the port ingress listens on if port is not set
+	DefaultTelemetryIngressPort = 9090
+)
+
+// TelemetryIngress is a telemetry ingress container the environment starts before node sets, nodes and jobs of products
+// implementing TelemetryIngressUser are pointed at it, ex.: an OTI image or a stub recording what nodes send
+type TelemetryIngress struct {
+	// Image is the ingress image
+	Image string `toml:"image"`
+	// Port ingress listens on in the container, it's mapped to the same host port, ex.: to inspect received telemetry
+	Port int `toml:"port"`
+	// ServerPubKey is the CSA public key ingress serves with, nodes verify it when they connect
+	ServerPubKey string `toml:"server_pub_key"`
+	// Env is passed to the container, ex.: the private key matching server_pub_key
+	Env map[string]string `toml:"env"`
+	// Out is set once the ingress is started
+	Out *TelemetryIngressOutput `toml:"out"`
+}
+
+// TelemetryIngressOutput describes a started telemetry ingress
+type TelemetryIngressOutput struct {
+	ContainerName string `toml:"container_name"`
+	// InternalURL is reachable from node containers, ex.: telemetry-ingress:9090
+	InternalURL string `toml:"internal_url"`
+	// ExternalURL is reachable from the host, ex.: localhost:9090
+	ExternalURL string `toml:"external_url"`
+}
+
+// Validate checks image and server key are set, nil ingress is valid
+func (t *TelemetryIngress) Validate() error {
+	if t == nil {
+		return nil
+	}
+	if t.Image == "" {
+		return errors.New("telemetry_ingress image is required")
+	}
+	if t.ServerPubKey == "" {
+		return errors.New("telemetry_ingress server_pub_key is required")
+	}
+	if t.Port < 0 || t.Port > 65535 {
+		return fmt.Errorf("telemetry_ingress port must be in 0-65535 range, 0 is %d, got %d", DefaultTelemetryIngressPort, t.Port)
+	}
+	return nil
+}
+
+// port returns ingress port, DefaultTelemetryIngressPort if it's not set
+func (t *TelemetryIngress) port() int {
+	if t.Port == 0 {
+		return DefaultTelemetryIngressPort
+	}
+	return t.Port
+}
+
+// env returns container environment as KEY=VALUE pairs sorted by key
+func (t *TelemetryIngress) env() []string {
+	env := make([]string, 0, len(t.Env))
+	for k, v := range t.Env {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// NewTelemetryIngress starts telemetry ingress container in the framework network, a container left by a previous run is
+// replaced, so reused nodes reconnect to the same name. The container is labelled as framework's, teardown removes it
+func NewTelemetryIngress(ctx context.Context, in *TelemetryIngress) (*TelemetryIngressOutput, error) {
+	dc, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %w", err)
+	}
+	defer dc.Close()
+	if err := dc.ContainerRemove(ctx, TelemetryIngressContainerName, container.RemoveOptions{Force: true}); err != nil && !errdefs.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to remove telemetry ingress container: %w", err)
+	}
+	if err := pullImage(ctx, dc, in.Image); err != nil {
+		return nil, err
+	}
+	port := strconv.Itoa(in.port())
+	containerPort := nat.Port(port + "/tcp")
+	resp, err := dc.ContainerCreate(ctx,
+		&container.Config{
+			Image:        in.Image,
+			Env:          in.env(),
+			ExposedPorts: nat.PortSet{containerPort: struct{}{}},
+			Labels:       map[string]string{"framework": "ctf"},
+		},
+		&container.HostConfig{
+			PortBindings: nat.PortMap{containerPort: []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: port}}},
+		},
+		&network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				framework.DefaultNetworkName: {Aliases: []string{TelemetryIngressContainerName}},
+			},
+		},
+		nil,
+		TelemetryIngressContainerName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telemetry ingress container: %w", err)
+	}
+	if err := dc.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to start telemetry ingress container: %w", err)
+	}
+	out := &TelemetryIngressOutput{
+		ContainerName: TelemetryIngressContainerName,
+		InternalURL:   TelemetryIngressContainerName + ":" + port,
+		ExternalURL:   "localhost:" + port,
+	}
+	ctxLogger(ctx).Info().Str("Image", in.Image).Str("URL", out.ExternalURL).Msg("Telemetry ingress is started")
+	return out, nil
+}
+
+// useTelemetryIngress starts telemetry ingress if [telemetry_ingress] is configured and points the product at it,
+// it's called before node config is generated so nodes start with ingress endpoint
+func useTelemetryIngress(ctx context.Context, in *Cfg, c Product) error {
+	if in.TelemetryIngress == nil {
+		return nil
+	}
+	u, ok := c.(TelemetryIngressUser)
+	if !ok {
+		return fmt.Errorf("product type %s can't send telemetry, unset telemetry_ingress", in.ProductType)
+	}
+	out, err := NewTelemetryIngress(ctx, in.TelemetryIngress)
+	if err != nil {
+		return err
+	}
+	in.TelemetryIngress.Out = out
+	u.UseTelemetryIngress(out.InternalURL, in.TelemetryIngress.ServerPubKey)
+	return nil
+}
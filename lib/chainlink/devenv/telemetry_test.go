@@ -0,0 +1,39 @@
+package devenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelemetryIngressValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      *TelemetryIngress
+		wantErr string
+	}{
+		{name: "not set"},
+		{name: "default port", in: &TelemetryIngress{Image: "ingress:latest", ServerPubKey: "abc"}},
+		{name: "no image", in: &TelemetryIngress{ServerPubKey: "abc"}, wantErr: "telemetry_ingress image is required"},
+		{name: "no server key", in: &TelemetryIngress{Image: "ingress:latest"}, wantErr: "telemetry_ingress server_pub_key is required"},
+		{name: "invalid port", in: &TelemetryIngress{Image: "ingress:latest", ServerPubKey: "abc", Port: 70000}, wantErr: "telemetry_ingress port"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.in.Validate()
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTelemetryIngressContainerSettings(t *testing.T) {
+	in := &TelemetryIngress{Env: map[string]string{"SERVER_PRIVATE_KEY": "def", "LOG_LEVEL": "debug"}}
+	require.Equal(t, DefaultTelemetryIngressPort, in.port())
+	require.Equal(t, []string{"LOG_LEVEL=debug", "SERVER_PRIVATE_KEY=def"}, in.env())
+	in.Port = 9091
+	require.Equal(t, 9091, in.port())
+}
@@ -0,0 +1,20 @@
+package ocr2
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnswerAboveMaxInt64(t *testing.T) {
+	latest := new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(1))
+	// differs from latest only above 64 bits, both wrap to the same int64
+	answer := new(big.Int).Add(latest, new(big.Int).Lsh(big.NewInt(1), 64))
+	require.Equal(t, latest.Int64(), answer.Int64())
+
+	require.True(t, answerChanged(latest, answer))
+	require.False(t, answerChanged(latest, new(big.Int).Set(latest)))
+	require.InDelta(t, 9.223372036854775808e18, answerFloat(latest), 1e3)
+}
@@ -22,22 +22,57 @@ import (
 
 func TestLoad(t *testing.T) {
 	ctx := context.Background()
-	outputFile := "../../env-out.toml"
+	outputFile := products.ArtifactPath("../../env-out.toml")
 	in, err := de.LoadOutput[de.Cfg](outputFile)
 	require.NoError(t, err)
 	pdConfig, err := products.LoadOutput[ocr2.Configurator](outputFile)
 	require.NoError(t, err)
 
 	t.Cleanup(func() {
-		_, cErr := framework.SaveContainerLogs(fmt.Sprintf("%s-%s", framework.DefaultCTFLogsDir, t.Name()))
+		logsDir := products.ArtifactPath(fmt.Sprintf("%s-%s", framework.DefaultCTFLogsDir, t.Name()))
+		_, cErr := framework.SaveContainerLogs(logsDir)
+		require.NoError(t, cErr)
+		// a feed can fail because of the data source or the chain, not only the DON
+		_, cErr = de.SaveEnvironmentLogs(ctx, in, logsDir)
 		require.NoError(t, cErr)
 	})
 	c, _, _, err := ocr2.ETHClient(ctx, in.Blockchains[0].Out.Nodes[0].ExternalWSUrl, pdConfig.OCR2.GasSettings.FeeCapMultiplier, pdConfig.OCR2.GasSettings.TipCapMultiplier)
 	require.NoError(t, err)
+	// keep gas spike timing in sync with actual block production
+	Mining, err = ocr2.AnvilMiningMode(in.Blockchains[0].DockerCmdParamsOverrides)
+	require.NoError(t, err)
+	BlockEvery = Mining.BlockTime
+	err = ocr2.SetAnvilIntervalMining(ctx, c, BlockEvery)
+	require.NoError(t, err)
+	// Anvil has no reorgs, confirmed reads are only needed on testnets
+	ReadConfirmations = pdConfig.OCR2.VerificationConfirmations
+	HeadReader = c
+	TransmissionsReader = c
+	Miner = ocr2.NewAnvilMiner(c.Client())
+	// test cases run against the primary feed, its answers are decoded with its own decimals and checked against its own bounds
+	feedSpecs, err := pdConfig.OCR2.FeedSpecs()
+	require.NoError(t, err)
+	feeds := pdConfig.OCR2.DeployedContracts.AllFeeds()
+	require.NotEmpty(t, feeds)
+	AnswerDecimals = feeds[0].Decimals
 	clNodes, err := clclient.New(in.NodeSets[0].Out.CLNodes)
 	require.NoError(t, err)
+	JobNodes = clNodes
+	ActiveSetConfig = pdConfig.OCR2.SetConfigOptions()
 
-	anvilClient := rpc.New(in.Blockchains[0].Out.Nodes[0].ExternalHTTPUrl, nil)
+	anvilURL, err := ocr2.RecordRPCURL(in.Blockchains[0].Out.Nodes[0].ExternalHTTPUrl)
+	require.NoError(t, err)
+	anvilClient := rpc.New(anvilURL, nil)
+	jobsFake, err := pdConfig.OCR2.Jobs.SelectFake(in.Fakes())
+	require.NoError(t, err)
+	fakeClient := ocr2.NewFakeServerClient(jobsFake.Out.BaseURLHost, pdConfig.OCR2.EAFake)
+	// juels ratio follows base fee, so billing reflects gas spikes
+	BaseFeeReader = c
+	err = ocr2.SetJuelsMode(fakeClient, ocr2.JuelsModeGasLinked)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, ocr2.SetJuelsMode(fakeClient, ocr2.JuelsModeConstant))
+	})
 
 	// this config must be as close to production as possible
 	productionCfg := &ocr2.OCRv2SetConfigOptions{
@@ -53,6 +88,13 @@ func TestLoad(t *testing.T) {
 		MaxDurationShouldTransmitAcceptedReport: 5 * time.Second,
 	}
 
+	// rounds are triggered as soon as the previous one is reported, so delta round bounds how often they are produced
+	slowRoundsCfg := *productionCfg
+	slowRoundsCfg.DeltaProgress = 30 * time.Second
+	slowRoundsCfg.DeltaRound = 15 * time.Second
+	fastRoundsCfg := *productionCfg
+	fastRoundsCfg.DeltaRound = 2 * time.Second
+
 	testCases := []testcase{
 		{
 			name:               "clean",
@@ -60,6 +102,7 @@ func TestLoad(t *testing.T) {
 			roundTimeout:       2 * time.Minute,
 			repeat:             2,
 			cfg:                productionCfg,
+			watchEvents:        true,
 			roundSettings: []*roundSettings{
 				{value: 1},
 				{value: 1e3},
@@ -85,6 +128,7 @@ func TestLoad(t *testing.T) {
 						rampSeconds:    2,
 						holdSeconds:    5,
 						releaseSeconds: 2,
+						manualMining:   true,
 					},
 				},
 				{
@@ -95,47 +139,192 @@ func TestLoad(t *testing.T) {
 						rampSeconds:    2,
 						holdSeconds:    5,
 						releaseSeconds: 2,
+						manualMining:   true,
 					},
 				},
 			},
 		},
 		{
-			name:               "chaos",
+			// maximum_answer in env.toml is 5e16, out of range medians are rejected and never reported
+			name:               "min max bounds",
 			roundCheckInterval: 5 * time.Second,
 			roundTimeout:       2 * time.Minute,
-			repeat:             2,
+			repeat:             1,
 			roundSettings: []*roundSettings{
-				// these are just Pumba tool commands, read more here https://github.com/alexei-led/pumba
-				{
-					value: 1,
-					chaos: &chaosSettings{
-						command:          "stop --duration=10s --restart re2:don-node0",
-						recoveryWaitTime: 10 * time.Second,
-					},
-				},
-				{
-					value: 1e3,
-					chaos: &chaosSettings{
-						command:          "netem --tc-image=gaiadocker/iproute2 --duration=10s delay --time=1000 re2:don-node.*",
-						recoveryWaitTime: 10 * time.Second,
-					},
+				{value: 1e3},
+				{value: 6e16},
+				{value: 1e5},
+			},
+		},
+		{
+			// node 1 job is disabled for two rounds, remaining nodes keep the feed going and node 1 must be observed again once re-enabled
+			name:               "pause worker",
+			roundCheckInterval: 5 * time.Second,
+			roundTimeout:       2 * time.Minute,
+			repeat:             1,
+			roundSettings: []*roundSettings{
+				{value: 1},
+				{value: 1e3, pause: &pauseSettings{node: 1}},
+				{value: 1e5},
+				{value: 1e7, pause: &pauseSettings{node: 1, resume: true}},
+				{value: 1e9},
+			},
+		},
+		{
+			name:               "delta round",
+			roundCheckInterval: 5 * time.Second,
+			roundTimeout:       3 * time.Minute,
+			repeat:             1,
+			cfg:                &slowRoundsCfg,
+			watchEvents:        true,
+			roundSettings: []*roundSettings{
+				{value: 1},
+				{value: 1e3},
+				{value: 1e5},
+				{value: 1e7},
+				{value: 1e9},
+			},
+			cadence: &cadenceSettings{
+				cfg: &fastRoundsCfg,
+				// round settings report 5 rounds, the one pending while the config goes live is dropped and the next may still follow the old config
+				check: ocr2.CadenceCheck{Expect: ocr2.CadenceFaster, Rounds: 3},
+			},
+		},
+		{
+			// fake EA answers right past max_duration_observation, nodes drop late observations, so the value is never reported,
+			// right under the boundary observations make it in time again
+			name:               "observation boundary",
+			roundCheckInterval: 10 * time.Second,
+			roundTimeout:       3 * time.Minute,
+			repeat:             1,
+			cfg:                productionCfg,
+			roundSettings: []*roundSettings{
+				{value: 1e3},
+				{value: 1e5, latency: &latencySettings{late: true, margin: 500 * time.Millisecond}},
+				{value: 1e7, latency: &latencySettings{margin: time.Second}},
+				{value: 1e9},
+			},
+		},
+		{
+			name:               "random walk",
+			roundCheckInterval: 5 * time.Second,
+			repeat:             1,
+			profile: &profileSettings{
+				ea: &ocr2.EAProfile{
+					Kind:     "random_walk",
+					Start:    1e5,
+					Step:     1e3,
+					Min:      5e4,
+					Max:      2e5,
+					Interval: 5 * time.Second,
+					Duration: 2 * time.Minute,
 				},
+				tolerance:        0.05,
+				minTrackingRatio: 0.8,
 			},
 		},
 	}
+	// "cl test --rmax=5 --delta-round=10s" overrides are layered over test cases that apply a new config
+	overrides, err := ocr2.SetConfigOverridesFromEnv()
+	require.NoError(t, err)
+	for i := range testCases {
+		if testCases[i].cadence != nil && overrides != nil && overrides.DeltaRound != nil {
+			// both configs would have the same delta round, so cadence can't change
+			L.Warn().Str("TestCase", testCases[i].name).Msg("Delta round is overridden, skipping cadence check")
+			testCases[i].cadence = nil
+		}
+		if testCases[i].cfg == nil {
+			continue
+		}
+		testCases[i].cfg, err = overrides.Apply(testCases[i].cfg)
+		require.NoError(t, err)
+		if testCases[i].cadence != nil {
+			testCases[i].cadence.cfg, err = overrides.Apply(testCases[i].cadence.cfg)
+			require.NoError(t, err)
+		}
+	}
+	// chaos experiments come from [[ocr2.chaos]], they are translated before any test case runs so a bad spec fails fast
+	chaosRounds, err := chaosRoundSettings(pdConfig.OCR2.Chaos, in.NodeSets[0].Name)
+	require.NoError(t, err)
+	if len(chaosRounds) > 0 {
+		testCases = append(testCases, testcase{
+			name:               "chaos",
+			roundCheckInterval: 5 * time.Second,
+			roundTimeout:       2 * time.Minute,
+			repeat:             2,
+			roundSettings:      chaosRounds,
+		})
+	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			start := time.Now()
+			eaRequests := fakeEARequests(t, fakeClient)
 			o2, err := ocr2aggregator.NewOCR2Aggregator(common.HexToAddress(pdConfig.OCR2.DeployedContracts.OCRv2AggregatorAddr), c)
 			require.NoError(t, err)
+			before, err := ocr2.LatestConfigDigest(ctx, o2)
+			require.NoError(t, err)
 			L.Info().Any("Config", tc.cfg).Msg("Applying new OCR2 configuration")
-			err = ocr2.UpdateOCR2ConfigOffChainValues(context.Background(), in.Blockchains[0], pdConfig.OCR2, o2, clNodes, tc.cfg)
+			after, err := ocr2.UpdateOCR2ConfigOffChainValues(ctx, in.Blockchains[0], pdConfig.OCR2, o2, clNodes, tc.cfg)
+			require.NoError(t, err)
+			assertDigestChanged(t, before, after, tc.cfg != nil)
+			if tc.cfg != nil {
+				ActiveSetConfig = tc.cfg
+				// applying the same config again must not send setConfig, config count on the aggregator stays the same
+				countBefore, err := ocr2.LatestConfigCount(ctx, o2)
+				require.NoError(t, err)
+				again, err := ocr2.UpdateOCR2ConfigOffChainValues(ctx, in.Blockchains[0], pdConfig.OCR2, o2, clNodes, tc.cfg)
+				require.NoError(t, err)
+				assertDigestChanged(t, after, again, false)
+				countAfter, err := ocr2.LatestConfigCount(ctx, o2)
+				require.NoError(t, err)
+				require.Equal(t, countBefore, countAfter, "same config must not be set on the aggregator again")
+			}
+			assertNodesAgreeOnConfig(ctx, t, in.NodeSets[0].Out.CLNodes, o2.Address(), after)
+			// epoch is reset by a new config, so it's read after config is applied
+			startEpoch, err := ocr2.LatestEpoch(ctx, o2)
 			require.NoError(t, err)
+			var rounds []roundData
 			for range tc.repeat {
-				verifyRounds(t, in, o2, tc, anvilClient)
+				if tc.profile != nil {
+					verifyProfile(t, fakeClient, o2, tc)
+					continue
+				}
+				rounds = verifyRounds(t, fakeClient, o2, tc, anvilClient, feedSpecs[0].Options)
 			}
-			checkResourceConsumption(t, in, start, time.Now(), 10.0, 400e6)
+			if tc.cadence != nil {
+				// the cadence config only lives for the check, the test case config is restored as is afterwards
+				stored, storedCfg := pdConfig.OCR2.OCR2SetConfigOut, ActiveSetConfig
+				// the same rounds are repeated under a new config, only rounds reported once it's live on all nodes are measured
+				digest, err := ocr2.UpdateOCR2ConfigOffChainValues(ctx, in.Blockchains[0], pdConfig.OCR2, o2, clNodes, tc.cadence.cfg)
+				require.NoError(t, err)
+				ActiveSetConfig = tc.cadence.cfg
+				assertNodesAgreeOnConfig(ctx, t, in.NodeSets[0].Out.CLNodes, o2.Address(), digest)
+				// rounds are filtered by chain time, block timestamps don't follow host clock
+				liveBlock, err := c.HeaderByNumber(ctx, nil)
+				require.NoError(t, err)
+				after := verifyRounds(t, fakeClient, o2, tc, anvilClient, feedSpecs[0].Options)
+				assertCadenceChanged(t, tc.cadence.check, rounds, ocr2.RoundsSince(after, liveBlock))
+				restored, err := ocr2.ApplyStoredConfig(ctx, in.Blockchains[0], pdConfig.OCR2, o2, stored)
+				require.NoError(t, err)
+				ActiveSetConfig = storedCfg
+				assertDigestChanged(t, digest, restored, true)
+				assertNodesAgreeOnConfig(ctx, t, in.NodeSets[0].Out.CLNodes, o2.Address(), restored)
+			}
+			requireNodesResumed(t, fakeClient, o2, pdConfig.OCR2.DeployedContracts, in.Blockchains[0].Out.ChainID, tc.roundCheckInterval)
+			end := time.Now()
+			checkEpochsAdvance(t, o2, startEpoch)
+			checkResourceConsumption(t, in, start, end, 10.0, 400e6)
+			workers, err := pdConfig.OCR2.Bootstrap.WorkerNodes(in.NodeSets[0], in.NodeSets)
+			require.NoError(t, err)
+			checkNodesTransmit(t, workers, start, end)
+			checkFakeServed(t, fakeClient, eaRequests)
 		})
 	}
+	t.Run("feeds", func(t *testing.T) {
+		checkFeedsReport(t, c, feeds, feedSpecs)
+	})
+	t.Run("gas budget", func(t *testing.T) {
+		requireGasWithinBudget(t, pdConfig.OCR2.GasBudget, TransmissionGas)
+	})
 }
@@ -1,35 +1,86 @@
 package ocr2
 
 import (
+	"context"
 	"fmt"
 	"math/big"
+	"regexp"
+	"slices"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/go-resty/resty/v2"
 
 	"github.com/smartcontractkit/libocr/gethwrappers2/ocr2aggregator"
+	"github.com/smartcontractkit/libocr/offchainreporting2/types"
 	"github.com/stretchr/testify/require"
 
 	"github.com/smartcontractkit/chainlink-testing-framework/framework/chaos"
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/clclient"
+	"github.com/smartcontractkit/chainlink-testing-framework/framework/components/clnode"
 	"github.com/smartcontractkit/chainlink-testing-framework/framework/rpc"
 	de "github.com/smartcontractkit/chainlink/devenv"
+	"github.com/smartcontractkit/chainlink/devenv/products"
 	"github.com/smartcontractkit/chainlink/devenv/products/ocr2"
 
 	f "github.com/smartcontractkit/chainlink-testing-framework/framework"
 )
 
 var (
-	L          = ocr2.L
-	BlockEvery = 1 * time.Second
+	L = ocr2.L
+	// BlockEvery is Anvil block time, it's set from docker_cmd_params in test setup
+	BlockEvery = ocr2.DefaultAnvilBlockTime
+	// Mining is Anvil mining mode manual mining restores, it's set from docker_cmd_params in test setup
+	Mining = ocr2.AnvilMining{BlockTime: ocr2.DefaultAnvilBlockTime}
+
+	nodeInstanceRe = regexp.MustCompile(`don-node(\d+)`)
+	// outOfRangeChecks is how many round check intervals we wait to ensure out of range value is not reported
+	outOfRangeChecks = 3
+	// maxRoundReadFailures is how many consecutive failed round reads verifyRounds tolerates while backing off
+	maxRoundReadFailures = 10
+	// defaultRoundCheckBackoff is how much round check interval can widen if roundCheckMaxInterval is not set
+	defaultRoundCheckBackoff = 4
+	// configPropagationTimeout is how long nodes have to switch to a new config, they poll the contract every 5s
+	configPropagationTimeout = time.Minute
 
 	TotalRoundsPerTestCount = int64(0)
 	LatestRound             = int64(0)
-	LatestRoundAnswer       = int64(0)
+	// ReadConfirmations is how many blocks behind the head aggregator rounds are read, it's set from verification_confirmations in test setup
+	ReadConfirmations = uint64(0)
+	// HeadReader reads the latest block number for confirmed reads, it's set in test setup
+	HeadReader ocr2.BlockNumberReader
+	// Miner switches Anvil to manual mining for gas spikes with manualMining, it's set in test setup
+	Miner *ocr2.AnvilMiner
+	// BaseFeeReader reads the latest base fee pushed to fake every round for gas_linked juels, pushing is skipped if it's nil
+	BaseFeeReader ocr2.HeaderReader
+	// TransmissionsReader reads transmit transactions of rounds and their receipts, it's set in test setup
+	TransmissionsReader ocr2.TransmissionReader
+	// TransmissionGas accumulates gas used by transmissions of every test case, it's checked against [ocr2.gas_budget] at the end of the run
+	TransmissionGas ocr2.GasStats
+
+	// LatestRoundAnswer is kept as *big.Int, answers of high value feeds don't fit into int64
+	LatestRoundAnswer = new(big.Int)
+	// AnswerDecimals are decimals of the feed under test answers are decoded with, it's set in test setup
+	AnswerDecimals = uint8(0)
+	// JobNodes are nodes pause rounds disable and enable OCR2 jobs on, by node set index, it's set in test setup
+	JobNodes []*clclient.ChainlinkClient
+	// pausedJobs are OCR2 job IDs disabled by pause rounds by node index, resumedNodes are blocks paused nodes were re-enabled at
+	pausedJobs   = make(map[int]string)
+	resumedNodes = make(map[int]uint64)
+	// ActiveSetConfig is the set config nodes run, latency rounds derive observation boundary from it,
+	// it's set in test setup and updated by test cases applying a config
+	ActiveSetConfig *ocr2.OCRv2SetConfigOptions
+	// resumeTimeout is how long a re-enabled node has to get its observations into reports again
+	resumeTimeout = 2 * time.Minute
 )
 
+// roundData is aggregator round as returned by LatestRoundData
+type roundData = ocr2.RoundData
+
 type chaosSettings struct {
 	command          string
 	recoveryWaitTime time.Duration
@@ -41,12 +92,46 @@ type gasSettings struct {
 	rampSeconds    int
 	holdSeconds    int
 	releaseSeconds int
+	// manualMining mines exactly one block per step instead of sleeping BlockEvery, so fee progression doesn't race the block timer
+	manualMining bool
+}
+
+// pauseSettings disables the OCR2 job of a worker node the way an operator pausing it would, unlike chaos the node keeps running
+type pauseSettings struct {
+	// node is the index of the worker node in the node set, node 0 is the bootstrap node
+	node int
+	// resume re-enables the job disabled by an earlier round instead of disabling it
+	resume bool
+}
+
+// latencySettings delays fake EA responses around max_duration_observation of ActiveSetConfig, latency is kept for later rounds
+// and reset when the test case ends
+type latencySettings struct {
+	// late sets latency margin past the boundary, nodes drop observations and the value must not be reported,
+	// otherwise latency is margin under the boundary and the value is reported
+	late   bool
+	margin time.Duration
 }
 
 type roundSettings struct {
-	value int
-	gas   *gasSettings
-	chaos *chaosSettings
+	value   int
+	gas     *gasSettings
+	chaos   *chaosSettings
+	pause   *pauseSettings
+	latency *latencySettings
+}
+
+// dropsObservations reports whether observations of the round value are late and must not be reported
+func (s *roundSettings) dropsObservations() bool {
+	return s.latency != nil && s.latency.late
+}
+
+type profileSettings struct {
+	ea *ocr2.EAProfile
+	// tolerance is max relative difference between on-chain answer and EA value, ex.: 0.05 is 5%
+	tolerance float64
+	// minTrackingRatio is a min ratio of samples within tolerance, on-chain answer always lags behind EA value
+	minTrackingRatio float64
 }
 
 type testcase struct {
@@ -55,115 +140,559 @@ type testcase struct {
 	roundTimeout       time.Duration
 	repeat             int
 	roundSettings      []*roundSettings
+	profile            *profileSettings
 	cfg                *ocr2.OCRv2SetConfigOptions
+	// roundCheckMaxInterval bounds how far round check interval widens on slow or failing RPC, defaultRoundCheckBackoff times roundCheckInterval if it's 0
+	roundCheckMaxInterval time.Duration
+	// maxTransmissionsPerRound bounds on-chain transmissions of every round, ocr2.DefaultMaxTransmissionsPerRound is used if it's 0
+	maxTransmissionsPerRound int
+	// watchEvents checks rounds as soon as AnswerUpdated is emitted, the check interval is kept as a fallback for missed events
+	watchEvents bool
+	// cadence applies another config after the test case rounds and checks rounds under it change cadence as expected
+	cadence *cadenceSettings
+}
+
+// cadenceSettings checks a config change takes effect behaviourally, not only by digest
+type cadenceSettings struct {
+	cfg   *ocr2.OCRv2SetConfigOptions
+	check ocr2.CadenceCheck
+}
+
+// simulateGasSpike is changing next block gas base fee in 3 steps: ramp, hold and release simulating a gas spike,
+// every base fee is pushed to fake too, so gas_linked juels source follows the spike
+func simulateGasSpike(t *testing.T, fc *resty.Client, r *rpc.RPCClient, g *gasSettings) {
+	ctx := context.Background()
+	gc := ocr2.NewGasController(r)
+	gc.OnBaseFee = func(fee *big.Int) error { return ocr2.SetBaseFee(fc, fee) }
+	spike := func() error {
+		if err := gc.RampBaseFee(ctx, g.gasPriceStart, g.gasPriceBump, g.rampSeconds, BlockEvery); err != nil {
+			return err
+		}
+		if err := gc.HoldBaseFee(ctx, ocr2.RampPeak(g.gasPriceStart, g.gasPriceBump, g.rampSeconds), g.holdSeconds, BlockEvery); err != nil {
+			return err
+		}
+		return gc.ReleaseBaseFee(ctx, g.releaseSeconds, BlockEvery)
+	}
+	if !g.manualMining || Miner == nil {
+		require.NoError(t, spike())
+		return
+	}
+	gc.Miner = Miner
+	require.NoError(t, Miner.ManualMining(ctx, Mining, spike))
+}
+
+// answerChanged compares answers with full precision, converting to int64 silently wraps answers above math.MaxInt64
+func answerChanged(latest, answer *big.Int) bool {
+	return latest.Cmp(answer) != 0
+}
+
+// answerFloat decodes answer with AnswerDecimals for human-readable logging only, comparisons must use *big.Int
+func answerFloat(answer *big.Int) float64 {
+	return ocr2.AnswerFloat(answer, AnswerDecimals)
+}
+
+// latestRoundData reads the latest round ReadConfirmations blocks behind the head, rounds that can still be reorged out are not visible
+func latestRoundData(t *testing.T, o2 *ocr2aggregator.OCR2Aggregator) roundData {
+	t.Helper()
+	rd, err := readLatestRound(o2)
+	require.NoError(t, err)
+	return rd
+}
+
+// readLatestRound is latestRoundData returning RPC errors, so callers can retry them
+func readLatestRound(o2 *ocr2aggregator.OCR2Aggregator) (roundData, error) {
+	opts := &bind.CallOpts{}
+	if ReadConfirmations > 0 {
+		var err error
+		opts, err = ocr2.ConfirmedCallOpts(context.Background(), HeadReader, ReadConfirmations)
+		if err != nil {
+			return roundData{}, err
+		}
+	}
+	return o2.LatestRoundData(opts)
+}
+
+// roundCheckBackoff returns adaptive round check interval of the test case, it starts at roundCheckInterval
+func (tc testcase) roundCheckBackoff() *ocr2.AdaptiveInterval {
+	maxInterval := tc.roundCheckMaxInterval
+	if maxInterval == 0 {
+		maxInterval = defaultRoundCheckBackoff * tc.roundCheckInterval
+	}
+	// a read slower than the base interval means RPC is struggling
+	return ocr2.NewAdaptiveInterval(tc.roundCheckInterval, maxInterval, tc.roundCheckInterval)
+}
+
+// inAnswerRange checks value against configured minimum/maximum answer, nil bounds are not checked
+func inAnswerRange(value *big.Int, bounds *ocr2.OCRv2OffChainOptions) bool {
+	if bounds == nil {
+		return true
+	}
+	if bounds.MinimumAnswer != nil && value.Cmp(bounds.MinimumAnswer) < 0 {
+		return false
+	}
+	if bounds.MaximumAnswer != nil && value.Cmp(bounds.MaximumAnswer) > 0 {
+		return false
+	}
+	return true
 }
 
-// simulateGasSpike is changing next block gas base fee in 3 steps: ramp, hold and release simulating a gas spike
-func simulateGasSpike(t *testing.T, r *rpc.RPCClient, g *gasSettings) {
-	currentGasPrice := g.gasPriceStart
-	for i := 0; i < g.rampSeconds; i++ {
-		err := r.PrintBlockBaseFee()
+// chaosValues are EA values set in chaos rounds, consecutive values differ so every round produces a new answer
+var chaosValues = []int{1, 1e3, 1e5, 1e7}
+
+// chaosRoundSettings translates chaos specs to round settings, one round per spec
+func chaosRoundSettings(specs []*ocr2.ChaosSpec, nodeSet string) ([]*roundSettings, error) {
+	rs := make([]*roundSettings, 0, len(specs))
+	for i, spec := range specs {
+		cmd, err := spec.PumbaCommand(nodeSet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chaos spec %d: %w", i, err)
+		}
+		rs = append(rs, &roundSettings{
+			value: chaosValues[i%len(chaosValues)],
+			chaos: &chaosSettings{
+				command:          cmd,
+				recoveryWaitTime: spec.RecoveryWait(),
+			},
+		})
+	}
+	return rs, nil
+}
+
+// applyRoundSettings sets next EA value and runs gas or chaos experiments for the next round
+func applyRoundSettings(t *testing.T, fc *resty.Client, c *rpc.RPCClient, s *roundSettings) {
+	L.Info().
+		Int("Value", s.value).
+		Msg("Settings new value for EA")
+	// latency is set before the value, so the value is never observed with previous latency
+	if s.latency != nil {
+		setObservationLatency(t, fc, s.latency)
+	}
+	err := ocr2.TriggerDeviation(fc, s.value)
+	require.NoError(t, err, "could not set ea fake value")
+	if BaseFeeReader != nil {
+		fee, err := ocr2.LatestBaseFee(context.Background(), BaseFeeReader)
 		require.NoError(t, err)
-		t.Logf("Setting block base fee: %d", currentGasPrice)
-		err = r.AnvilSetNextBlockBaseFeePerGas(currentGasPrice)
+		err = ocr2.SetBaseFee(fc, fee)
+		require.NoError(t, err, "could not push base fee to ea fake")
+	}
+	// apply varios chaos experiments for next round
+	if s.gas != nil {
+		L.Info().Msg("Creating gas spike")
+		simulateGasSpike(t, fc, c, s.gas)
+	}
+	if s.chaos != nil {
+		L.Info().Msg("Executing chaos action")
+		_, err = chaos.ExecPumba(
+			s.chaos.command,
+			s.chaos.recoveryWaitTime,
+		)
 		require.NoError(t, err)
-		currentGasPrice = currentGasPrice.Add(currentGasPrice, g.gasPriceBump)
-		time.Sleep(BlockEvery)
 	}
-	for i := 0; i < g.holdSeconds; i++ {
-		err := r.PrintBlockBaseFee()
+	if s.pause != nil {
+		pauseJob(t, s.pause)
+	}
+}
+
+// setObservationLatency sets fake EA latency just past or under max_duration_observation of the active config
+func setObservationLatency(t *testing.T, fc *resty.Client, l *latencySettings) {
+	late, onTime, err := ActiveSetConfig.ObservationLatency(l.margin)
+	require.NoError(t, err)
+	latency := onTime
+	if l.late {
+		latency = late
+	}
+	L.Info().
+		Dur("Latency", latency).
+		Dur("MaxDurationObservation", ActiveSetConfig.MaxDurationObservation).
+		Bool("Late", l.late).
+		Msg("Setting EA latency")
+	require.NoError(t, ocr2.SetLatency(fc, latency), "could not set ea fake latency")
+	t.Cleanup(func() {
+		require.NoError(t, ocr2.SetLatency(fc, 0))
+	})
+}
+
+// pauseJob disables or re-enables the OCR2 job of a worker node, a job left disabled by a failed test is re-enabled on cleanup
+func pauseJob(t *testing.T, p *pauseSettings) {
+	ctx := context.Background()
+	require.Less(t, p.node, len(JobNodes), "no node %d to pause", p.node)
+	node := JobNodes[p.node]
+	if p.resume {
+		id, ok := pausedJobs[p.node]
+		require.True(t, ok, "job of node %d is not paused", p.node)
+		L.Info().Int("Node", p.node).Msg("Resuming OCR2 job")
+		_, err := ocr2.EnableJob(ctx, node, id)
 		require.NoError(t, err)
-		time.Sleep(BlockEvery)
-		t.Logf("Setting block base fee: %d", currentGasPrice)
-		err = r.AnvilSetNextBlockBaseFeePerGas(currentGasPrice)
+		delete(pausedJobs, p.node)
+		head, err := HeadReader.BlockNumber(ctx)
 		require.NoError(t, err)
+		resumedNodes[p.node] = head
+		return
 	}
-	for i := 0; i < g.releaseSeconds; i++ {
-		err := r.PrintBlockBaseFee()
+	jobs, err := ocr2.ListJobs(ctx, node)
+	require.NoError(t, err)
+	idx := slices.IndexFunc(jobs, func(j ocr2.JobSummary) bool { return j.Type == ocr2.JobTypeOCR2 })
+	require.GreaterOrEqual(t, idx, 0, "node %d has no OCR2 job", p.node)
+	L.Info().Int("Node", p.node).Str("JobID", jobs[idx].ID).Msg("Pausing OCR2 job")
+	require.NoError(t, ocr2.DisableJob(ctx, node, jobs[idx].ID))
+	pausedJobs[p.node] = jobs[idx].ID
+	t.Cleanup(func() {
+		if id, ok := pausedJobs[p.node]; ok {
+			_, err := ocr2.EnableJob(ctx, node, id)
+			require.NoError(t, err)
+			delete(pausedJobs, p.node)
+		}
+	})
+}
+
+// requireNodesResumed checks every node re-enabled by pause rounds gets its observations into reports again,
+// new EA values are set until a report includes them, so the check doesn't depend on rounds left in the test case
+func requireNodesResumed(t *testing.T, fc *resty.Client, o2 *ocr2aggregator.OCR2Aggregator, deployed *ocr2.DeployedContracts, chainID string, checkInterval time.Duration) {
+	t.Helper()
+	defer clear(resumedNodes)
+	ctx := context.Background()
+	for idx, fromBlock := range resumedNodes {
+		key, err := JobNodes[idx].ReadPrimaryETHKey(chainID)
 		require.NoError(t, err)
-		time.Sleep(BlockEvery)
+		oracle, err := deployed.OracleIndex(ctx, o2, common.HexToAddress(key.Attributes.Address))
+		require.NoError(t, err)
+		deadline := time.Now().Add(resumeTimeout)
+		for i := 0; ; i++ {
+			included, err := ocr2.ObservationIncluded(ctx, o2, fromBlock, oracle)
+			require.NoError(t, err)
+			if included {
+				L.Info().Int("Node", idx).Int("Oracle", oracle).Msg("Resumed node observations are reported again")
+				break
+			}
+			require.True(t, time.Now().Before(deadline), "node %d (oracle %d) observations are not reported %s after its job was re-enabled", idx, oracle, resumeTimeout)
+			require.NoError(t, ocr2.TriggerDeviation(fc, chaosValues[i%len(chaosValues)]))
+			time.Sleep(checkInterval)
+		}
+	}
+}
+
+// requireAnswerHeld checks that an out of min/max range or late observed EA value is never reported,
+// median plugin and aggregator reject such reports so the answer stays at the latest in range value and never exceeds bounds
+func requireAnswerHeld(t *testing.T, o2 *ocr2aggregator.OCR2Aggregator, answer *big.Int, bounds *ocr2.OCRv2OffChainOptions, checkInterval time.Duration) {
+	for range outOfRangeChecks {
+		time.Sleep(checkInterval)
+		rd := latestRoundData(t, o2)
+		require.True(t, inAnswerRange(rd.Answer, bounds), "answer %s is out of min/max range", rd.Answer)
+		require.False(t, answerChanged(answer, rd.Answer), "out of range or late value must not be reported, expected %s, got %s", answer, rd.Answer)
 	}
 }
 
 // verifyRounds is a main test loop that applies EA deviations, chaos and verifier that eventually next round is still published on-chain
-func verifyRounds(t *testing.T, in *de.Cfg, o2 *ocr2aggregator.OCR2Aggregator, tc testcase, c *rpc.RPCClient) {
-	roundTicker := time.NewTicker(tc.roundCheckInterval)
-	defer roundTicker.Stop()
-
-	rounds := make([]struct {
-		RoundId         *big.Int //nolint:revive // we can't change this field in generated binding
-		Answer          *big.Int
-		StartedAt       *big.Int
-		UpdatedAt       *big.Int
-		AnsweredInRound *big.Int
-	}, 0)
+// values outside of configured min/max bounds are not expected to produce a new round, the answer must stay at the previous value
+// round checks back off on slow or failing RPC and speed back up once reads are healthy, detected rounds are returned,
+// held answers count toward round settings but aren't returned, so only reported rounds are checked for transmissions and cadence
+func verifyRounds(t *testing.T, fc *resty.Client, o2 *ocr2aggregator.OCR2Aggregator, tc testcase, c *rpc.RPCClient, bounds *ocr2.OCRv2OffChainOptions) []roundData {
+	interval := tc.roundCheckBackoff()
+	roundTimer := time.NewTimer(interval.Current())
+	defer roundTimer.Stop()
+
+	rounds := make([]roundData, 0)
+	// held is how many round settings were checked to hold the answer instead of reporting a round
+	held := 0
 	defer func() { TotalRoundsPerTestCount = 0 }()
+	// rounds reported before the loop are not counted, confirmed reads lag the head so events are searched from the confirmed block
+	head, err := HeadReader.BlockNumber(context.Background())
+	require.NoError(t, err)
+	fromBlock := uint64(0)
+	if head > ReadConfirmations {
+		fromBlock = head - ReadConfirmations
+	}
+	startRound := latestRoundData(t, o2).RoundId.Uint64()
+	answerEvents, stopWatch := watchAnswers(o2, tc.watchEvents)
+	defer stopWatch()
 
 	for {
 		select {
+		case <-answerEvents:
+			// Go 1.23+ timers have no stale ticks, so reset fires the round check right away
+			roundTimer.Reset(0)
 		case <-time.After(tc.roundTimeout):
 			L.Warn().Msgf("timeout reached, goal of %d rounds is not complete!", len(tc.roundSettings))
-			return
-		case <-roundTicker.C:
+			return rounds
+		case <-roundTimer.C:
 			L.Trace().
 				Msg("checking for new rounds")
-			currentRoundSettings := tc.roundSettings[TotalRoundsPerTestCount]
 
-			rd, err := o2.LatestRoundData(&bind.CallOpts{})
-			require.NoError(t, err)
+			start := time.Now()
+			rd, err := readLatestRound(o2)
+			next := interval.Observe(err, time.Since(start))
+			roundTimer.Reset(next)
+			if err != nil {
+				require.Less(t, interval.ConsecutiveFailures(), maxRoundReadFailures, "could not read the latest round: %s", err)
+				L.Warn().Err(err).Dur("NextCheck", next).Msg("Could not read the latest round, backing off")
+				continue
+			}
 
-			if rd.Answer.Int64() != LatestRoundAnswer {
+			if answerChanged(LatestRoundAnswer, rd.Answer) {
 				LatestRound = rd.RoundId.Int64()
-				LatestRoundAnswer = rd.Answer.Int64()
+				LatestRoundAnswer = new(big.Int).Set(rd.Answer)
 				rounds = append(rounds, rd)
 				L.Info().
 					Int64("RoundID", rd.RoundId.Int64()).
-					Int64("Answer", rd.Answer.Int64()).
+					Float64("Answer", answerFloat(rd.Answer)).
 					Msg("New round data")
 
-				// apply next value deviation
-				L.Info().
-					Int("Value", currentRoundSettings.value).
-					Msg("Settings new value for EA")
-				r := resty.New().SetBaseURL(in.FakeServer.Out.BaseURLHost)
-				_, err := r.R().Post(
-					fmt.Sprintf(
-						`/trigger_deviation?result=%d`, currentRoundSettings.value,
-					),
-				)
-				// apply varios chaos experiments for next round
-				if currentRoundSettings.gas != nil {
-					L.Info().Msg("Creating gas spike")
-					simulateGasSpike(t, c, currentRoundSettings.gas)
-				}
-				if currentRoundSettings.chaos != nil {
-					L.Info().Msg("Executing chaos action")
-					_, err = chaos.ExecPumba(
-						currentRoundSettings.chaos.command,
-						currentRoundSettings.chaos.recoveryWaitTime,
-					)
-					require.NoError(t, err)
+				for {
+					// an extra round detection must not index past round settings
+					if TotalRoundsPerTestCount >= int64(len(tc.roundSettings)) {
+						L.Warn().
+							Int64("TotalRounds", TotalRoundsPerTestCount).
+							Int("RequiredRounds", len(tc.roundSettings)).
+							Msg("All round settings are already applied, stopping")
+						return rounds
+					}
+					currentRoundSettings := tc.roundSettings[TotalRoundsPerTestCount]
+					applyRoundSettings(t, fc, c, currentRoundSettings)
+					TotalRoundsPerTestCount++
+					if inAnswerRange(big.NewInt(int64(currentRoundSettings.value)), bounds) && !currentRoundSettings.dropsObservations() {
+						break
+					}
+					L.Info().
+						Int("Value", currentRoundSettings.value).
+						Float64("Answer", answerFloat(LatestRoundAnswer)).
+						Bool("LateObservations", currentRoundSettings.dropsObservations()).
+						Msg("Value is out of min/max range or observed late, expecting answer to stay the same")
+					requireAnswerHeld(t, o2, LatestRoundAnswer, bounds, tc.roundCheckInterval)
+					if len(rounds)+held == len(tc.roundSettings) {
+						break
+					}
+					held++
 				}
-				require.NoError(t, err)
-				TotalRoundsPerTestCount++
 			}
-			if len(rounds) == len(tc.roundSettings) {
+			if len(rounds)+held == len(tc.roundSettings) {
 				L.Info().
 					Int64("LatestRound", LatestRound).
+					Int("Rounds", len(rounds)).
+					Int("HeldRounds", held).
 					Int("RequiredRounds", len(tc.roundSettings)).
 					Int64("TotalRounds", TotalRoundsPerTestCount).
 					Msg("All rounds are complete")
+				requireTransmissionsBounded(t, o2, fromBlock, startRound, rounds, tc.maxTransmissionsPerRound)
+				return rounds
+			}
+		}
+	}
+}
+
+// assertCadenceChanged checks rounds reported once a new config is live change cadence in the expected direction
+func assertCadenceChanged(t *testing.T, check ocr2.CadenceCheck, before, after []roundData) {
+	t.Helper()
+	old, cur, err := check.Check(before, after)
+	L.Info().
+		Dur("Before", old).
+		Dur("After", cur).
+		Str("Expected", string(check.Expect)).
+		Msg("Round cadence after config change")
+	require.NoError(t, err)
+}
+
+// checkFeedsReport checks every deployed feed has reported a round within its own bounds,
+// answers are decoded with decimals of their feed, feeds may differ from the primary one
+func checkFeedsReport(t *testing.T, c *ethclient.Client, feeds []*ocr2.DeployedFeed, specs []ocr2.FeedSpec) {
+	t.Helper()
+	bounds := make(map[string]*ocr2.OCRv2OffChainOptions, len(specs))
+	for _, s := range specs {
+		bounds[s.Name] = s.Options
+	}
+	for _, feed := range feeds {
+		o2, err := ocr2aggregator.NewOCR2Aggregator(common.HexToAddress(feed.Address), c)
+		require.NoError(t, err)
+		rd := latestRoundData(t, o2)
+		require.Positive(t, rd.RoundId.Sign(), "feed %s has no rounds", feed.Name)
+		require.True(t, inAnswerRange(rd.Answer, bounds[feed.Name]), "feed %s answer %s is out of min/max range", feed.Name, rd.Answer)
+		L.Info().
+			Str("Feed", feed.Name).
+			Int64("RoundID", rd.RoundId.Int64()).
+			Str("Answer", ocr2.FormatAnswer(rd.Answer, feed.Decimals)).
+			Uint8("Decimals", feed.Decimals).
+			Msg("Feed is reporting")
+	}
+}
+
+// watchAnswers returns a channel signalled on every AnswerUpdated event if watch is set, the channel is nil and never fires otherwise,
+// stop ends the watch
+func watchAnswers(o2 *ocr2aggregator.OCR2Aggregator, watch bool) (<-chan struct{}, func()) {
+	if !watch {
+		return nil, func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	wake := make(chan struct{}, 1)
+	go func() {
+		err := ocr2.WatchAnswerUpdates(ctx, o2, func(round ocr2.RoundEvent) {
+			if round.Kind != ocr2.RoundEventAnswerUpdated || round.Removed {
 				return
 			}
+			L.Debug().Uint32("RoundID", round.RoundID).Uint64("Block", round.BlockNumber).Msg("AnswerUpdated event received")
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		})
+		if err != nil {
+			L.Warn().Err(err).Msg("Answer events watch stopped, rounds are checked by interval only")
+		}
+	}()
+	return wake, cancel
+}
+
+// requireTransmissionsBounded checks every report transmitted since fromBlock was transmitted at most maxTransmissions times and
+// every round reported after startRound has a transmission, transmit transactions are counted by report epoch and round so
+// reverted duplicates are counted as well, they waste gas and are not caught by liveness checks. Gas used by transmissions is added to TransmissionGas
+func requireTransmissionsBounded(t *testing.T, o2 *ocr2aggregator.OCR2Aggregator, fromBlock, startRound uint64, rounds []roundData, maxTransmissions int) {
+	t.Helper()
+	if maxTransmissions <= 0 {
+		maxTransmissions = ocr2.DefaultMaxTransmissionsPerRound
+	}
+	// out of range values repeat the latest round, every round is counted once
+	seen := make(map[uint64]bool)
+	for _, rd := range rounds {
+		if id := rd.RoundId.Uint64(); id > startRound {
+			seen[id] = true
+		}
+	}
+	toBlock, err := HeadReader.BlockNumber(context.Background())
+	require.NoError(t, err)
+	counts, err := ocr2.CountRoundTransmissions(context.Background(), TransmissionsReader, o2.Address(), fromBlock, toBlock)
+	require.NoError(t, err)
+	TransmissionGas.Merge(ocr2.TransmissionGas(counts))
+	for _, c := range counts {
+		L.Info().
+			Uint32("Epoch", c.Epoch).
+			Uint8("Round", c.Round).
+			Int("Transmissions", c.Transmissions).
+			Int("Reverted", c.Reverted).
+			Uint64("FromBlock", c.FromBlock).
+			Uint64("ToBlock", c.ToBlock).
+			Any("GasUsed", c.GasUsed).
+			Msg("Report transmissions")
+		require.NoError(t, c.Check(maxTransmissions))
+	}
+	// every report passing the check has a successful transmission
+	require.GreaterOrEqual(t, len(counts), len(seen), "every reported round must be transmitted")
+	exportQuery(t, "rounds", counts)
+}
+
+// requireGasWithinBudget checks gas used by transmissions of the whole run against the budget, nil budget only logs gas used
+func requireGasWithinBudget(t *testing.T, budget *ocr2.GasBudget, stats ocr2.GasStats) {
+	t.Helper()
+	L.Info().
+		Int("Transmissions", stats.Transmissions).
+		Uint64("Average", stats.Average()).
+		Uint64("Max", stats.Max).
+		Msg("Transmission gas used")
+	exportQuery(t, "gas", stats)
+	require.NoError(t, budget.Check(stats))
+}
+
+// verifyProfile starts EA profile and checks on-chain answer tracks EA value within tolerance for the profile duration
+func verifyProfile(t *testing.T, fc *resty.Client, o2 *ocr2aggregator.OCR2Aggregator, tc testcase) {
+	p := tc.profile
+	err := ocr2.SetProfile(fc, p.ea)
+	require.NoError(t, err, "could not set ea fake profile")
+	ticker := time.NewTicker(tc.roundCheckInterval)
+	defer ticker.Stop()
+	deadline := time.After(p.ea.Duration)
+	var total, tracked int
+	for {
+		select {
+		case <-deadline:
+			require.Positive(t, total, "no samples were collected")
+			ratio := float64(tracked) / float64(total)
+			L.Info().
+				Int("Samples", total).
+				Int("Tracked", tracked).
+				Float64("Ratio", ratio).
+				Msg("EA profile is complete")
+			require.GreaterOrEqual(t, ratio, p.minTrackingRatio, "feed doesn't track EA profile within %.2f%%", p.tolerance*100)
+			return
+		case <-ticker.C:
+			v, err := ocr2.FakeValue(fc)
+			require.NoError(t, err)
+			rd := latestRoundData(t, o2)
+			diff := new(big.Float).Quo(
+				new(big.Float).SetInt(new(big.Int).Abs(new(big.Int).Sub(rd.Answer, big.NewInt(v)))),
+				new(big.Float).SetInt64(max(v, 1)),
+			)
+			d, _ := diff.Float64()
+			total++
+			if d <= p.tolerance {
+				tracked++
+			}
+			L.Info().
+				Int64("EAValue", v).
+				Float64("Answer", answerFloat(rd.Answer)).
+				Float64("Diff", d).
+				Msg("EA profile sample")
+		}
+	}
+}
+
+// assertDigestChanged checks that config digest changed if config was changed and stayed the same otherwise,
+// nodes ignore configs that don't produce a new digest
+func assertDigestChanged(t *testing.T, before, after types.ConfigDigest, shouldChange bool) {
+	t.Helper()
+	L.Info().
+		Str("Before", before.Hex()).
+		Str("After", after.Hex()).
+		Bool("ShouldChange", shouldChange).
+		Msg("Checking config digest")
+	if shouldChange {
+		require.NotEqual(t, before, after, "config digest must change after config update")
+		return
+	}
+	require.Equal(t, before, after, "config digest must not change")
+}
+
+// assertNodesAgreeOnConfig checks every node switched to the expected on-chain config of the aggregator, config digests are read from node logs.
+// A node on a stale config doesn't stop the feed while the others reach quorum, so round checks don't catch it
+func assertNodesAgreeOnConfig(ctx context.Context, t *testing.T, nodes []*clnode.Output, aggregator common.Address, expected types.ConfigDigest) {
+	t.Helper()
+	deadline := time.Now().Add(configPropagationTimeout)
+	for {
+		digests := make([]ocr2.NodeDigest, 0, len(nodes))
+		for _, n := range nodes {
+			logs, err := de.ContainerLogs(ctx, n.Node.ContainerName)
+			require.NoError(t, err)
+			d, found := ocr2.LastConfigDigest(logs, aggregator)
+			digests = append(digests, ocr2.NodeDigest{Node: n.Node.ContainerName, Digest: d, Found: found})
+		}
+		divergent := ocr2.DivergentNodes(digests, expected)
+		if len(divergent) == 0 {
+			L.Info().Str("Digest", expected.Hex()).Int("Nodes", len(nodes)).Msg("All nodes run the on-chain config")
+			return
 		}
+		if time.Now().After(deadline) {
+			require.Empty(t, divergent, "nodes are not on the on-chain config digest %s after %s", expected.Hex(), configPropagationTimeout)
+		}
+		L.Info().Any("Divergent", divergent).Msg("Waiting for nodes to switch to the on-chain config")
+		time.Sleep(5 * time.Second)
 	}
 }
 
+// checkEpochsAdvance checks that the protocol made progress since startEpoch, it catches stalls
+// that round checks miss when the answer is stable and only heartbeat reports are transmitted
+func checkEpochsAdvance(t *testing.T, o2 *ocr2aggregator.OCR2Aggregator, startEpoch uint32) {
+	t.Helper()
+	epoch, err := ocr2.LatestEpoch(context.Background(), o2)
+	require.NoError(t, err)
+	L.Info().
+		Uint32("StartEpoch", startEpoch).
+		Uint32("Epoch", epoch).
+		Msg("Checking OCR2 epoch progression")
+	require.Greater(t, epoch, startEpoch, "OCR2 epoch didn't advance, protocol is stalled")
+}
+
 // checkResourceConsumption checks if resource consumption during tests is acceptable
 func checkResourceConsumption(t *testing.T, in *de.Cfg, start, end time.Time, maxCPUTotalPercentage float64, maxMem int) {
 	pc := f.NewPrometheusQueryClient(f.LocalPrometheusBaseURL)
 	cpuResp, err := pc.Query("sum(rate(container_cpu_usage_seconds_total{name=~\".*don.*\"}[5m])) by (name) *100", end)
 	require.NoError(t, err)
+	exportQuery(t, "cpu", cpuResp)
 	cpu := f.ToLabelsMap(cpuResp)
 	for i := 0; i < in.NodeSets[0].Nodes; i++ {
 		nodeLabel := fmt.Sprintf("name:don-node%d", i)
@@ -174,6 +703,7 @@ func checkResourceConsumption(t *testing.T, in *de.Cfg, start, end time.Time, ma
 	}
 	memoryResp, err := pc.Query("sum(container_memory_rss{name=~\".*don.*\"}) by (name)", end)
 	require.NoError(t, err)
+	exportQuery(t, "memory", memoryResp)
 	mem := f.ToLabelsMap(memoryResp)
 	for i := 0; i < in.NodeSets[0].Nodes; i++ {
 		nodeLabel := fmt.Sprintf("name:don-node%d", i)
@@ -183,3 +713,60 @@ func checkResourceConsumption(t *testing.T, in *de.Cfg, start, end time.Time, ma
 		require.LessOrEqual(t, nodeMem, maxMem)
 	}
 }
+
+// fakeEARequests returns how many EA requests fake served successfully so far, it's read from fake /metrics,
+// requests nodes gave up on are counted with 499 status and are not served
+func fakeEARequests(t *testing.T, fc *resty.Client) float64 {
+	n, err := ocr2.FakeRequests(fc, "/ea", "200")
+	require.NoError(t, err)
+	return n
+}
+
+// checkFakeServed checks nodes kept calling the data source during the test case, a feed without EA requests reports stale answers
+func checkFakeServed(t *testing.T, fc *resty.Client, before float64) {
+	served := fakeEARequests(t, fc) - before
+	L.Info().Float64("Requests", served).Msg("EA requests served by fake")
+	require.Positive(t, served, "fake served no EA requests during the test case")
+}
+
+// checkNodesTransmit checks that every worker node sent at least one successful transaction during the test window,
+// a silent node indicates a stuck oracle even if the feed is still reporting, workers are node indexes in the first node set
+func checkNodesTransmit(t *testing.T, workers []int, start, end time.Time) {
+	pc := f.NewPrometheusQueryClient(f.LocalPrometheusBaseURL)
+	window := int64(end.Sub(start).Seconds()) + 1
+	txResp, err := pc.Query(fmt.Sprintf("sum(increase(tx_manager_num_successful_transactions[%ds])) by (instance)", window), end)
+	require.NoError(t, err)
+	exportQuery(t, "transmissions", txResp)
+	txs := make(map[int]float64)
+	for label, values := range f.ToLabelsMap(txResp) {
+		m := nodeInstanceRe.FindStringSubmatch(label)
+		if len(m) < 2 || len(values) == 0 {
+			continue
+		}
+		idx, err := strconv.Atoi(m[1])
+		require.NoError(t, err)
+		nodeTxs, err := strconv.ParseFloat(values[0].(string), 64)
+		require.NoError(t, err)
+		txs[idx] += nodeTxs
+	}
+	silent := make([]int, 0)
+	// bootstrap nodes don't transmit, they're not among workers
+	for _, i := range workers {
+		L.Info().Int("Node", i).Float64("Transmissions", txs[i]).Msg("Successful transactions")
+		if txs[i] == 0 {
+			silent = append(silent, i)
+		}
+	}
+	require.Empty(t, silent, "nodes did not transmit during the test: %v", silent)
+}
+
+// exportQuery saves raw Prometheus query result under CTF logs dir of the test in the artifacts directory if OCR2_EXPORT_METRICS is set
+func exportQuery(t *testing.T, name string, resp any) {
+	t.Helper()
+	if !ocr2.ExportMetricsEnabled() {
+		return
+	}
+	path, err := ocr2.WriteJSONArtifact(products.ArtifactPath(fmt.Sprintf("%s-%s/metrics", f.DefaultCTFLogsDir, t.Name())), name, resp)
+	require.NoError(t, err)
+	L.Info().Str("Path", path).Msg("Exported Prometheus query result")
+}